@@ -0,0 +1,16 @@
+#!/bin/bash
+
+if [[ $# -gt 2 ]]; then
+  echo "usage: `basename "$0"` [count] [username]"
+  echo "(the default count is 50; with a username, only the actions that targeted them are listed)"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+admin_curl --get "http://localhost:$LOCALHOST_PORT/adminActions" \
+  --data-urlencode "count=$1" \
+  --data-urlencode "username=$2"
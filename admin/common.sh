@@ -10,6 +10,17 @@ if [[ -z $LOCALHOST_PORT ]]; then
   LOCALHOST_PORT=8081
 fi
 
+# The name of the person who is running the script is recorded in the audit log
+# (it can be overridden with the "ADMIN_NAME" environment variable)
+if [[ -z $ADMIN_NAME ]]; then
+  ADMIN_NAME="$USER"
+fi
+
+function admin_curl {
+  # Performs a request with any extra curl arguments
+  curl --silent --header "X-Admin: $ADMIN_NAME" "$@"
+}
+
 function admin_command {
   if [[ $# -ne 1 ]]; then
     echo "usage: admin_command [command]"
@@ -17,7 +28,7 @@ function admin_command {
   fi
 
   # Performs a GET request
-  curl --silent "http://localhost:$LOCALHOST_PORT/$1"
+  admin_curl "http://localhost:$LOCALHOST_PORT/$1"
 }
 
 function admin_command_post {
@@ -27,5 +38,5 @@ function admin_command_post {
   fi
 
   # Performs a POST request (since we include the "data" flag)
-  curl --silent "http://localhost:$LOCALHOST_PORT/$1" --data "$2"
+  admin_curl "http://localhost:$LOCALHOST_PORT/$1" --data "$2"
 }
@@ -1,7 +1,9 @@
 #!/bin/bash
 
-if [[ $# -ne 1 ]]; then
-  echo "usage: `basename "$0"` [username]"
+if [[ $# -lt 2 || $# -gt 5 ]]; then
+  echo "usage: `basename "$0"` [username] [moderator] [duration] [reason] [scope]"
+  echo "(the duration is e.g. \"30m\", \"12h\", \"7d\", or \"permanent\", which is the default)"
+  echo "(the scope is \"account\", \"ip\", or \"both\", which is the default)"
   exit 1
 fi
 
@@ -13,4 +15,11 @@ DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
 COMMAND=$(basename "$0" | cut -f 1 -d '.')
 
 source "$DIR/common.sh"
-admin_command_post "$COMMAND" "username=$1"
+
+# The reason contains spaces, so it has to be URL-encoded
+admin_curl "http://localhost:$LOCALHOST_PORT/$COMMAND" \
+  --data-urlencode "username=$1" \
+  --data-urlencode "moderator=$2" \
+  --data-urlencode "duration=$3" \
+  --data-urlencode "reason=$4" \
+  --data-urlencode "scope=$5"
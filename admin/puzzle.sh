@@ -0,0 +1,20 @@
+#!/bin/bash
+
+if [[ $# -lt 3 || $# -gt 4 ]]; then
+  echo "usage: `basename "$0"` [game ID] [turn] [answers] [description]"
+  echo "(answers are separated by semicolons, e.g. \"clues Bob red; plays slot 2\")"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+
+# The answers and the description contain spaces, so they have to be URL-encoded
+admin_curl "http://localhost:$LOCALHOST_PORT/puzzle" \
+  --data-urlencode "gameID=$1" \
+  --data-urlencode "turn=$2" \
+  --data-urlencode "answers=$3" \
+  --data-urlencode "description=$4"
@@ -0,0 +1,16 @@
+#!/bin/bash
+
+if [[ $# -ne 2 ]]; then
+  echo "usage: `basename "$0"` [report ID] [moderator]"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+
+admin_curl "http://localhost:$LOCALHOST_PORT/reportClaim" \
+  --data-urlencode "id=$1" \
+  --data-urlencode "moderator=$2"
@@ -0,0 +1,24 @@
+#!/bin/bash
+
+if [[ $# -lt 3 || $# -gt 5 ]]; then
+  echo "usage: `basename "$0"` [report ID] [moderator] [action] [notes] [warning message or duration]"
+  echo "(the action is \"none\", \"warn\", \"mute\", or \"ban\")"
+  echo "(for a warning, the last argument is the message that is shown to the reported user)"
+  echo "(for a mute or a ban, the last argument is the duration, e.g. \"7d\"; the default is permanent)"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+
+# The notes and the warning message contain spaces, so they have to be URL-encoded
+admin_curl "http://localhost:$LOCALHOST_PORT/reportResolve" \
+  --data-urlencode "id=$1" \
+  --data-urlencode "moderator=$2" \
+  --data-urlencode "action=$3" \
+  --data-urlencode "notes=$4" \
+  --data-urlencode "msg=$5" \
+  --data-urlencode "duration=$5"
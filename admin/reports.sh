@@ -0,0 +1,15 @@
+#!/bin/bash
+
+if [[ $# -gt 1 ]]; then
+  echo "usage: `basename "$0"` [report ID]"
+  echo "(with no report ID, all of the unresolved reports are listed)"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+
+admin_curl --get "http://localhost:$LOCALHOST_PORT/reports" --data-urlencode "id=$1"
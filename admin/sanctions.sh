@@ -0,0 +1,16 @@
+#!/bin/bash
+
+if [[ $# -gt 1 ]]; then
+  echo "usage: `basename "$0"` [username]"
+  echo "(with no username, all of the active bans and mutes are listed)"
+  echo "(with a username, every ban and mute that was ever issued against them is listed)"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+
+admin_curl --get "http://localhost:$LOCALHOST_PORT/sanctions" --data-urlencode "username=$1"
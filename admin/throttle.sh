@@ -0,0 +1,17 @@
+#!/bin/bash
+
+if [[ $# -lt 1 || $# -gt 2 ]]; then
+  echo "usage: `basename "$0"` [username] [reason]"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+source "$DIR/common.sh"
+
+# The reason contains spaces, so it has to be URL-encoded
+admin_curl "http://localhost:$LOCALHOST_PORT/throttle" \
+  --data-urlencode "username=$1" \
+  --data-urlencode "reason=$2"
@@ -0,0 +1,21 @@
+#!/bin/bash
+
+if [[ $# -lt 2 || $# -gt 3 ]]; then
+  echo "usage: `basename "$0"` [username] [moderator] [reason]"
+  exit 1
+fi
+
+# Get the directory of this script
+# https://stackoverflow.com/questions/59895/getting-the-source-directory-of-a-bash-script-from-within
+DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
+
+# Get the name of the script and trim the ".sh"
+COMMAND=$(basename "$0" | cut -f 1 -d '.')
+
+source "$DIR/common.sh"
+
+# The reason contains spaces, so it has to be URL-encoded
+admin_curl "http://localhost:$LOCALHOST_PORT/$COMMAND" \
+  --data-urlencode "username=$1" \
+  --data-urlencode "moderator=$2" \
+  --data-urlencode "reason=$3"
@@ -3,7 +3,13 @@
 // for e.g. in-game replays
 
 import { createStore } from "redux";
-import { initArray, parseIntSafe, setBrowserAddressBarPath } from "../../misc";
+import { addSelf } from "../../chat";
+import {
+  initArray,
+  millisecondsToClockString,
+  parseIntSafe,
+  setBrowserAddressBarPath,
+} from "../../misc";
 import * as sentry from "../../sentry";
 import { getVariant } from "../data/gameData";
 import initialState from "../reducers/initialStates/initialState";
@@ -281,6 +287,61 @@ commands.set("gameActionList", (data: GameActionListData) => {
   }
 });
 
+// Received when joining a replay of a game from the database
+// (or when a game ends and becomes a shared replay)
+interface PlayerAnalysis {
+  name: string;
+  cluesGiven: number;
+  cardsPlayed: number;
+  cardsDiscarded: number;
+  misplays: number;
+  criticalDiscards: number;
+  cardsGotten: number;
+  potentialCluesLost: number;
+  efficiency: number;
+  numTurns: number;
+  turnTimes: number[] | null;
+  timeUsed: number;
+  averageTurnTime: number;
+}
+interface GameAnalysisData {
+  tableID: number;
+  analysis: {
+    cardsGotten: number;
+    potentialCluesLost: number;
+    efficiency: number;
+    hasTimes: boolean;
+    players: PlayerAnalysis[];
+  };
+}
+commands.set("gameAnalysis", (data: GameAnalysisData) => {
+  const { analysis } = data;
+  const room = `table${data.tableID}`;
+
+  addSelf(
+    `Game analysis: ${analysis.cardsGotten} cards gotten, ${
+      analysis.potentialCluesLost
+    } potential clues lost (efficiency: ${formatEfficiency(
+      analysis.efficiency,
+    )})`,
+    room,
+  );
+  for (const player of analysis.players) {
+    let msg = `${player.name}: ${player.cluesGiven} clues given, `;
+    msg += `${player.cardsPlayed} played, ${player.cardsDiscarded} discarded, `;
+    msg += `${player.misplays} misplayed, `;
+    msg += `${player.criticalDiscards} critical cards lost, `;
+    msg += `${player.cardsGotten} cards gotten `;
+    msg += `(efficiency: ${formatEfficiency(player.efficiency)})`;
+    if (analysis.hasTimes) {
+      msg += `, average turn time: ${millisecondsToClockString(
+        player.averageTurnTime,
+      )}`;
+    }
+    addSelf(msg, room);
+  }
+});
+
 interface PauseData {
   active: boolean;
   playerIndex: number;
@@ -293,6 +354,31 @@ commands.set("pause", (data: PauseData) => {
   });
 });
 
+// Received when joining a replay of a game from the database that has annotations from previous
+// shared replays
+interface ReplayAnnotation {
+  turn: number;
+  type: string;
+  username: string;
+  value: string;
+  datetime: string;
+}
+interface ReplayAnnotationsData {
+  tableID: number;
+  annotations: ReplayAnnotation[];
+}
+commands.set("replayAnnotations", (data: ReplayAnnotationsData) => {
+  const room = `table${data.tableID}`;
+
+  addSelf(
+    `This game has ${data.annotations.length} saved annotation(s) from previous shared replays:`,
+    room,
+  );
+  for (const annotation of data.annotations) {
+    addSelf(getReplayAnnotationText(annotation), room);
+  }
+});
+
 interface ReplayEfficiencyModData {
   tableID: number;
   mod: number;
@@ -403,6 +489,39 @@ commands.set("spectators", (data: SpectatorsData) => {
 // Subroutines
 // -----------
 
+// The efficiency is 0 if no potential clues were lost
+function formatEfficiency(efficiency: number) {
+  return efficiency === 0 ? "-" : efficiency.toFixed(2);
+}
+
+function getReplayAnnotationText(annotation: ReplayAnnotation) {
+  // The turns are shown to the user starting at 1
+  const prefix = `[Turn ${annotation.turn + 1}] ${annotation.username}`;
+
+  switch (annotation.type) {
+    case "chat": {
+      // The message was already escaped by the server when it was sent
+      return `${prefix}: ${annotation.value}`;
+    }
+
+    case "arrow": {
+      return `${prefix} highlighted a card`;
+    }
+
+    case "efficiencyMod": {
+      return `${prefix} changed the efficiency modifier to ${annotation.value}`;
+    }
+
+    case "hypothetical": {
+      return `${prefix} explored a hypothetical`;
+    }
+
+    default: {
+      return `${prefix} made an annotation of type "${annotation.type}"`;
+    }
+  }
+}
+
 function setURL(data: InitData) {
   let path;
   if (data.sharedReplay) {
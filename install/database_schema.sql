@@ -41,7 +41,9 @@ CREATE TABLE users (
     old_password_hash    TEXT         NULL, /* A SHA-256 hash */
     last_ip              TEXT         NOT NULL,
     datetime_created     TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
-    datetime_last_login  TIMESTAMPTZ  NOT NULL  DEFAULT NOW()
+    datetime_last_login  TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
+    /* Bot accounts can sit at more than one table and their games do not count towards stats */
+    bot                  BOOLEAN      NOT NULL  DEFAULT FALSE
 );
 
 /* Any default settings must also be applied to the "userSettings.go" file */
@@ -94,6 +96,10 @@ CREATE TABLE user_stats (
     best_score5_mod  SMALLINT  NOT NULL  DEFAULT 0,
     best_score6      SMALLINT  NOT NULL  DEFAULT 0,
     best_score6_mod  SMALLINT  NOT NULL  DEFAULT 0,
+    best_score7      SMALLINT  NOT NULL  DEFAULT 0,
+    best_score7_mod  SMALLINT  NOT NULL  DEFAULT 0,
+    best_score8      SMALLINT  NOT NULL  DEFAULT 0,
+    best_score8_mod  SMALLINT  NOT NULL  DEFAULT 0,
     average_score    FLOAT     NOT NULL  DEFAULT 0,
     num_strikeouts   INTEGER   NOT NULL  DEFAULT 0,
     FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
@@ -118,6 +124,25 @@ CREATE TABLE user_reverse_friends (
     PRIMARY KEY (user_id, friend_id)
 );
 
+/* Blocked users cannot send lobby messages or private messages to the user who blocked them */
+DROP TABLE IF EXISTS user_blocks CASCADE;
+CREATE TABLE user_blocks (
+    user_id     INTEGER  NOT NULL,
+    blocked_id  INTEGER  NOT NULL,
+    FOREIGN KEY (user_id)    REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, blocked_id)
+);
+
+DROP TABLE IF EXISTS user_reverse_blocks CASCADE;
+CREATE TABLE user_reverse_blocks (
+    user_id     INTEGER  NOT NULL,
+    blocker_id  INTEGER  NOT NULL,
+    FOREIGN KEY (user_id)    REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (blocker_id) REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, blocker_id)
+);
+
 DROP TABLE IF EXISTS games CASCADE;
 CREATE TABLE games (
     id                      SERIAL       PRIMARY KEY,
@@ -141,11 +166,27 @@ CREATE TABLE games (
     one_less_card           BOOLEAN      NOT NULL,
     all_or_nothing          BOOLEAN      NOT NULL,
     detrimental_characters  BOOLEAN      NOT NULL,
+    /* For the custom rules, 0 means that the normal rules were used */
+    starting_clue_tokens    SMALLINT     NOT NULL  DEFAULT 0,
+    max_clue_tokens         SMALLINT     NOT NULL  DEFAULT 0,
+    strike_limit            SMALLINT     NOT NULL  DEFAULT 0,
+    hand_size               SMALLINT     NOT NULL  DEFAULT 0,
+    /* See the "TimeControl" constants in "constants.go" */
+    time_control            TEXT         NOT NULL  DEFAULT 'fischer',
+    byo_yomi_periods        SMALLINT     NOT NULL  DEFAULT 0,
+    team_clock              BOOLEAN      NOT NULL  DEFAULT FALSE,
+    correspondence          BOOLEAN      NOT NULL  DEFAULT FALSE,
+    correspondence_deadline INTEGER      NOT NULL  DEFAULT 0, /* in hours */
     seed                    TEXT         NOT NULL, /* e.g. "p2v0s1" */
     score                   SMALLINT     NOT NULL,
     num_turns               SMALLINT     NOT NULL,
     /* See the "endCondition" values in "constants.go" */
     end_condition           SMALLINT     NOT NULL,
+    /**
+     * The number of moves that were taken back with the "undo" vote
+     * Games with undos do not count towards best scores
+     */
+    num_undos               SMALLINT     NOT NULL  DEFAULT 0,
     datetime_started        TIMESTAMPTZ  NOT NULL,
     datetime_finished       TIMESTAMPTZ  NOT NULL
 );
@@ -158,11 +199,17 @@ CREATE TABLE game_participants (
     id                    SERIAL    PRIMARY KEY,
     game_id               INTEGER   NOT NULL,
     user_id               INTEGER   NOT NULL,
+    /**
+     * If someone took over the seat in the middle of the game, this is the user that started the
+     * game in the seat (and "user_id" is the user that finished the game)
+     */
+    original_user_id      INTEGER   NULL      DEFAULT NULL,
     seat                  SMALLINT  NOT NULL, /* Needed for the "GetNotes()" function */
     character_assignment  SMALLINT  NOT NULL,
     character_metadata    SMALLINT  NOT NULL,
     FOREIGN KEY (game_id) REFERENCES games (id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (original_user_id) REFERENCES users (id) ON DELETE SET NULL,
     CONSTRAINT game_participants_unique UNIQUE (game_id, user_id)
 );
 
@@ -211,10 +258,94 @@ CREATE TABLE game_tags (
     CONSTRAINT game_tags_unique UNIQUE (game_id, tag)
 );
 
+/*
+ * The statistics that are generated at the end of every game
+ * They are stored as JSON (see the "GameAnalysis" struct in "game_analysis.go")
+ */
+DROP TABLE IF EXISTS game_analyses CASCADE;
+CREATE TABLE game_analyses (
+    game_id   INTEGER  PRIMARY KEY,
+    analysis  TEXT     NOT NULL,
+    FOREIGN KEY (game_id) REFERENCES games (id) ON DELETE CASCADE
+);
+
+/*
+ * Hypotheticals from shared replays that the leader chose to save
+ * The branches are stored as JSON (see the "HypoBranch" struct in "hypothetical.go")
+ */
+DROP TABLE IF EXISTS game_hypotheticals CASCADE;
+CREATE TABLE game_hypotheticals (
+    id              SERIAL       PRIMARY KEY,
+    game_id         INTEGER      NOT NULL,
+    user_id         INTEGER      NOT NULL, /* The leader who saved it */
+    name            TEXT         NOT NULL,
+    branches        TEXT         NOT NULL,
+    datetime_saved  TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
+    FOREIGN KEY (game_id) REFERENCES games (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT game_hypotheticals_unique UNIQUE (game_id, name)
+);
+
+/*
+ * Everything that happens in a shared replay of a game from the database
+ * (e.g. chat, arrows, hypotheticals) so that it can be seen again when the game is reviewed later
+ * The turn is the shared replay segment that the annotation was made on
+ */
+DROP TABLE IF EXISTS replay_annotations CASCADE;
+CREATE TABLE replay_annotations (
+    id                SERIAL       PRIMARY KEY,
+    game_id           INTEGER      NOT NULL,
+    turn              INTEGER      NOT NULL,
+    user_id           INTEGER      NOT NULL,
+    type              TEXT         NOT NULL, /* e.g. "chat", "arrow", "efficiencyMod" */
+    value             TEXT         NOT NULL,
+    datetime_created  TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
+    FOREIGN KEY (game_id) REFERENCES games (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX replay_annotations_index_game_id ON replay_annotations (game_id);
+
+/*
+ * Curated puzzles, where a player has to find the right move at a specific point in a game
+ * The turn is the index of the action that the player has to make (like in "game_actions")
+ * The accepted answers are stored as JSON (a list of actions)
+ */
+DROP TABLE IF EXISTS puzzles CASCADE;
+CREATE TABLE puzzles (
+    id                SERIAL       PRIMARY KEY,
+    game_id           INTEGER      NOT NULL,
+    turn              SMALLINT     NOT NULL,
+    player_index      SMALLINT     NOT NULL, /* The player whose turn it is */
+    answers           TEXT         NOT NULL,
+    description       TEXT         NOT NULL  DEFAULT '',
+    datetime_created  TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
+    FOREIGN KEY (game_id) REFERENCES games (id) ON DELETE CASCADE,
+    CONSTRAINT puzzles_unique UNIQUE (game_id, turn)
+);
+
+DROP TABLE IF EXISTS user_puzzles CASCADE;
+CREATE TABLE user_puzzles (
+    user_id          INTEGER      NOT NULL,
+    puzzle_id        INTEGER      NOT NULL,
+    num_attempts     INTEGER      NOT NULL  DEFAULT 0,
+    solved           BOOLEAN      NOT NULL  DEFAULT FALSE,
+    datetime_solved  TIMESTAMPTZ  NULL      DEFAULT NULL, /* The first time that it was solved */
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (puzzle_id) REFERENCES puzzles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, puzzle_id)
+);
+
 DROP TABLE IF EXISTS seeds CASCADE;
 CREATE TABLE seeds (
-    seed       TEXT     NOT NULL  PRIMARY KEY,
-    num_games  INTEGER  NOT NULL
+    seed                   TEXT      NOT NULL  PRIMARY KEY,
+    num_games              INTEGER   NOT NULL,
+    /*
+     * The best score that is achievable on this seed with perfect information (from the solver)
+     * -1 if it has not been calculated yet
+     */
+    max_achievable_score   SMALLINT  NOT NULL  DEFAULT -1,
+    /* False if the solver ran out of time before it could prove that no better score exists */
+    max_achievable_proven  BOOLEAN   NOT NULL  DEFAULT FALSE
 );
 
 DROP TABLE IF EXISTS variant_stats CASCADE;
@@ -228,11 +359,25 @@ CREATE TABLE variant_stats (
     best_score4     SMALLINT  NOT NULL  DEFAULT 0,
     best_score5     SMALLINT  NOT NULL  DEFAULT 0,
     best_score6     SMALLINT  NOT NULL  DEFAULT 0,
+    best_score7     SMALLINT  NOT NULL  DEFAULT 0,
+    best_score8     SMALLINT  NOT NULL  DEFAULT 0,
     num_max_scores  INTEGER   NOT NULL  DEFAULT 0,
     average_score   FLOAT     NOT NULL  DEFAULT 0,
     num_strikeouts  INTEGER   NOT NULL  DEFAULT 0
 );
 
+/*
+ * Ongoing correspondence games are stored here so that they survive server restarts
+ * (with the same JSON that is used to save ongoing tables to disk during a graceful restart)
+ * Rows are deleted once the game ends and is written to the "games" table
+ */
+DROP TABLE IF EXISTS correspondence_tables CASCADE;
+CREATE TABLE correspondence_tables (
+    table_id          BIGINT       NOT NULL  PRIMARY KEY,
+    table_json        TEXT         NOT NULL,
+    datetime_updated  TIMESTAMPTZ  NOT NULL  DEFAULT NOW()
+);
+
 DROP TABLE IF EXISTS chat_log CASCADE;
 CREATE TABLE chat_log (
     id             SERIAL       PRIMARY KEY,
@@ -263,28 +408,105 @@ CREATE INDEX chat_log_pm_index_user_id       ON chat_log_pm (user_id);
 CREATE INDEX chat_log_pm_index_recipient_id  ON chat_log_pm (recipient_id);
 CREATE INDEX chat_log_pm_index_datetime_sent ON chat_log_pm (datetime_sent);
 
-DROP TABLE IF EXISTS banned_ips CASCADE;
-CREATE TABLE banned_ips (
-    id               SERIAL       PRIMARY KEY,
-    ip               TEXT         NOT NULL,
-    /* An entry for a banned IP can optionally be associated with a user */
-    user_id          INTEGER      NULL      DEFAULT NULL,
-    reason           TEXT         NULL      DEFAULT NULL,
-    datetime_banned  TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
-    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+DROP TABLE IF EXISTS sanctions CASCADE;
+CREATE TABLE sanctions (
+    id                SERIAL       PRIMARY KEY,
+    /* See the "SanctionType" constants in "sanctions.go" (e.g. "ban" or "mute") */
+    type              TEXT         NOT NULL,
+    /* A sanction can target an account, an IP, or both */
+    user_id           INTEGER      NULL      DEFAULT NULL,
+    ip                TEXT         NULL      DEFAULT NULL,
+    reason            TEXT         NOT NULL  DEFAULT '',
+    /* The moderator who issued the sanction (NULL if it was issued automatically by the server) */
+    issued_by         INTEGER      NULL      DEFAULT NULL,
+    datetime_issued   TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
+    /* NULL if the sanction is permanent */
+    datetime_expires  TIMESTAMPTZ  NULL      DEFAULT NULL,
+    /**
+     * Sanctions are never deleted so that there is a history of them (e.g. for appeals)
+     * Instead, they are marked as being lifted
+     */
+    lifted_by         INTEGER      NULL      DEFAULT NULL,
+    lift_reason       TEXT         NOT NULL  DEFAULT '',
+    datetime_lifted   TIMESTAMPTZ  NULL      DEFAULT NULL,
+    CHECK (user_id IS NOT NULL OR ip IS NOT NULL),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (issued_by) REFERENCES users (id) ON DELETE SET NULL,
+    FOREIGN KEY (lifted_by) REFERENCES users (id) ON DELETE SET NULL
 );
+CREATE INDEX sanctions_index_user_id ON sanctions (user_id);
+CREATE INDEX sanctions_index_ip      ON sanctions (ip);
 
-DROP TABLE IF EXISTS muted_ips CASCADE;
-CREATE TABLE muted_ips (
+/* Players can report other players for abusive chat or griefing (see "report.go") */
+DROP TABLE IF EXISTS reports CASCADE;
+CREATE TABLE reports (
+    id                 SERIAL       PRIMARY KEY,
+    reporter_id        INTEGER      NOT NULL,
+    reported_id        INTEGER      NOT NULL,
+    /* The chat room that the report was made from (e.g. "lobby" or "table123") */
+    room               TEXT         NOT NULL,
+    /* NULL if the report was made from the lobby */
+    table_id           BIGINT       NULL      DEFAULT NULL,
+    reason             TEXT         NOT NULL,
+    /* The most recent chat messages in the room at the time of the report (one per line) */
+    chat               TEXT         NOT NULL  DEFAULT '',
+    datetime_created   TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
+    /* The moderator who is looking into the report */
+    claimed_by         INTEGER      NULL      DEFAULT NULL,
+    datetime_claimed   TIMESTAMPTZ  NULL      DEFAULT NULL,
+    resolved_by        INTEGER      NULL      DEFAULT NULL,
+    /* See the "ReportAction" constants in "report.go" */
+    action             TEXT         NOT NULL  DEFAULT '',
+    notes              TEXT         NOT NULL  DEFAULT '',
+    datetime_resolved  TIMESTAMPTZ  NULL      DEFAULT NULL,
+    FOREIGN KEY (reporter_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (reported_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (claimed_by) REFERENCES users (id) ON DELETE SET NULL,
+    FOREIGN KEY (resolved_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX reports_index_reported_id        ON reports (reported_id);
+CREATE INDEX reports_index_datetime_resolved  ON reports (datetime_resolved);
+
+/*
+ * Every administrative action that is performed on the localhost admin server is recorded here
+ * (see "http_localhost_audit_log.go")
+ * This table is append-only, so that it can be trusted when auditing what happened in an incident
+ */
+DROP TABLE IF EXISTS admin_actions CASCADE;
+CREATE TABLE admin_actions (
     id               SERIAL       PRIMARY KEY,
-    ip               TEXT         NOT NULL,
-    /* An entry for a muted IP can optionally be associated with a user */
-    user_id          INTEGER      NULL      DEFAULT NULL,
-    reason           TEXT         NULL      DEFAULT NULL,
-    datetime_banned  TIMESTAMPTZ  NOT NULL  DEFAULT NOW(),
-    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+    /* The name of the person who performed the action (as reported by the admin script) */
+    admin            TEXT         NOT NULL,
+    /* The path of the localhost endpoint without the slash (e.g. "ban") */
+    action           TEXT         NOT NULL,
+    /*
+     * There are no foreign keys on the targets,
+     * since the record must stay the same even if the user is deleted
+     */
+    target_user_id   INTEGER      NULL      DEFAULT NULL,
+    target_table_id  BIGINT       NULL      DEFAULT NULL,
+    /* A JSON object of all of the parameters that were sent with the request */
+    parameters       TEXT         NOT NULL  DEFAULT '{}',
+    /*
+     * The HTTP status code of the response
+     * (NULL for the actions that might end the server,
+     * since they are recorded before they are performed)
+     */
+    status_code      INTEGER      NULL      DEFAULT NULL,
+    datetime         TIMESTAMPTZ  NOT NULL  DEFAULT NOW()
 );
+CREATE INDEX admin_actions_index_target_user_id ON admin_actions (target_user_id);
+
+CREATE OR REPLACE FUNCTION admin_actions_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'The "admin_actions" table is append-only.';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER admin_actions_append_only
+    BEFORE UPDATE OR DELETE ON admin_actions
+    FOR EACH STATEMENT EXECUTE PROCEDURE admin_actions_append_only();
 
+/* Throttled users have stricter rate limits (see "throttle.go") */
 DROP TABLE IF EXISTS throttled_ips CASCADE;
 CREATE TABLE throttled_ips (
     id                  SERIAL       PRIMARY KEY,
@@ -30,9 +30,11 @@ UPDATE users SET last_ip='0.0.0.0';
 DELETE FROM user_settings;
 DELETE FROM user_friends;
 DELETE FROM user_reverse_friends;
+DELETE FROM user_blocks;
+DELETE FROM user_reverse_blocks;
 DELETE FROM chat_log;
 DELETE FROM chat_log_pm;
-DELETE FROM banned_ips;
-DELETE FROM muted_ips;
+DELETE FROM sanctions;
+DELETE FROM reports;
 DELETE FROM throttled_ips;
 EOF
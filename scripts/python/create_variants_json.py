@@ -15,6 +15,18 @@ import sys
 
 # Constants
 SUIT_REVERSED_SUFFIX = " Reversed"
+START_CARD_RANK = 7
+
+# In "Up or Down" variants, there is only one of each card, except for the 2s, 3s, and 4s
+# (the keys are strings since they are JSON object keys)
+UP_OR_DOWN_CARDS_PER_RANK = {
+    "1": 1,
+    "2": 2,
+    "3": 2,
+    "4": 2,
+    "5": 1,
+    str(START_CARD_RANK): 1,
+}
 
 
 def main():
@@ -765,6 +777,7 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "alternatingClues": True,
             }
         )
     for [suit_name, suit] in suits.items():
@@ -785,6 +798,7 @@ def main():
                     "name": variant_name,
                     "id": get_variant_id(variant_name),
                     "suits": variant_suits[suit_num - 1].copy() + [suit_name],
+                    "alternatingClues": True,
                 }
             )
 
@@ -796,6 +810,7 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "discardsGiveHalfClue": True,
             }
         )
     for [suit_name, suit] in suits.items():
@@ -814,6 +829,7 @@ def main():
                     "name": variant_name,
                     "id": get_variant_id(variant_name),
                     "suits": variant_suits[suit_num - 1].copy() + [suit_name],
+                    "discardsGiveHalfClue": True,
                 }
             )
 
@@ -825,6 +841,7 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "hideClueValues": True,
             }
         )
 
@@ -836,6 +853,8 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "hideClueTypes": True,
+                "hideClueValues": True,
             }
         )
 
@@ -847,6 +866,8 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "hidePlays": True,
+                "noClueForPlaying5": True,
             }
         )
     for [suit_name, suit] in suits.items():
@@ -866,6 +887,8 @@ def main():
                     "name": variant_name,
                     "id": get_variant_id(variant_name),
                     "suits": variant_suits[suit_num - 1].copy() + [suit_name],
+                    "hidePlays": True,
+                    "noClueForPlaying5": True,
                 }
             )
 
@@ -918,6 +941,8 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "stackDirection": "upOrDown",
+                "cardsPerRank": UP_OR_DOWN_CARDS_PER_RANK,
                 "showSuitNames": True,
             }
         )
@@ -938,6 +963,8 @@ def main():
                     "name": variant_name,
                     "id": get_variant_id(variant_name),
                     "suits": variant_suits[suit_num - 1].copy() + [suit_name],
+                    "stackDirection": "upOrDown",
+                    "cardsPerRank": UP_OR_DOWN_CARDS_PER_RANK,
                     "showSuitNames": True,
                 }
             )
@@ -962,6 +989,7 @@ def main():
                 "name": variant_name,
                 "id": get_variant_id(variant_name),
                 "suits": variant_suits[suit_num],
+                "colorCluesTouchRanks": True,
                 "clueRanks": [],
             }
         )
@@ -981,6 +1009,7 @@ def main():
                     "name": variant_name,
                     "id": get_variant_id(variant_name),
                     "suits": variant_suits[suit_num - 1].copy() + [suit_name],
+                    "colorCluesTouchRanks": True,
                     "clueRanks": [],
                 }
             )
@@ -4,55 +4,20 @@
 
 package main
 
-// Used to implement the "Slow-Witted" detrimental character
-type ActionCardIdentity struct {
-	Type        string `json:"type"`
-	PlayerIndex int    `json:"playerIndex"` // Needed so that we can validate who holds the card
-	Order       int    `json:"order"`
-	SuitIndex   int    `json:"suitIndex"`
-	Rank        int    `json:"rank"`
-}
-
-type ActionClue struct {
-	Type   string `json:"type"`
-	Clue   Clue   `json:"clue"`
-	Giver  int    `json:"giver"`
-	List   []int  `json:"list"` // The list of cards that the clue "touches"
-	Target int    `json:"target"`
-	// The client records the turn that each clue is given (for the clue log)
-	Turn int `json:"turn"`
-}
-
-type ActionDiscard struct {
-	Type        string `json:"type"`
-	PlayerIndex int    `json:"playerIndex"`
-	Order       int    `json:"order"` // The ID of the card (based on its order in the deck)
-	SuitIndex   int    `json:"suitIndex"`
-	Rank        int    `json:"rank"`
-	Failed      bool   `json:"failed"`
-}
-
-type ActionDraw struct {
-	Type        string `json:"type"`
-	PlayerIndex int    `json:"playerIndex"`
-	Order       int    `json:"order"` // The ID of the card, based on its ordering in the deck
-	SuitIndex   int    `json:"suitIndex"`
-	Rank        int    `json:"rank"`
-}
-
-type ActionGameOver struct {
-	Type         string `json:"type"`
-	EndCondition int    `json:"endCondition"`
-	PlayerIndex  int    `json:"playerIndex"`
-}
-
-type ActionPlay struct {
-	Type        string `json:"type"`
-	PlayerIndex int    `json:"playerIndex"`
-	Order       int    `json:"order"` // The ID of the card (based on its order in the deck)
-	SuitIndex   int    `json:"suitIndex"`
-	Rank        int    `json:"rank"`
-}
+import (
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// Most actions are events that are emitted by the engine
+type ActionCardIdentity = engine.EventCardIdentity
+type ActionClue = engine.EventClue
+type ActionDiscard = engine.EventDiscard
+type ActionDraw = engine.EventDraw
+type ActionGameOver = engine.EventGameOver
+type ActionPlay = engine.EventPlay
+type ActionStrike = engine.EventStrike
+type ActionStatus = engine.EventStatus
+type ActionTurn = engine.EventTurn
 
 type ActionPlayerTimes struct {
 	Type        string  `json:"type"`
@@ -60,37 +25,4 @@ type ActionPlayerTimes struct {
 	Duration    int64   `json:"duration"`
 }
 
-type ActionStrike struct {
-	Type  string `json:"type"`
-	Num   int    `json:"num"`   // Whether it was the first strike, the second strike, etc.
-	Turn  int    `json:"turn"`  // The turn that the strike happened
-	Order int    `json:"order"` // The order of the card that was played
-}
-
-type ActionStatus struct {
-	Type     string `json:"type"`
-	Clues    int    `json:"clues"`
-	Score    int    `json:"score"`
-	MaxScore int    `json:"maxScore"`
-}
-
-type ActionTurn struct {
-	Type               string `json:"type"`
-	Num                int    `json:"num"`
-	CurrentPlayerIndex int    `json:"currentPlayerIndex"`
-}
-
-type Clue struct {
-	Type  int `json:"type"`
-	Value int `json:"value"`
-}
-
-func NewClue(d *CommandData) Clue {
-	return Clue{
-		// A color clue is action type 2
-		// A rank clue is action type 3
-		// Remap these to 0 and 1, respectively
-		Type:  d.Type - 2,
-		Value: d.Value,
-	}
-}
+type Clue = engine.Clue
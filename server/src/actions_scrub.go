@@ -3,36 +3,42 @@ package main
 // CheckScrub removes some information from the action to prevent players having more knowledge
 // than they should have, if necessary (e.g. when a card is drawn to a player's hand)
 func CheckScrub(t *Table, action interface{}, userID int) interface{} {
+	clueAction, ok := action.(ActionClue)
+	if ok && clueAction.Type == "clue" {
+		scrubClue(t, &clueAction, userID)
+		return clueAction
+	}
+
 	cardIdentityAction, ok := action.(ActionCardIdentity)
 	if ok && cardIdentityAction.Type == "cardIdentity" {
-		cardIdentityAction.Scrub(t, userID)
+		scrubCardIdentity(t, &cardIdentityAction, userID)
 		return cardIdentityAction
 	}
 
 	discardAction, ok := action.(ActionDiscard)
 	if ok && discardAction.Type == "discard" {
-		discardAction.Scrub(t, userID)
+		scrubDiscard(t, &discardAction, userID)
 		return discardAction
 	}
 
 	drawAction, ok := action.(ActionDraw)
 	if ok && drawAction.Type == "draw" {
-		drawAction.Scrub(t, userID)
+		scrubDraw(t, &drawAction, userID)
 		return drawAction
 	}
 
 	playAction, ok := action.(ActionPlay)
 	if ok && playAction.Type == "play" {
-		playAction.Scrub(t, userID)
+		scrubPlay(t, &playAction, userID)
 		return playAction
 	}
 
 	return action
 }
 
-// Scrub removes some information from a draw so that we do not reveal the identity of drawn
-// cards to the players drawing those cards
-func (a *ActionDraw) Scrub(t *Table, userID int) {
+// scrubDraw removes some information from a draw so that we do not reveal the identity of
+// drawn cards to the players drawing those cards
+func scrubDraw(t *Table, a *ActionDraw, userID int) {
 	// Local variables
 	g := t.Game
 	p := getEquivalentPlayer(t, userID)
@@ -44,16 +50,16 @@ func (a *ActionDraw) Scrub(t *Table, userID int) {
 
 	if a.PlayerIndex == p.Index || // They are drawing the card
 		// They are playing a special character that should not be able to see the card
-		characterHideCard(a, g, p) {
+		g.State.CharacterHideCard(a, p.Player) {
 
 		a.Rank = -1
 		a.SuitIndex = -1
 	}
 }
 
-// Scrub removes some information from played cards so that we do not reveal the identity of played
-// cards to anybody (in some specific variants)
-func (a *ActionPlay) Scrub(t *Table, userID int) {
+// scrubPlay removes some information from played cards so that we do not reveal the identity of
+// played cards to anybody (in some specific variants)
+func scrubPlay(t *Table, a *ActionPlay, userID int) {
 	// Local variables
 	p := getEquivalentPlayer(t, userID)
 	variant := variants[t.Options.VariantName]
@@ -63,15 +69,15 @@ func (a *ActionPlay) Scrub(t *Table, userID int) {
 		return
 	}
 
-	if variant.IsThrowItInAHole() {
+	if variant.HidePlays {
 		a.Rank = -1
 		a.SuitIndex = -1
 	}
 }
 
-// Scrub removes some information from discarded cards so that we do not reveal the identity of
-// discarded cards to anybody (in some specific variants)
-func (a *ActionDiscard) Scrub(t *Table, userID int) {
+// scrubDiscard removes some information from discarded cards so that we do not reveal the
+// identity of discarded cards to anybody (in some specific variants)
+func scrubDiscard(t *Table, a *ActionDiscard, userID int) {
 	// Local variables
 	p := getEquivalentPlayer(t, userID)
 	variant := variants[t.Options.VariantName]
@@ -81,16 +87,46 @@ func (a *ActionDiscard) Scrub(t *Table, userID int) {
 		return
 	}
 
-	if variant.IsThrowItInAHole() && a.Failed {
+	if variant.HidePlays && a.Failed {
 		// For the purposes of hiding information, failed discards are equivalent to plays
 		a.Rank = -1
 		a.SuitIndex = -1
 	}
 }
 
-// Scrub removes some information from a card identity action so that we do not reveal the identity
-// of sliding cards to the players who are holding those cards
-func (a *ActionCardIdentity) Scrub(t *Table, userID int) {
+// scrubClue removes some information from clues so that we do not reveal the type or the value of
+// a clue to the players who receive it (in some specific variants)
+func scrubClue(t *Table, a *ActionClue, userID int) {
+	// Local variables
+	p := getEquivalentPlayer(t, userID)
+	variant := variants[t.Options.VariantName]
+
+	if p == nil || p.Index == a.Giver {
+		// Spectators and the player who gave the clue get to see the clue
+		return
+	}
+
+	// The client does not apply clues to the cards in these variants,
+	// so we replace the hidden parts with the first valid value instead of removing them
+	if variant.HideClueTypes {
+		if len(variant.ClueColors) > 0 {
+			a.Clue.Type = ClueTypeColor
+		} else {
+			a.Clue.Type = ClueTypeRank
+		}
+	}
+	if variant.HideClueValues {
+		if a.Clue.Type == ClueTypeColor {
+			a.Clue.Value = 0
+		} else if len(variant.ClueRanks) > 0 {
+			a.Clue.Value = variant.ClueRanks[0]
+		}
+	}
+}
+
+// scrubCardIdentity removes some information from a card identity action so that we do not reveal
+// the identity of sliding cards to the players who are holding those cards
+func scrubCardIdentity(t *Table, a *ActionCardIdentity, userID int) {
 	// Local variables
 	p := getEquivalentPlayer(t, userID)
 
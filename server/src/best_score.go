@@ -10,14 +10,15 @@ type BestScore struct {
 	OneExtraCard bool `json:"oneExtraCard"`
 	OneLessCard  bool `json:"oneLessCard"`
 	AllOrNothing bool `json:"allOrNothing"`
+	CustomRules  bool `json:"customRules"`
 }
 
 func NewBestScores() []*BestScore {
-	bestScores := make([]*BestScore, 5) // From 2 to 6 players
+	bestScores := make([]*BestScore, NumBestScores) // From 2 to 8 players
 	for i := range bestScores {
 		// This will not work if written as "for i, bestScore :="
 		bestScores[i] = &BestScore{}
-		bestScores[i].NumPlayers = i + 2
+		bestScores[i].NumPlayers = i + MinPlayers
 	}
 	return bestScores
 }
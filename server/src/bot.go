@@ -0,0 +1,211 @@
+// Bots that run inside of the server and can sit in a seat at a table
+// (bots can also connect through the WebSocket like any other user; see "docs/BOTS.md")
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
+	"github.com/alexedwards/argon2id"
+)
+
+// BotPlayer is the interface that every bot that runs inside of the server must satisfy
+type BotPlayer interface {
+	// GetAction is called when it is the bot's turn
+	// The state is a copy, so the bot is free to modify it
+	// (the state contains the entire deck, so the bot must not look at the cards in its own hand)
+	GetAction(state *engine.State, playerIndex int) *engine.Action
+}
+
+const (
+	// The amount of time that a bot waits before performing an action
+	// (so that the humans at the table can follow along)
+	BotActionDelay = time.Second
+)
+
+var (
+	// The keys are the usernames of the bot accounts
+	// The values are the functions that create a new instance of the bot
+	botConstructors = map[string]func() BotPlayer{
+		"Bot-Random": NewRandomBot,
+	}
+	// The keys are the usernames of the bot accounts and the values are their user IDs
+	botUserIDs = make(map[string]int)
+
+	// By default, games with bots in them do not affect the stats of anyone involved
+	botGamesUpdateStats bool
+)
+
+func botsInit() {
+	botGamesUpdateStats = os.Getenv("BOT_GAMES_UPDATE_STATS") == "true"
+
+	// Every bot that runs inside of the server needs a corresponding account in the database
+	for name := range botConstructors {
+		var exists bool
+		var user User
+		if v1, v2, err := models.Users.Get(name); err != nil {
+			logger.Fatal("Failed to get the account for bot \"" + name + "\": " + err.Error())
+			return
+		} else {
+			exists = v1
+			user = v2
+		}
+
+		if !exists {
+			// Nobody should be able to log in as a bot,
+			// so we use the hash of a password that is immediately thrown away
+			var passwordHash string
+			password := strconv.FormatInt(time.Now().UnixNano(), 10) + getName()
+			if v, err := argon2id.CreateHash(password, argon2id.DefaultParams); err != nil {
+				logger.Fatal("Failed to create a password hash for bot \"" + name + "\": " +
+					err.Error())
+				return
+			} else {
+				passwordHash = v
+			}
+
+			if v, err := models.Users.Insert(
+				name,
+				normalizeString(name),
+				passwordHash,
+				"127.0.0.1",
+			); err != nil {
+				logger.Fatal("Failed to insert the account for bot \"" + name + "\": " + err.Error())
+				return
+			} else {
+				user = v
+			}
+		}
+
+		if err := models.Users.SetBot(user.ID, true); err != nil {
+			logger.Fatal("Failed to flag the account for bot \"" + name + "\": " + err.Error())
+			return
+		}
+
+		botUserIDs[name] = user.ID
+	}
+}
+
+// NewBotSession prepares a "fake" user session that a bot will use to perform actions
+func NewBotSession(id int, name string) *Session {
+	s := NewFakeSession(id, name)
+	s.Bot = true
+
+	return s
+}
+
+// tableAddBot seats a bot that runs inside of the server at a table that has not started yet
+// The table lock is assumed to be acquired in this function
+func tableAddBot(ctx context.Context, d *CommandData, t *Table, name string) {
+	// Since this is a function that changes a user's relationship to tables,
+	// we must acquires the tables lock to prevent race conditions
+	if !d.NoTablesLock {
+		tables.Lock(ctx)
+		defer tables.Unlock(ctx)
+	}
+
+	userID := botUserIDs[name]
+	p := &Player{
+		UserID:         userID,
+		Name:           name,
+		OriginalUserID: 0,
+		Session:        NewBotSession(userID, name),
+		Present:        true,
+		Bot:            true,
+		BotPlayer:      botConstructors[name](),
+		Stats: &PregameStats{
+			NumGames: 0,
+			Variant:  NewUserStatsRow(),
+		},
+		Typing:    false,
+		LastTyped: time.Time{},
+	}
+
+	t.Players = append(t.Players, p)
+	tables.AddPlaying(userID, t.ID) // Keep track of user to table relationships
+
+	logger.Info(t.GetName() + "Bot \"" + name + "\" joined. " +
+		"(There are now " + strconv.Itoa(len(t.Players)) + " players.)")
+
+	notifyAllTable(t)
+	t.NotifyPlayerChange()
+}
+
+// CheckBotTurn makes the active player take their turn if they are a bot that runs inside of the
+// server
+// The table lock is assumed to be acquired in this function
+func (g *Game) CheckBotTurn(ctx context.Context) {
+	// Local variables
+	t := g.Table
+
+	if g.State.EndCondition > EndConditionInProgress || g.Paused {
+		return
+	}
+
+	p := t.Players[g.State.ActivePlayerIndex]
+	if p.BotPlayer == nil {
+		return
+	}
+
+	go g.BotTakeTurn(ctx, len(g.Actions2), p)
+}
+
+// BotTakeTurn is meant to be called in a new goroutine
+func (g *Game) BotTakeTurn(ctx context.Context, numActions int, p *Player) {
+	// Give the other players a chance to see what is happening
+	time.Sleep(BotActionDelay)
+
+	// Local variables
+	t := g.Table
+
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(ctx, nil, t.ID, false, true)
+	if !exists || t != t2 {
+		return
+	}
+	t.Lock(ctx)
+	defer t.Unlock(ctx)
+
+	// Check to see if someone has made a move in the meanwhile
+	// (we cannot use the turn for this because some characters take two actions in one turn)
+	if numActions != len(g.Actions2) {
+		return
+	}
+
+	// Check to see if the game is currently paused or has ended already
+	if g.Paused || g.State.EndCondition > EndConditionInProgress {
+		return
+	}
+
+	a := p.BotPlayer.GetAction(g.State.Clone(), g.State.ActivePlayerIndex)
+	if a == nil {
+		logger.Error(t.GetName() + "Bot \"" + p.Name + "\" did not return an action.")
+		return
+	}
+
+	commandAction(ctx, p.Session, &CommandData{ // nolint: exhaustivestruct
+		TableID:     t.ID,
+		Type:        a.Type,
+		Target:      a.Target,
+		Value:       a.Value,
+		NoTableLock: true,
+	})
+
+	if numActions == len(g.Actions2) {
+		logger.Error(t.GetName() + "Bot \"" + p.Name + "\" performed an illegal action.")
+	}
+}
+
+func (t *Table) HasBots() bool {
+	for _, p := range t.Players {
+		if p.Bot {
+			return true
+		}
+	}
+
+	return false
+}
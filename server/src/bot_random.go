@@ -0,0 +1,30 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// RandomBot performs a random legal action on every turn
+// It is mostly useful for testing
+type RandomBot struct {
+	Rand *rand.Rand
+}
+
+func NewRandomBot() BotPlayer {
+	return &RandomBot{
+		Rand: rand.New(rand.NewSource(time.Now().UnixNano())), // nolint: gosec
+	}
+}
+
+func (b *RandomBot) GetAction(state *engine.State, playerIndex int) *engine.Action {
+	legalActions := state.LegalActions(playerIndex)
+	if len(legalActions) == 0 {
+		return nil
+	}
+
+	a := legalActions[b.Rand.Intn(len(legalActions))].Action
+	return &a
+}
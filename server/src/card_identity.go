@@ -1,6 +0,0 @@
-package main
-
-type CardIdentity struct {
-	SuitIndex int `json:"suitIndex"`
-	Rank      int `json:"rank"`
-}
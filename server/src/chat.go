@@ -171,7 +171,11 @@ func chatSendPastFromDatabase(s *Session, room string, count int) bool {
 }
 
 func chatSendPastFromTable(s *Session, t *Table) {
+	// The players of a game with a spectator delay cannot see the spectator chat
+	isPlayer := !t.Replay && t.GetPlayerIndexFromID(s.UserID) != -1
+
 	chatList := make([]*ChatMessage, 0)
+	unread := 0
 	i := 0
 	if len(t.Chat) > ChatLimit {
 		i = len(t.Chat) - ChatLimit
@@ -179,6 +183,12 @@ func chatSendPastFromTable(s *Session, t *Table) {
 	for ; i < len(t.Chat); i++ {
 		// We have to convert the *GameChatMessage to a *ChatMessage
 		gcm := t.Chat[i]
+		if gcm.HiddenFromPlayers && isPlayer {
+			continue
+		}
+		if i >= t.ChatRead[s.UserID] {
+			unread++
+		}
 		cm := &ChatMessage{
 			Msg:       gcm.Msg,
 			Who:       gcm.Username,
@@ -192,6 +202,6 @@ func chatSendPastFromTable(s *Session, t *Table) {
 	}
 	s.Emit("chatList", &ChatListMessage{
 		List:   chatList,
-		Unread: len(t.Chat) - t.ChatRead[s.UserID],
+		Unread: unread,
 	})
 }
@@ -8,6 +8,10 @@ import (
 var (
 	// Used to store all of the functions that handle each command
 	chatCommandMap = make(map[string]func(context.Context, *Session, *CommandData, *Table))
+
+	// Private commands are not sent to the room, so they are stored separately
+	// (see "chatPrivateCommand()")
+	chatPrivateCommandMap = make(map[string]func(context.Context, *Session, *CommandData))
 )
 
 func chatCommandInit() {
@@ -32,6 +36,9 @@ func chatCommandInit() {
 	chatCommandMap["uptime"] = chatUptime
 	chatCommandMap["timeleft"] = chatTimeLeft
 
+	// Lobby-only commands
+	chatCommandMap["puzzle"] = chatPuzzle
+
 	// Undocumented info commands (that work only in the lobby)
 	chatCommandMap["here"] = chatHere
 	chatCommandMap["wrongchannel"] = chatWrongChannel
@@ -43,10 +50,13 @@ func chatCommandInit() {
 	chatCommandMap["s4"] = chatS4
 	chatCommandMap["s5"] = chatS5
 	chatCommandMap["s6"] = chatS6
+	chatCommandMap["s7"] = chatS7
+	chatCommandMap["s8"] = chatS8
 	chatCommandMap["si"] = chatStartIn
 	chatCommandMap["startin"] = chatStartIn
 	chatCommandMap["kick"] = chatKick
 	chatCommandMap["impostor"] = chatImpostor
+	chatCommandMap["addbot"] = chatAddBot
 
 	// Table-only commands (pregame or game)
 	chatCommandMap["m"] = chatMissingScores
@@ -62,11 +72,19 @@ func chatCommandInit() {
 	chatCommandMap["random-variant"] = chatFindVariant
 
 	// Table-only commands (game only)
+	chatCommandMap["substitute"] = chatSubstitute
+	chatCommandMap["sub"] = chatSubstitute
+	chatCommandMap["vote"] = chatVote
+	chatCommandMap["undo"] = chatUndo
+	chatCommandMap["accept"] = chatAccept
+	chatCommandMap["decline"] = chatDecline
 	// chatCommandMap["pause"] = chatPause
 	// chatCommandMap["unpause"] = chatUnpause
 
 	// Table-only commands (replay only)
 	chatCommandMap["suggest"] = chatSuggest
+	chatCommandMap["solve"] = chatSolve
+	chatCommandMap["maxscore"] = chatSolve
 	chatCommandMap["tags"] = chatTags
 	chatCommandMap["taglist"] = chatTags
 
@@ -79,7 +97,15 @@ func chatCommandInit() {
 	chatCommandMap["friend"] = chatCommandWebsiteOnly
 	chatCommandMap["friends"] = chatCommandWebsiteOnly
 	chatCommandMap["unfriend"] = chatCommandWebsiteOnly
+	chatCommandMap["report"] = chatCommandWebsiteOnly
+	chatCommandMap["block"] = chatCommandWebsiteOnly
+	chatCommandMap["unblock"] = chatCommandWebsiteOnly
 	chatCommandMap["version"] = chatCommandWebsiteOnly
+
+	// Private commands (that work both in the lobby and at a table, but not from Discord)
+	chatPrivateCommandMap["report"] = chatReport
+	chatPrivateCommandMap["block"] = chatBlock
+	chatPrivateCommandMap["unblock"] = chatUnblock
 }
 
 func chatCommand(ctx context.Context, s *Session, d *CommandData, t *Table) {
@@ -106,6 +132,26 @@ func chatCommand(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	}
 }
 
+// chatPrivateCommand handles commands that only the sender should see
+// (e.g. the reported user should not see the report)
+// It is called before the message is sent to the room and returns true if the message was a
+// private command
+func chatPrivateCommand(ctx context.Context, s *Session, d *CommandData) bool {
+	args := strings.Fields(d.Msg)
+	if len(args) == 0 || !strings.HasPrefix(args[0], "/") {
+		return false
+	}
+	command := strings.ToLower(strings.TrimPrefix(args[0], "/"))
+
+	chatPrivateCommandFunction, ok := chatPrivateCommandMap[command]
+	if !ok {
+		return false
+	}
+	d.Args = args[1:]
+	chatPrivateCommandFunction(ctx, s, d)
+	return true
+}
+
 func chatCommandWebsiteOnly(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	msg := "You cannot perform that command from Discord; please use the website instead."
 	chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
@@ -1,5 +1,152 @@
 package main
 
+import (
+	"context"
+	"strings"
+)
+
+// /substitute [player] [spectator]
+func chatSubstitute(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	if t == nil || d.Room == "lobby" {
+		chatServerSend(ctx, NotInGameFail, "lobby", d.NoTablesLock)
+		return
+	}
+
+	if !t.Running {
+		chatServerSend(ctx, NotStartedFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if t.Replay {
+		msg := "You cannot substitute a player in a replay."
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if s.UserID != t.OwnerID {
+		chatServerSend(ctx, NotOwnerFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if len(d.Args) != 2 {
+		msg := "The format of the /substitute command is: /substitute [player] [spectator]"
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	// Find the seat of the player
+	playerIndex := -1
+	normalizedUsername := normalizeString(d.Args[0])
+	for i, p := range t.Players {
+		if normalizedUsername == normalizeString(p.Name) {
+			playerIndex = i
+			break
+		}
+	}
+	if playerIndex == -1 {
+		msg := "\"" + d.Args[0] + "\" is not playing in this game."
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	commandTableSubstitute(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		TableID:      t.ID,
+		PlayerIndex:  playerIndex,
+		Name:         d.Args[1],
+		NoTableLock:  true,
+		NoTablesLock: d.NoTablesLock,
+	})
+}
+
+// /vote [terminate|pause|unpause|restart|undo]
+func chatVote(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	if t == nil || d.Room == "lobby" {
+		chatServerSend(ctx, NotInGameFail, "lobby", d.NoTablesLock)
+		return
+	}
+
+	if !t.Running {
+		chatServerSend(ctx, NotStartedFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if len(d.Args) != 1 {
+		msg := "The format of the /vote command is: " +
+			"/vote [terminate|pause|unpause|restart|undo]"
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	commandTableVote(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		TableID:      t.ID,
+		Setting:      strings.ToLower(d.Args[0]),
+		NoTableLock:  true,
+		NoTablesLock: d.NoTablesLock,
+	})
+}
+
+// /undo
+func chatUndo(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	if t == nil || d.Room == "lobby" {
+		chatServerSend(ctx, NotInGameFail, "lobby", d.NoTablesLock)
+		return
+	}
+
+	if !t.Running {
+		chatServerSend(ctx, NotStartedFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	commandTableVote(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		TableID:      t.ID,
+		Setting:      VoteTypeUndo,
+		NoTableLock:  true,
+		NoTablesLock: d.NoTablesLock,
+	})
+}
+
+// /accept
+func chatAccept(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	chatCastVote(ctx, s, d, t, true)
+}
+
+// /decline
+func chatDecline(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	chatCastVote(ctx, s, d, t, false)
+}
+
+// chatCastVote votes on either the pending table vote or the pending substitution
+// (there can only be one of them at a time)
+func chatCastVote(ctx context.Context, s *Session, d *CommandData, t *Table, vote bool) {
+	if t == nil || d.Room == "lobby" {
+		chatServerSend(ctx, NotInGameFail, "lobby", d.NoTablesLock)
+		return
+	}
+
+	if t.Vote != nil {
+		commandTableVoteCast(ctx, s, &CommandData{ // nolint: exhaustivestruct
+			TableID:      t.ID,
+			Vote:         vote,
+			NoTableLock:  true,
+			NoTablesLock: d.NoTablesLock,
+		})
+		return
+	}
+
+	if t.Substitution != nil {
+		commandTableSubstituteVote(ctx, s, &CommandData{ // nolint: exhaustivestruct
+			TableID:      t.ID,
+			Vote:         vote,
+			NoTableLock:  true,
+			NoTablesLock: d.NoTablesLock,
+		})
+		return
+	}
+
+	msg := "There is nothing to vote on."
+	chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+}
+
 /*
 // /pause
 func chatPause(ctx context.Context, s *Session, d *CommandData, t *Table) {
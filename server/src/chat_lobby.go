@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"strconv"
+)
+
+// /puzzle [puzzleID]
+func chatPuzzle(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	if t != nil {
+		chatServerSend(ctx, NotInLobbyFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if s == nil || d.Discord {
+		chatCommandWebsiteOnly(ctx, s, d, t)
+		return
+	}
+
+	if len(d.Args) != 1 {
+		msg := "The format of the /puzzle command is: /puzzle [puzzle ID]"
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	var puzzleID int
+	if v, err := strconv.Atoi(d.Args[0]); err != nil {
+		msg := "\"" + d.Args[0] + "\" is not a number."
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	} else {
+		puzzleID = v
+	}
+
+	commandPuzzleStart(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		PuzzleID:     puzzleID,
+		NoTablesLock: d.NoTablesLock,
+	})
+}
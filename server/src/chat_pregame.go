@@ -43,6 +43,16 @@ func chatS6(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	automaticStart(ctx, s, d, t, 6)
 }
 
+// /s7 - Automatically start the game as soon as there are 7 players
+func chatS7(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	automaticStart(ctx, s, d, t, 7)
+}
+
+// /s8 - Automatically start the game as soon as there are 8 players
+func chatS8(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	automaticStart(ctx, s, d, t, 8)
+}
+
 // /startin [minutes]
 func chatStartIn(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	if t == nil || d.Room == "lobby" {
@@ -190,8 +200,9 @@ func chatMissingScores(ctx context.Context, s *Session, d *CommandData, t *Table
 		}
 	}
 
-	if len(usernames) < 2 || len(usernames) > 6 {
-		msg := "You can only perform this command if the game or shared replay has between 2 and 6 players."
+	if len(usernames) < MinPlayers || len(usernames) > MaxPlayers {
+		msg := "You can only perform this command if the game or shared replay has between " +
+			strconv.Itoa(MinPlayers) + " and " + strconv.Itoa(MaxPlayers) + " players."
 		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
 		return
 	}
@@ -222,8 +233,9 @@ func chatFindVariant(ctx context.Context, s *Session, d *CommandData, t *Table)
 		}
 	}
 
-	if len(userIDs) < 2 || len(userIDs) > 6 {
-		msg := "You can only perform this command if the game or shared replay has between 2 and 6 players."
+	if len(userIDs) < MinPlayers || len(userIDs) > MaxPlayers {
+		msg := "You can only perform this command if the game or shared replay has between " +
+			strconv.Itoa(MinPlayers) + " and " + strconv.Itoa(MaxPlayers) + " players."
 		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
 		return
 	}
@@ -250,8 +262,8 @@ func chatFindVariant(ctx context.Context, s *Session, d *CommandData, t *Table)
 			if stats, ok := statsMap[variant.ID]; ok {
 				// This player has played at least one game in this particular variant
 				// Check to see if they have a max score
-				// We minus 2 because element 0 is for 2-player, element 1 is for 3-player, etc.
-				if stats.BestScores[len(userIDs)-2].Score == maxScore {
+				// Element 0 is for 2-player, element 1 is for 3-player, etc.
+				if stats.BestScores[len(userIDs)-MinPlayers].Score == maxScore {
 					someoneHasMaxScore = true
 					break
 				}
@@ -301,8 +313,9 @@ func automaticStart(ctx context.Context, s *Session, d *CommandData, t *Table, n
 			numPlayers = v
 		}
 
-		if numPlayers < 2 || numPlayers > 6 {
-			msg := "You can only start a table with 2 to 6 players."
+		if numPlayers < MinPlayers || numPlayers > MaxPlayers {
+			msg := "You can only start a table with " + strconv.Itoa(MinPlayers) + " to " +
+				strconv.Itoa(MaxPlayers) + " players."
 			chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
 			return
 		}
@@ -412,3 +425,68 @@ func chatImpostor(ctx context.Context, s *Session, d *CommandData, t *Table) {
 		p.Session.Emit("chat", chatMessage)
 	}
 }
+
+// /addbot [bot name]
+func chatAddBot(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	if t == nil || d.Room == "lobby" {
+		chatServerSend(ctx, NotInGameFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if t.Running {
+		chatServerSend(ctx, StartedFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if t.Replay {
+		msg := "You cannot add a bot to a replay."
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if s.UserID != t.OwnerID {
+		chatServerSend(ctx, NotOwnerFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if len(d.Args) > 1 {
+		msg := "The format of the /addbot command is: /addbot [bot name]"
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	// Default to the random bot if a name was not provided
+	name := "Bot-Random"
+	if len(d.Args) == 1 {
+		name = ""
+		for botName := range botConstructors {
+			if normalizeString(d.Args[0]) == normalizeString(botName) {
+				name = botName
+				break
+			}
+		}
+		if name == "" {
+			msg := "\"" + d.Args[0] + "\" is not a valid bot name."
+			chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+			return
+		}
+	}
+
+	if len(t.Players) >= MaxPlayers {
+		msg := "The table is already full. " +
+			"(You can not play with more than " + strconv.Itoa(MaxPlayers) + " players.)"
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	if t.GetPlayerIndexFromID(botUserIDs[name]) != -1 {
+		msg := "\"" + name + "\" is already joined to this game."
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	tableAddBot(ctx, d, t, name)
+
+	msg := "Successfully added \"" + name + "\" to the game."
+	chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sort"
 	"strconv"
+
+	"github.com/Zamiell/hanabi-live/engine"
 )
 
 // /suggest
@@ -81,3 +83,57 @@ func chatTags(ctx context.Context, s *Session, d *CommandData, t *Table) {
 		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
 	}
 }
+
+// /solve
+func chatSolve(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	if t == nil || d.Room == "lobby" {
+		chatServerSend(ctx, NotInGameFail, "lobby", d.NoTablesLock)
+		return
+	}
+
+	if !t.Replay {
+		chatServerSend(ctx, NotReplayFail, d.Room, d.NoTablesLock)
+		return
+	}
+
+	// Local variables
+	g := t.Game
+
+	if g.InitialState == nil {
+		msg := "The deck for this replay is not available, so it cannot be solved."
+		chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+		return
+	}
+
+	msg := "Searching for the best achievable score on this deck..."
+	chatServerSend(ctx, msg, d.Room, d.NoTablesLock)
+
+	// The search can take a while, so do it in the background (without holding the table lock)
+	go solveReplay(ctx, t, g.InitialState.Clone(), g.Seed, g.Options)
+}
+
+// solveReplay is meant to be called in a new goroutine
+func solveReplay(ctx context.Context, t *Table, state *engine.State, seed string, options *Options) {
+	solution := solveState(state, seed, options)
+
+	msg := "The best achievable score on this deck is " + strconv.Itoa(solution.Score)
+	if solution.Proven {
+		msg += "."
+	} else {
+		msg += " (or better; the search ran out of time)."
+	}
+	if t.ExtraOptions.DatabaseID > 0 {
+		path := "/solve/" + strconv.Itoa(t.ExtraOptions.DatabaseID)
+		msg += " The line that achieves it is at: " + getURLFromPath(path)
+	}
+
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(ctx, nil, t.ID, false, true)
+	if !exists || t != t2 {
+		return
+	}
+	t.Lock(ctx)
+	defer t.Unlock(ctx)
+
+	chatServerSend(ctx, msg, t.GetRoomName(), false)
+}
@@ -34,9 +34,14 @@ type CommandData struct {
 	// tableSpectate
 	ShadowingPlayerIndex int `json:"shadowingPlayerIndex"`
 
+	// tableSubstitute, tableVoteCast
+	PlayerIndex int  `json:"playerIndex"`
+	Vote        bool `json:"vote"`
+
 	// replayCreate
 	Source     string    `json:"source"`
 	GameJSON   *GameJSON `json:"gameJSON"`
+	GameText   string    `json:"gameText"`
 	Visibility string    `json:"visibility"`
 
 	// sharedReplay
@@ -45,6 +50,9 @@ type CommandData struct {
 	Suit    int    `json:"suit"`
 	Sound   string `json:"sound"`
 
+	// puzzleStart
+	PuzzleID int `json:"puzzleID"`
+
 	// historyGet
 	Offset int `json:"offset"`
 	Amount int `json:"amount"`
@@ -98,6 +106,10 @@ func commandInit() {
 	commandMap["tableSpectate"] = commandTableSpectate
 	commandMap["tableRestart"] = commandTableRestart
 	commandMap["tableUpdate"] = commandTableUpdate
+	commandMap["tableSubstitute"] = commandTableSubstitute
+	commandMap["tableSubstituteVote"] = commandTableSubstituteVote
+	commandMap["tableVote"] = commandTableVote
+	commandMap["tableVoteCast"] = commandTableVoteCast
 
 	// Other lobby commands
 	commandMap["setting"] = commandSetting
@@ -107,6 +119,8 @@ func commandInit() {
 	commandMap["chatTyping"] = commandChatTyping
 	commandMap["chatFriend"] = commandChatFriend
 	commandMap["chatUnfriend"] = commandChatUnfriend
+	commandMap["chatBlock"] = commandChatBlock
+	commandMap["chatUnblock"] = commandChatUnblock
 	commandMap["chatPlayerInfo"] = commandChatPlayerInfo
 	commandMap["getName"] = commandGetName
 	commandMap["inactive"] = commandInactive
@@ -115,11 +129,16 @@ func commandInit() {
 	commandMap["historyFriendsGet"] = commandHistoryFriendsGet
 	commandMap["replayCreate"] = commandReplayCreate
 	commandMap["tagSearch"] = commandTagSearch
+	commandMap["correspondenceTableList"] = commandCorrespondenceTableList
+	commandMap["puzzleList"] = commandPuzzleList
+	commandMap["puzzleStart"] = commandPuzzleStart
+	commandMap["report"] = commandReport
 
 	// Game and replay commands
 	commandMap["getGameInfo1"] = commandGetGameInfo1
 	commandMap["getGameInfo2"] = commandGetGameInfo2
 	commandMap["loaded"] = commandLoaded
+	commandMap["getLegalActions"] = commandGetLegalActions
 	commandMap["tag"] = commandTag
 	commandMap["tagDelete"] = commandTagDelete
 
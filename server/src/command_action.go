@@ -2,24 +2,13 @@ package main
 
 import (
 	"context"
+	"math"
 	"strconv"
 	"time"
-)
 
-var (
-	actionFunctions map[int]func(*Session, *CommandData, *Game, *GamePlayer) bool
+	"github.com/Zamiell/hanabi-live/engine"
 )
 
-func actionsFunctionsInit() {
-	actionFunctions = map[int]func(*Session, *CommandData, *Game, *GamePlayer) bool{
-		ActionTypePlay:      commandActionPlay,
-		ActionTypeDiscard:   commandActionDiscard,
-		ActionTypeColorClue: commandActionClue,
-		ActionTypeRankClue:  commandActionClue,
-		ActionTypeEndGame:   commandActionEndGame,
-	}
-}
-
 // commandAction is sent when the user performs an in-game action
 //
 // Example data:
@@ -71,7 +60,7 @@ func commandAction(ctx context.Context, s *Session, d *CommandData) {
 
 	if d.Type != ActionTypeEndGame {
 		// Validate that it is this player's turn
-		if g.ActivePlayerIndex != playerIndex {
+		if g.State.ActivePlayerIndex != playerIndex {
 			s.Warning("It is not your turn, so you cannot perform an action.")
 			g.InvalidActionOccurred = true
 			return
@@ -83,16 +72,14 @@ func commandAction(ctx context.Context, s *Session, d *CommandData) {
 			g.InvalidActionOccurred = true
 			return
 		}
+	}
 
-		// Validate that a player is not doing an illegal action for their character
-		if characterValidateAction(s, d, g, p) {
-			g.InvalidActionOccurred = true
-			return
-		}
-		if characterValidateSecondAction(s, d, g, p) {
-			g.InvalidActionOccurred = true
-			return
-		}
+	// In puzzles, the first move that the player makes is graded instead of being performed
+	if t.ExtraOptions.PuzzleID != 0 && d.Type != ActionTypeEndGame &&
+		len(g.Actions2) == t.ExtraOptions.SetReplayTurn {
+
+		puzzleMove(ctx, s, d, t)
+		return
 	}
 
 	action(ctx, s, d, t, p)
@@ -108,94 +95,77 @@ func action(ctx context.Context, s *Session, d *CommandData, t *Table, p *GamePl
 		go t.CheckIdle(ctx)
 	}
 
-	// Do different tasks depending on the action
-	if actionFunction, ok := actionFunctions[d.Type]; ok {
-		if !actionFunction(s, d, g, p) {
-			return
-		}
-	} else {
-		s.Warning("That is not a valid action type.")
+	// Let the engine validate and perform the action
+	// (the rules of the game and the validation of the rules are all contained in the engine)
+	// The action is performed on behalf of the player who sent it,
+	// since an "end game" action can be sent by a player whose turn it is not
+	a := engine.NewAction(d.Type, d.Target, d.Value)
+	oldEndTurn := g.State.EndTurn
+	state, events, err := g.State.ApplyAs(a, p.Index)
+	if err != nil {
+		s.Warning(err.Error())
 		g.InvalidActionOccurred = true
 		return
 	}
+	g.SetState(state)
 
-	// Do post-action tasks
-	characterPostAction(d, g, p)
-
-	// Send a message about the current status
-	t.NotifyStatus()
-
-	// Adjust the timer for the player that just took their turn
-	// (if the game is over now due to a player running out of time, we don't need to adjust the
-	// timer because we already set it to 0 in the "checkTimer" function)
-	if d.Type != ActionTypeEndGame {
-		p.Time -= time.Since(g.DatetimeTurnBegin)
-		// (in non-timed games,
-		// "Time" will decrement into negative numbers to show how much time they are taking)
+	// Add the action to the action log
+	// (in the future, we will delete GameActions and only keep track of GameActions2)
+	if a.Type == ActionTypeEndGame && a.Value == EndConditionIdleTimeout {
+		// No particular player is responsible for the game ending due to idleness
+		a.Target = -1
+	}
+	g.Actions2 = append(g.Actions2, a)
 
-		// In timed games, a player gains additional time after performing an action
-		if t.Options.Timed {
-			p.Time += time.Duration(t.Options.TimePerTurn) * time.Second
-		}
+	// A pending undo was for the previous move, so it no longer applies
+	t.CancelUndoVote(ctx, d)
 
-		g.DatetimeTurnBegin = time.Now()
+	// Keep track of how long every action took (for the analysis at the end of the game)
+	turnTimeTaken := time.Duration(0)
+	if a.Type != ActionTypeEndGame {
+		turnTimeTaken = g.GetTurnTimeTaken()
 	}
+	g.TurnDurations = append(g.TurnDurations, turnTimeTaken)
 
-	// If a player has just taken their final turn,
-	// mark all of the cards in their hand as not able to be played
-	// (but don't do this if we are in an end game that has a custom amount of turns)
-	if g.Options.DetrimentalCharacters {
-		if characterHasTakenLastTurn(g) {
-			for _, c := range p.Hand {
-				c.CannotBePlayed = true
-			}
-		}
-	} else if g.EndTurn != -1 &&
-		g.EndTurn != g.Turn+len(g.Players)+1 {
-
-		for _, c := range p.Hand {
-			c.CannotBePlayed = true
-		}
+	// Send the events to everyone
+	for _, event := range events {
+		g.Actions = append(g.Actions, event)
+		t.NotifyGameAction()
 	}
 
-	// Increment the turn
-	// (but don't increment it if we are on a characters that take two turns in a row)
-	if !characterNeedsToTakeSecondTurn(d, g, p) {
-		g.Turn++
-		if g.TurnsInverted {
-			// In Golang, "%" will give the remainder and not the modulus,
-			// so we need to ensure that the result is not negative or we will get a
-			// "index out of range" error
-			g.ActivePlayerIndex += len(g.Players)
-			g.ActivePlayerIndex = (g.ActivePlayerIndex - 1) % len(g.Players)
-		} else {
-			g.ActivePlayerIndex = (g.ActivePlayerIndex + 1) % len(g.Players)
-		}
+	if g.State.EndTurn != oldEndTurn {
+		logger.Info(t.GetName() + "Marking to end the game on turn: " +
+			strconv.Itoa(g.State.EndTurn))
 	}
-	nextPlayer := g.Players[g.ActivePlayerIndex]
-	nextPlayerSession := t.Players[nextPlayer.Index].Session
 
-	// Check for character-related softlocks
-	// (we will set the strikes to 3 if there is a softlock)
-	characterCheckSoftlock(g, nextPlayer)
+	// Update the progress
+	// (the lobby is not shown the progress while there is a spectator delay,
+	// since that would reveal the score before the spectators can see it)
+	progressFloat := float64(g.State.Score) / float64(g.State.MaxScore) * 100 // In percent
+	progress := int(math.Round(progressFloat))
+	oldProgress := t.Progress
+	if progress != oldProgress && !t.HasSpectatorDelay() {
+		t.Progress = progress
+		t.NotifyProgress()
+	}
 
-	// Check for end game states
-	if g.CheckEnd() {
-		// Append a game over action
-		g.Actions = append(g.Actions, ActionGameOver{
-			Type:         "gameOver",
-			EndCondition: g.EndCondition,
-			PlayerIndex:  g.EndPlayer,
-		})
-		t.NotifyGameAction()
+	// Adjust the timer for the player that just took their turn
+	// (if the game is over now due to a player running out of time, we don't need to adjust the
+	// timer because we already set it to 0 in the "EndTimer()" function)
+	if d.Type != ActionTypeEndGame {
+		g.ChargeTime(p, turnTimeTaken)
+		g.TurnTimeTaken = 0
+		g.DatetimeTurnBegin = time.Now()
 	}
 
-	// Send the new turn
-	t.NotifyTurn()
+	nextPlayer := g.Players[g.State.ActivePlayerIndex]
+	nextPlayerSession := t.Players[nextPlayer.Index].Session
 
-	if g.EndCondition == EndConditionInProgress {
+	if g.State.EndCondition == EndConditionInProgress {
 		logger.Info(t.GetName() + "It is now " + nextPlayer.Name + "'s turn.")
 	} else {
+		logger.Info(t.GetName() + "The game is over with an end condition of " +
+			strconv.Itoa(g.State.EndCondition) + ".")
 		g.End(ctx, d)
 		return
 	}
@@ -203,10 +173,17 @@ func action(ctx context.Context, s *Session, d *CommandData, t *Table, p *GamePl
 	// Send everyone new clock values
 	t.NotifyTime()
 
+	// Correspondence games are saved after every move
+	// (and the players that are not currently looking at the game need to know whose turn it is)
+	if t.IsOngoingCorrespondenceGame() {
+		t.SaveCorrespondence()
+		t.NotifyCorrespondence()
+	}
+
 	if t.Options.Timed && !t.ExtraOptions.NoWriteToDatabase {
-		// Start the function that will check to see if the current player has run out of time
+		// Reschedule the check to see if the current player has run out of time
 		// (since it just got to be their turn)
-		go g.CheckTimer(ctx, nextPlayer.Time, g.Turn, g.PauseCount, nextPlayer)
+		g.StartTimer(ctx)
 
 		// If the next player queued a pause command, then pause the game
 		if nextPlayer.RequestedPause {
@@ -218,207 +195,7 @@ func action(ctx context.Context, s *Session, d *CommandData, t *Table, p *GamePl
 			})
 		}
 	}
-}
-
-func commandActionPlay(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
-	// Validate "Detrimental Character Assignment" restrictions
-	if characterCheckPlay(s, d, g, p) {
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate deck plays
-	if g.Options.DeckPlays &&
-		g.DeckIndex == len(g.Deck)-1 && // There is 1 card left in the deck
-		d.Target == g.DeckIndex { // The target is the last card left in the deck
-
-		p.PlayDeck()
-		return true
-	}
-
-	// Validate that the card is in their hand
-	if !p.InHand(d.Target) {
-		s.Warning("You cannot play a card that is not in your hand.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	c := p.RemoveCard(d.Target)
-	p.PlayCard(c)
-	p.DrawCard()
-
-	return true
-}
-
-func commandActionDiscard(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
-	// Local variables
-	variant := variants[g.Options.VariantName]
-
-	// Validate that the card is in their hand
-	if !p.InHand(d.Target) {
-		s.Warning("You cannot play a card that is not in your hand.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate that the team is not at the maximum amount of clues
-	if variant.AtMaxClueTokens(g.ClueTokens) {
-		s.Warning("You cannot discard while the team has " + strconv.Itoa(MaxClueNum) + " clues.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate "Detrimental Character Assignment" restrictions
-	if characterCheckDiscard(s, g, p) {
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	g.ClueTokens++
-	c := p.RemoveCard(d.Target)
-	p.DiscardCard(c)
-	p.DrawCard()
-
-	return true
-}
-
-func commandActionClue(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
-	// Local variables
-	variant := variants[g.Options.VariantName]
-
-	// Validate that the target of the clue is sane
-	if d.Target < 0 || d.Target > len(g.Players)-1 {
-		s.Warning("That is an invalid clue target.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate that the player is not giving a clue to themselves
-	if g.ActivePlayerIndex == d.Target {
-		s.Warning("You cannot give a clue to yourself.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate that there are clues available to use
-	if g.ClueTokens < variant.GetAdjustedClueTokens(1) {
-		s.Warning("You need at least 1 clue token available in order to give a clue.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Convert the incoming data to a clue object
-	clue := NewClue(d)
-
-	// Validate the clue value
-	if clue.Type == ClueTypeColor {
-		if clue.Value < 0 || clue.Value > len(variant.ClueColors)-1 {
-			s.Warning("You cannot give a color clue with a value of " +
-				"\"" + strconv.Itoa(clue.Value) + "\".")
-			g.InvalidActionOccurred = true
-			return false
-		}
-	} else if clue.Type == ClueTypeRank {
-		if !intInSlice(clue.Value, variant.ClueRanks) {
-			s.Warning("You cannot give a rank clue with a value of " +
-				"\"" + strconv.Itoa(clue.Value) + "\".")
-			g.InvalidActionOccurred = true
-			return false
-		}
-	} else {
-		s.Warning("The clue type of " + strconv.Itoa(clue.Type) + " is invalid..")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate special variant restrictions
-	if variant.IsAlternatingClues() && clue.Type == g.LastClueTypeGiven {
-		s.Warning("You cannot give two clues of the same time in a row in this variant.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate "Detrimental Character Assignment" restrictions
-	if characterValidateClue(s, d, g, p) {
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Validate that the clue touches at least one card
-	p2 := g.Players[d.Target] // The target of the clue
-	touchedAtLeastOneCard := false
-	for _, c := range p2.Hand {
-		// Prevent characters from cluing cards that they are not supposed to see
-		if !characterSeesCard(g, p, p2, c.Order) {
-			continue
-		}
-
-		if variantIsCardTouched(g.Options.VariantName, clue, c) {
-			touchedAtLeastOneCard = true
-			break
-		}
-	}
-	if !touchedAtLeastOneCard &&
-		// Make an exception if they have the optional setting for "Empty Clues" turned on
-		!g.Options.EmptyClues &&
-		// Make an exception for variants where color clues are always allowed
-		(!variant.ColorCluesTouchNothing || clue.Type != ClueTypeColor) &&
-		// Make an exception for variants where rank clues are always allowed
-		(!variant.RankCluesTouchNothing || clue.Type != ClueTypeRank) {
-
-		s.Warning("You cannot give a clue that touches 0 cards in the hand.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	p.GiveClue(d)
-
-	return true
-}
-
-func commandActionEndGame(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
-	// An "endGame" action is a special action type sent by the server to itself
-	// The value will correspond to the end condition (see "endCondition" in "constants.go")
-	// The target will correspond to the index of the player who ended the game
-
-	// Validate the value
-	if d.Value != EndConditionTimeout &&
-		d.Value != EndConditionTerminated &&
-		d.Value != EndConditionIdleTimeout {
-
-		s.Warning("That is not a valid value for the end game action.")
-		g.InvalidActionOccurred = true
-		return false
-	}
-
-	// Mark that the game should be ended
-	g.EndCondition = d.Value
-	g.EndPlayer = d.Target
-
-	// Insert an "end game" action
-	var endGameAction *GameAction
-	if g.EndCondition == EndConditionTimeout {
-		endGameAction = &GameAction{
-			Type:   ActionTypeEndGame,
-			Target: g.EndPlayer,
-			Value:  EndConditionTimeout,
-		}
-	} else if g.EndCondition == EndConditionTerminated {
-		endGameAction = &GameAction{
-			Type:   ActionTypeEndGame,
-			Target: g.EndPlayer,
-			Value:  EndConditionTerminated,
-		}
-	} else if g.EndCondition == EndConditionIdleTimeout {
-		endGameAction = &GameAction{
-			Type:   ActionTypeEndGame,
-			Target: -1,
-			Value:  EndConditionIdleTimeout,
-		}
-	}
-	if endGameAction != nil {
-		g.Actions2 = append(g.Actions2, endGameAction)
-	}
 
-	return true
+	// If the next player is a bot that runs inside of the server, it will need to act
+	g.CheckBotTurn(ctx)
 }
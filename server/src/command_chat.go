@@ -46,9 +46,14 @@ func commandChat(ctx context.Context, s *Session, d *CommandData) {
 		d.Username = s.Username
 	}
 
-	// Check to see if their IP has been muted
-	if s != nil && s.Muted {
-		s.Warning("You have been muted by an administrator.")
+	// Private commands are handled before the message is sent to the room,
+	// so that the other users do not see them (and so that muted users can still use them)
+	if s != nil && !d.Server && !d.Discord && chatPrivateCommand(ctx, s, d) {
+		return
+	}
+
+	// Check to see if their account or their IP has been muted
+	if s != nil && !d.Server && isMuted(s) {
 		return
 	}
 
@@ -118,10 +123,13 @@ func chat(ctx context.Context, s *Session, d *CommandData, userID int, rawMsg st
 		}
 	}
 
-	// Lobby messages go to everyone
+	// Lobby messages go to everyone (except for the users who have blocked the sender)
 	if !d.OnlyDiscord {
 		sessionList := sessions.GetList()
 		for _, s2 := range sessionList {
+			if _, ok := s2.Blocks()[userID]; userID != 0 && ok {
+				continue
+			}
 			s2.Emit("chat", &ChatMessage{
 				Msg:       d.Msg,
 				Who:       d.Username,
@@ -194,14 +202,20 @@ func commandChatTable(ctx context.Context, s *Session, d *CommandData) {
 		userID = s.UserID
 	}
 	chatMsg := &TableChatMessage{
-		UserID:   userID,
-		Username: d.Username, // This was prepared above in the "commandChat()" function
-		Msg:      d.Msg,
-		Datetime: time.Now(),
-		Server:   d.Server,
+		UserID:            userID,
+		Username:          d.Username, // This was prepared above in the "commandChat()" function
+		Msg:               d.Msg,
+		Datetime:          time.Now(),
+		Server:            d.Server,
+		HiddenFromPlayers: !d.Server && t.isHiddenFromPlayers(spectatorIndex),
 	}
 	t.Chat = append(t.Chat, chatMsg)
 
+	// The discussion in a shared replay is kept alongside the game
+	if t.Replay && !d.Server {
+		t.SaveReplayAnnotation(s, t.Game.State.Turn, ReplayAnnotationTypeChat, d.Msg)
+	}
+
 	// Send it to all of the players and spectators
 	t.NotifyChat(&ChatMessage{
 		Msg:       d.Msg,
@@ -211,7 +225,7 @@ func commandChatTable(ctx context.Context, s *Session, d *CommandData) {
 		Datetime:  chatMsg.Datetime,
 		Room:      d.Room,
 		Recipient: "",
-	})
+	}, chatMsg.HiddenFromPlayers)
 
 	// Check for commands
 	chatCommand(ctx, s, d, t)
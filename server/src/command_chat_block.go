@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// commandChatBlock is sent when a user blocks another user
+// (it is also sent on behalf of the user when they type "/block [username]")
+// Blocked users cannot send lobby messages or private messages to the user,
+// and they cannot join the tables that the user owns
+//
+// Example data:
+// {
+//   name: 'Alice',
+// }
+func commandChatBlock(ctx context.Context, s *Session, d *CommandData) {
+	block(s, d, true)
+}
+
+// commandChatUnblock is sent when a user unblocks another user
+// (it is also sent on behalf of the user when they type "/unblock [username]")
+//
+// Example data:
+// {
+//   name: 'Alice',
+// }
+func commandChatUnblock(ctx context.Context, s *Session, d *CommandData) {
+	block(s, d, false)
+}
+
+// chatBlock is a private chat command, so the blocked user does not see it
+// (see "chatPrivateCommand()")
+func chatBlock(ctx context.Context, s *Session, d *CommandData) {
+	commandChatBlock(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		Name: strings.Join(d.Args, " "),
+		Room: d.Room,
+	})
+}
+
+// chatUnblock is a private chat command, so the blocked user does not see it
+// (see "chatPrivateCommand()")
+func chatUnblock(ctx context.Context, s *Session, d *CommandData) {
+	commandChatUnblock(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		Name: strings.Join(d.Args, " "),
+		Room: d.Room,
+	})
+}
+
+func block(s *Session, d *CommandData, add bool) {
+	// Validate that they sent a username
+	if len(d.Name) == 0 {
+		var msg string
+		if add {
+			msg = "The format of the /block command is: /block [username]"
+		} else {
+			msg = "The format of the /unblock command is: /unblock [username]"
+		}
+		chatServerSendPM(s, msg, d.Room)
+		blockList(s, d)
+		return
+	}
+
+	normalizedUsername := normalizeString(d.Name)
+
+	// Validate that they did not target themselves
+	if normalizedUsername == normalizeString(s.Username) {
+		var verb string
+		if add {
+			verb = "block"
+		} else {
+			verb = "unblock"
+		}
+		s.Warning("You cannot " + verb + " yourself.")
+		return
+	}
+
+	// Validate that this person exists in the database
+	var blocked User
+	if exists, v, err := models.Users.GetUserFromNormalizedUsername(
+		normalizedUsername,
+	); err != nil {
+		logger.Error("Failed to validate that \"" + normalizedUsername + "\" " +
+			"exists in the database: " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else if !exists {
+		s.Warning("The username of \"" + d.Name + "\" does not exist in the database.")
+		return
+	} else {
+		blocked = v
+	}
+
+	blockMap := s.Blocks()
+	s2, blockedIsOnline := sessions.Get(blocked.ID)
+
+	var msg string
+	if add {
+		// Validate that this user is not already blocked
+		if _, ok := blockMap[blocked.ID]; ok {
+			s.Warning("You have already blocked \"" + blocked.Username + "\".")
+			return
+		}
+
+		// Add the block
+		if err := models.UserBlocks.Insert(s.UserID, blocked.ID); err != nil {
+			logger.Error("Failed to insert a new block for user \"" + s.Username + "\": " +
+				err.Error())
+			s.Error(DefaultErrorMsg)
+			return
+		}
+		s.AddBlock(blocked.ID)
+
+		// Add the reverse block (e.g. the inverse relationship)
+		if err := models.UserReverseBlocks.Insert(blocked.ID, s.UserID); err != nil {
+			logger.Error("Failed to insert a new reverse block for user \"" + s.Username + "\": " +
+				err.Error())
+			s.Error(DefaultErrorMsg)
+			return
+		}
+		if blockedIsOnline {
+			s2.AddReverseBlock(s.UserID)
+		}
+
+		msg = "Successfully blocked \"" + blocked.Username + "\". You will no longer see their " +
+			"messages in the lobby or their private messages, and they cannot join your tables. " +
+			"(They are not notified.)"
+	} else {
+		// Validate that this user is blocked
+		if _, ok := blockMap[blocked.ID]; !ok {
+			s.Warning("\"" + blocked.Username + "\" is not blocked, so you cannot unblock them.")
+			return
+		}
+
+		// Remove the block
+		if err := models.UserBlocks.Delete(s.UserID, blocked.ID); err != nil {
+			logger.Error("Failed to delete a block for user \"" + s.Username + "\": " + err.Error())
+			s.Error(DefaultErrorMsg)
+			return
+		}
+		s.DeleteBlock(blocked.ID)
+
+		// Remove the reverse block (e.g. the inverse relationship)
+		if err := models.UserReverseBlocks.Delete(blocked.ID, s.UserID); err != nil {
+			logger.Error("Failed to delete a reverse block for user \"" + s.Username + "\": " +
+				err.Error())
+			s.Error(DefaultErrorMsg)
+			return
+		}
+		if blockedIsOnline {
+			s2.DeleteReverseBlock(s.UserID)
+		}
+
+		msg = "Successfully unblocked \"" + blocked.Username + "\"."
+	}
+	chatServerSendPM(s, msg, d.Room)
+}
+
+// blockList sends the user a private message with the users that they have blocked
+func blockList(s *Session, d *CommandData) {
+	var blocks []string
+	if v, err := models.UserBlocks.GetAllUsernames(s.UserID); err != nil {
+		logger.Error("Failed to get the blocks for user \"" + s.Username + "\": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		blocks = v
+	}
+
+	var msg string
+	if len(blocks) == 0 {
+		msg = "You have not blocked anyone."
+	} else {
+		msg = "Blocked users: " + strings.Join(blocks, ", ")
+	}
+	chatServerSendPM(s, msg, d.Room)
+}
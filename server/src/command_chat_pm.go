@@ -14,9 +14,8 @@ import (
 //   recipient: 'Alice',
 // }
 func commandChatPM(ctx context.Context, s *Session, d *CommandData) {
-	// Check to see if their IP has been muted
-	if s != nil && s.Muted {
-		s.Warning("You have been muted by an administrator.")
+	// Check to see if their account or their IP has been muted
+	if s != nil && isMuted(s) {
 		return
 	}
 
@@ -86,5 +85,9 @@ func chatPM(s *Session, d *CommandData, recipientSession *Session) {
 	s.Emit("chat", chatMessage)
 
 	// Send the private message to the recipient
+	// (the sender is not told if the recipient has blocked them)
+	if _, ok := recipientSession.Blocks()[s.UserID]; ok {
+		return
+	}
 	recipientSession.Emit("chat", chatMessage)
 }
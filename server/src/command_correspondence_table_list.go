@@ -0,0 +1,13 @@
+package main
+
+import (
+	"context"
+)
+
+// commandCorrespondenceTableList is sent when the user opens the list of their correspondence games
+// (it is also automatically sent to them when they log in)
+//
+// Has no data
+func commandCorrespondenceTableList(ctx context.Context, s *Session, d *CommandData) {
+	sendCorrespondenceTableList(ctx, s)
+}
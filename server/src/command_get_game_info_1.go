@@ -156,7 +156,7 @@ func getGameInfo1(s *Session, t *Table, playerIndex int, spectatorIndex int) {
 		// Shared replay settings
 		SharedReplay:        t.Replay && t.Visible,
 		SharedReplayLeader:  t.GetSharedReplayLeaderName(),
-		SharedReplaySegment: g.Turn,
+		SharedReplaySegment: g.State.Turn,
 		SharedReplayEffMod:  g.EfficiencyMod,
 
 		// Other features
@@ -46,7 +46,12 @@ func getGameInfo2(s *Session, t *Table, playerIndex int, spectatorIndex int) {
 	// Check to see if we need to remove some card information
 	scrubbedActions := make([]interface{}, 0)
 	if !t.Replay {
-		for _, action := range g.Actions {
+		actions := g.Actions
+		if spectatorIndex > -1 {
+			// The game might be shown to spectators after a delay
+			actions = t.GetSpectatorActions()
+		}
+		for _, action := range actions {
 			scrubbedAction := CheckScrub(t, action, s.UserID)
 			scrubbedActions = append(scrubbedActions, scrubbedAction)
 		}
@@ -80,7 +85,10 @@ func getGameInfo2(s *Session, t *Table, playerIndex int, spectatorIndex int) {
 		s.NotifyConnected(t)
 
 		// Send them the current time for all player's clocks
-		s.NotifyTime(t)
+		// (the clocks would show the spectators of a delayed game whose turn it is)
+		if playerIndex > -1 || !t.HasSpectatorDelay() {
+			s.NotifyTime(t)
+		}
 
 		if playerIndex > -1 {
 			// They are a player in an ongoing game
@@ -115,21 +123,22 @@ func getGameInfo2(s *Session, t *Table, playerIndex int, spectatorIndex int) {
 				s.NotifyChatTyping(t, p.Name, p.Typing)
 			}
 		}
-		for _, sp := range t.Spectators {
-			if sp.Typing {
+		for i, sp := range t.Spectators {
+			if sp.Typing && (playerIndex == -1 || !t.isHiddenFromPlayers(i)) {
 				s.NotifyChatTyping(t, sp.Name, sp.Typing)
 			}
 		}
 	}
 
+	if t.Replay && len(g.ReplayAnnotations) > 0 {
+		s.NotifyReplayAnnotations(t)
+	}
+
+	if t.Replay && g.Analysis != nil {
+		s.NotifyGameAnalysis(t)
+	}
+
 	if g.Hypothetical {
-		type HypotheticalMessage struct {
-			ShowDrawnCards bool     `json:"showDrawnCards"`
-			Actions        []string `json:"actions"`
-		}
-		s.Emit("hypothetical", &HypotheticalMessage{
-			ShowDrawnCards: g.HypoShowDrawnCards,
-			Actions:        g.HypoActions,
-		})
+		s.NotifyHypothetical(t)
 	}
 }
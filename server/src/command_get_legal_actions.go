@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// commandGetLegalActions is sent when a client or a bot wants to know every action that the rules
+// allow (so that they do not have to re-implement the rules themselves)
+// In an ongoing game, the legal actions are for the player that sent the command
+// (and will be empty if it is not their turn)
+// In a replay, the legal actions are for the active player at the provided segment
+// (and if there is a hypothetical in a shared replay, it will be applied on top of the segment)
+//
+// Example data:
+// {
+//   tableID: 5,
+//   segment: 10, // Only used in replays
+// }
+func commandGetLegalActions(ctx context.Context, s *Session, d *CommandData) {
+	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
+	if !exists {
+		return
+	}
+	if !d.NoTableLock {
+		defer t.Unlock(ctx)
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.Warning(NotStartedFail)
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID)
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID)
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the legal actions for it.")
+		return
+	}
+
+	getLegalActions(s, d, t, playerIndex)
+}
+
+func getLegalActions(s *Session, d *CommandData, t *Table, playerIndex int) {
+	// Local variables
+	g := t.Game
+
+	var state *engine.State
+	if !t.Replay {
+		// The legal actions can reveal information about other players' hands,
+		// so spectators of an ongoing game are not allowed to see them
+		if playerIndex == -1 {
+			s.Warning("You are not playing in this game, so you cannot get the legal actions.")
+			return
+		}
+		state = g.State
+	} else {
+		if g.Hypothetical {
+			if v, _, err := g.GetHypotheticalState(); err != nil {
+				logger.Error(t.GetName() + "Failed to get the hypothetical state: " + err.Error())
+				s.Error(DefaultErrorMsg)
+				return
+			} else {
+				state = v
+			}
+		} else {
+			if v, err := g.GetStateAtSegment(d.Segment); err != nil {
+				logger.Error(t.GetName() + "Failed to get the state at segment " +
+					strconv.Itoa(d.Segment) + ": " + err.Error())
+				s.Error(DefaultErrorMsg)
+				return
+			} else {
+				state = v
+			}
+		}
+		playerIndex = state.ActivePlayerIndex
+	}
+
+	type LegalActionsMessage struct {
+		TableID     uint64         `json:"tableID"`
+		PlayerIndex int            `json:"playerIndex"`
+		List        []*LegalAction `json:"list"`
+	}
+	s.Emit("legalActions", &LegalActionsMessage{
+		TableID:     t.ID,
+		PlayerIndex: playerIndex,
+		List:        state.LegalActions(playerIndex),
+	})
+}
@@ -41,7 +41,7 @@ func commandLoaded(ctx context.Context, s *Session, d *CommandData) {
 	t.NotifyConnected()
 
 	// Start the timer if this is the first player
-	if !g.StartedTimer && playerIndex == g.ActivePlayerIndex {
+	if !g.StartedTimer && playerIndex == g.State.ActivePlayerIndex {
 		g.StartedTimer = true
 		g.DatetimeTurnBegin = time.Now()
 
@@ -50,9 +50,6 @@ func commandLoaded(ctx context.Context, s *Session, d *CommandData) {
 		t.NotifyTime()
 
 		// Start the countdown for when the active player runs out of time
-		if t.Options.Timed && !t.ExtraOptions.NoWriteToDatabase {
-			activePlayer := g.Players[g.ActivePlayerIndex]
-			go g.CheckTimer(ctx, activePlayer.Time, g.Turn, g.PauseCount, activePlayer)
-		}
+		g.StartTimer(ctx)
 	}
 }
@@ -104,5 +104,10 @@ func note(d *CommandData, t *Table, playerIndex int, spectatorIndex int) {
 	}
 
 	// Let all of the spectators know that there is a new note
+	// (the notes of the players are held back from the spectators until the spectator delay is
+	// over; they will get every note when the game ends)
+	if playerIndex > -1 && t.HasSpectatorDelay() {
+		return
+	}
 	t.NotifySpectatorsNote(d.Order)
 }
@@ -14,6 +14,7 @@ import (
 //   setting: 'pause', // Can also be 'unpause', 'pause-queue', 'pause-unqueue'
 //   // ('pause-queue' will automatically pause the game when it gets to their turn)
 // }
+// (if the table requires votes, this proposes a vote to pause or unpause instead)
 func commandPause(ctx context.Context, s *Session, d *CommandData) {
 	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
 	if !exists {
@@ -52,7 +53,7 @@ func commandPause(ctx context.Context, s *Session, d *CommandData) {
 	}
 
 	// If a player requests a queued pause on their turn, turn it into a normal pause
-	if d.Setting == "pause-queue" && g.ActivePlayerIndex == playerIndex {
+	if d.Setting == "pause-queue" && g.State.ActivePlayerIndex == playerIndex {
 		d.Setting = "pause"
 	}
 
@@ -82,6 +83,17 @@ func commandPause(ctx context.Context, s *Session, d *CommandData) {
 		return
 	}
 
+	// Tables that require votes do not let a single player pause or unpause the game
+	// ("Server" is set once the vote has passed)
+	if t.Options.VoteRequired && !d.Server {
+		if d.Setting == "pause-queue" || d.Setting == "pause-unqueue" {
+			s.Warning("This table requires a vote to pause, so you cannot queue a pause.")
+			return
+		}
+		startVote(ctx, s, d, t, d.Setting)
+		return
+	}
+
 	pause(ctx, s, d, t, playerIndex)
 }
 
@@ -104,24 +116,31 @@ func pause(ctx context.Context, s *Session, d *CommandData, t *Table, playerInde
 		g.PausePlayerIndex = playerIndex
 		g.PauseCount++
 
-		// Decrement the time that the player has taken so far prior to this pause
-		p.Time -= time.Since(g.DatetimeTurnBegin)
+		// Record the time that the player has taken so far prior to this pause
+		// (it will be taken off of their clock at the end of their turn)
+		g.TurnTimeTaken += time.Since(g.DatetimeTurnBegin)
+
+		// Players cannot run out of time while the game is paused
+		g.StopTimer()
 	} else if d.Setting == "unpause" {
 		g.Paused = false
 		g.PausePlayerIndex = -1
 
 		// Technically, a players turn should not begin when the game is unpaused,
-		// but this variable is only used for decrementing time taken at the end of a player's turn
+		// but this variable is only used for measuring the time taken on the current turn
 		g.DatetimeTurnBegin = time.Now()
 
-		// Restart the function that will check to see if the current player has run out of time
-		// (the old "CheckTimer()" invocation will return and do nothing because the pause count of
-		// the game will not match)
-		activePlayer := g.Players[g.ActivePlayerIndex]
-		go g.CheckTimer(ctx, activePlayer.Time, g.Turn, g.PauseCount, activePlayer)
+		// Reschedule the check to see if the current player has run out of time
+		// (if the old check already fired, it will do nothing because the pause count of the game
+		// will not match)
+		g.StartTimer(ctx)
+
+		// If it is a bot's turn, the bot will need to act
+		g.CheckBotTurn(ctx)
 	}
 
 	t.NotifyPause()
+	t.SaveCorrespondence()
 
 	// Also send a chat message about it
 	msg := s.Username + " "
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"strconv"
+)
+
+type PuzzleListMessage struct {
+	Puzzles []*Puzzle `json:"puzzles"`
+	// Only contains the puzzles that the user has attempted
+	Progress map[int]*UserPuzzle `json:"progress"`
+}
+
+// commandPuzzleList is sent when the user wants to see the list of puzzles
+//
+// Has no data
+func commandPuzzleList(ctx context.Context, s *Session, d *CommandData) {
+	var puzzles []*Puzzle
+	if v, err := models.Puzzles.GetAll(); err != nil {
+		logger.Error("Failed to get the puzzles: " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		puzzles = v
+	}
+
+	var progress map[int]*UserPuzzle
+	if v, err := models.UserPuzzles.GetAll(s.UserID); err != nil {
+		logger.Error("Failed to get the puzzle progress for user " + strconv.Itoa(s.UserID) +
+			": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		progress = v
+	}
+
+	s.Emit("puzzleList", &PuzzleListMessage{
+		Puzzles:  puzzles,
+		Progress: progress,
+	})
+}
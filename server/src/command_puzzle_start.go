@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"strconv"
+)
+
+// commandPuzzleStart is sent when the user wants to attempt a puzzle
+// (it is also sent on behalf of the user when they type "/puzzle [puzzle ID]" in the lobby)
+// The user is put into a game that has been emulated up to the turn of the puzzle,
+// with fake players in the other seats
+//
+// Example data:
+// {
+//   puzzleID: 5,
+// }
+func commandPuzzleStart(ctx context.Context, s *Session, d *CommandData) {
+	var puzzle *Puzzle
+	if v, exists, err := models.Puzzles.Get(d.PuzzleID); err != nil {
+		logger.Error("Failed to get puzzle " + strconv.Itoa(d.PuzzleID) + ": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else if !exists {
+		s.Warning("Puzzle #" + strconv.Itoa(d.PuzzleID) + " does not exist.")
+		return
+	} else {
+		puzzle = v
+	}
+
+	puzzleStart(ctx, s, d, puzzle)
+}
+
+func puzzleStart(ctx context.Context, s *Session, d *CommandData, puzzle *Puzzle) {
+	// Since this is a function that changes a user's relationship to tables,
+	// we must acquires the tables lock to prevent race conditions
+	if !d.NoTablesLock {
+		tables.Lock(ctx)
+		defer tables.Unlock(ctx)
+	}
+
+	// Validate that the player is not joined to another table
+	// (this cannot be in the "commandPuzzleStart()" function because we need the tables lock)
+	if len(tables.GetTablesUserPlaying(s.UserID)) > 0 ||
+		len(tables.GetTablesUserSpectating(s.UserID)) > 0 {
+
+		s.Warning("You cannot attempt a puzzle while you are at another table. " +
+			"Leave your other table first.")
+		return
+	}
+
+	// Create a table that only the user can see
+	t := NewTable("Puzzle #"+strconv.Itoa(puzzle.ID), s.UserID)
+	t.Lock(ctx)
+	defer t.Unlock(ctx)
+	t.Visible = false
+
+	var dbPlayers []*DBPlayer
+	if v, success := loadDatabaseOptionsToTable(s, puzzle.GameID, t); !success {
+		return
+	} else {
+		dbPlayers = v
+	}
+
+	// Puzzles are not a race against the clock and they only last for one move
+	t.Options.Timed = false
+	t.Options.Correspondence = false
+
+	// "loadDatabaseOptionsToTable()" sets the database ID to a positive number
+	// The database ID for an ongoing game should be set to -1
+	// (the game is still not written to the database because "NoWriteToDatabase" is set)
+	t.ExtraOptions.DatabaseID = -1
+
+	// "loadDatabaseOptionsToTable()" does not specify the "!replay" options
+	t.ExtraOptions.SetReplay = true
+	t.ExtraOptions.SetReplayTurn = puzzle.Turn
+	t.ExtraOptions.PuzzleID = puzzle.ID
+
+	playerNames := make([]string, 0)
+	for _, dbPlayer := range dbPlayers {
+		playerNames = append(playerNames, dbPlayer.Name)
+	}
+	loadFakePlayers(t, playerNames)
+
+	// The user takes the seat of the player that has to make the move
+	// (but they keep the original name so that the game looks the same as it did)
+	if puzzle.PlayerIndex < 0 || puzzle.PlayerIndex >= len(t.Players) {
+		logger.Error("Puzzle " + strconv.Itoa(puzzle.ID) + " has an invalid player index of " +
+			strconv.Itoa(puzzle.PlayerIndex) + ".")
+		s.Error(InitGameFail)
+		return
+	}
+	p := t.Players[puzzle.PlayerIndex]
+	p.UserID = s.UserID
+	p.Session = s
+
+	// Add the table to a map so that we can keep track of all of the active tables
+	tables.Set(t.ID, t)
+	tables.AddPlaying(s.UserID, t.ID)
+
+	logger.Info("User \"" + s.Username + "\" started puzzle #" + strconv.Itoa(puzzle.ID) + ".")
+
+	// Emulate the game up to the turn of the puzzle
+	// (this sends the user the "tableStart" message)
+	commandTableStart(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		TableID:      t.ID,
+		NoTableLock:  true,
+		NoTablesLock: true,
+	})
+	g := t.Game
+	if g == nil || g.InvalidActionOccurred ||
+		g.State.ActivePlayerIndex != puzzle.PlayerIndex ||
+		len(g.Actions2) != puzzle.Turn {
+
+		logger.Error("Failed to emulate the game up to the turn of puzzle " +
+			strconv.Itoa(puzzle.ID) + ".")
+		s.Error(InitGameFail)
+		tables.DeletePlaying(s.UserID, t.ID)
+		deleteTable(t)
+		return
+	}
+
+	s.SetStatus(StatusPlaying)
+	s.SetTableID(t.ID)
+	notifyAllUser(s)
+
+	// After the client receives the "tableStart" message, they will send a "getGameInfo1" command
+	// to begin the process of loading the UI and putting them in the game
+}
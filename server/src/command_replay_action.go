@@ -4,15 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"math"
-	"regexp"
 	"strconv"
-)
 
-var (
-	replayActionFunctions map[int]func(*Session, *CommandData, *Table)
-	actionTypeTurnRegExp  = regexp.MustCompile(`"type":\s*"turn"`)
+	"github.com/Zamiell/hanabi-live/engine"
 )
 
+var replayActionFunctions map[int]func(*Session, *CommandData, *Table)
+
 func replayActionsFunctionsInit() {
 	replayActionFunctions = map[int]func(*Session, *CommandData, *Table){
 		ReplayActionTypeSegment:        replayActionSegment,
@@ -24,6 +22,10 @@ func replayActionsFunctionsInit() {
 		ReplayActionTypeHypoBack:       replayActionHypoBack,
 		ReplayActionTypeToggleRevealed: replayActionToggleRevealed,
 		ReplayActionTypeEfficiencyMod:  replayActionEfficiencyMod,
+		ReplayActionTypeHypoBranch:     replayActionHypoBranch,
+		ReplayActionTypeHypoSwitch:     replayActionHypoSwitch,
+		ReplayActionTypeHypoSave:       replayActionHypoSave,
+		ReplayActionTypeHypoLoad:       replayActionHypoLoad,
 	}
 }
 
@@ -34,7 +36,7 @@ func replayActionsFunctionsInit() {
 //   tableID: 5,
 //   type: 0, // Types are listed in the "constants.go" file
 //   value: 10, // Optional
-//   name: 'Alice', // Optional
+//   name: 'Alice', // Optional (also the name of a hypothetical branch or a saved hypothetical)
 // }
 func commandReplayAction(ctx context.Context, s *Session, d *CommandData) {
 	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
@@ -86,7 +88,7 @@ func replayActionSegment(s *Session, d *CommandData, t *Table) {
 
 	// Change the segment
 	// (we borrow the turn variable to use as a stand-in for the current shared replay segment)
-	g.Turn = d.Segment
+	g.State.Turn = d.Segment
 
 	// Notify everyone
 	type ReplaySegmentMessage struct {
@@ -102,7 +104,7 @@ func replayActionSegment(s *Session, d *CommandData, t *Table) {
 	}
 
 	// Update the progress
-	progressFloat := float64(g.Turn) / float64(g.EndTurn) * 100 // In percent
+	progressFloat := float64(g.State.Turn) / float64(g.State.EndTurn) * 100 // In percent
 	progress := int(math.Round(progressFloat))
 	if progress > 100 {
 		// The server has no notion of game segments, it knows about the total number of turns
@@ -123,6 +125,9 @@ func replayActionSegment(s *Session, d *CommandData, t *Table) {
 }
 
 func replayActionArrow(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
 	// Display an arrow to indicate a specific card that the shared replay leader wants to draw
 	// attention to
 	// The server does not know what a particular order value corresponds to;
@@ -138,6 +143,11 @@ func replayActionArrow(s *Session, d *CommandData, t *Table) {
 	for _, sp := range t.Spectators {
 		sp.Session.Emit("replayIndicator", replayIndicatorMessage)
 	}
+
+	// An order of -1 means that the arrow was removed
+	if d.Order >= 0 {
+		t.SaveReplayAnnotation(s, g.State.Turn, ReplayAnnotationTypeArrow, strconv.Itoa(d.Order))
+	}
 }
 
 func replayActionSound(s *Session, d *CommandData, t *Table) {
@@ -164,10 +174,19 @@ func replayActionHypoStart(s *Session, d *CommandData, t *Table) {
 		return
 	}
 
-	// Start a hypothetical line
+	// Start a hypothetical line from the current segment
+	// (we borrow the turn variable to use as a stand-in for the current shared replay segment)
 	g.Hypothetical = true
 	g.HypoShowDrawnCards = false
+	g.HypoBranches = map[string]*HypoBranch{
+		DefaultHypoBranchName: NewHypoBranch(DefaultHypoBranchName, nil, g.State.Turn),
+	}
+	g.HypoCurrentBranch = DefaultHypoBranchName
+
+	notifyHypoStart(t)
+}
 
+func notifyHypoStart(t *Table) {
 	type HypoStartMessage struct {
 		TableID uint64
 	}
@@ -189,8 +208,10 @@ func replayActionHypoEnd(s *Session, d *CommandData, t *Table) {
 	}
 
 	// End a hypothetical line
+	t.SaveReplayHypothetical(s)
 	g.Hypothetical = false
-	g.HypoActions = make([]string, 0)
+	g.HypoBranches = make(map[string]*HypoBranch)
+	g.HypoCurrentBranch = ""
 
 	type HypoEndMessage struct {
 		TableID uint64
@@ -207,24 +228,64 @@ func replayActionHypoAction(s *Session, d *CommandData, t *Table) {
 	// Local variables
 	g := t.Game
 
+	if !g.Hypothetical {
+		s.Warning("You are not in a hypothetical, so you cannot perform a hypothetical action.")
+		return
+	}
+
 	// Validate that the submitted action is not empty
 	if d.ActionJSON == "" {
 		s.Warning("The action JSON cannot be blank.")
 		return
 	}
 
-	// Test to see if it is valid JSON
-	var js json.RawMessage
-	if json.Unmarshal([]byte(d.ActionJSON), &js) != nil {
+	var a *GameAction
+	if v, err := parseHypoAction(d.ActionJSON); err != nil {
 		s.Warning("That is not a valid JSON object.")
 		return
+	} else if v == nil {
+		// This is an event that is a consequence of an action (e.g. a "draw" or a "turn"),
+		// which the server generates by itself
+		return
+	} else {
+		a = v
+	}
+
+	// Simulate the move with the real rules of the game
+	var state *engine.State
+	if v, _, err := g.GetHypotheticalState(); err != nil {
+		logger.Error(t.GetName() + "Failed to get the hypothetical state: " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		state = v
+	}
+	var events []engine.Event
+	if _, v, err := state.Apply(a); err != nil {
+		s.Warning("That is not a legal move in the hypothetical: " + err.Error())
+		return
+	} else {
+		events = v
+	}
+
+	var actions []string
+	if v, err := hypoEventsToJSON(events); err != nil {
+		logger.Error(t.GetName() + "Failed to convert the hypothetical events to JSON: " +
+			err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		actions = v
 	}
 
 	// Perform a move in the hypothetical
-	g.HypoActions = append(g.HypoActions, d.ActionJSON)
+	branch := g.GetHypoBranch()
+	branch.Actions = append(branch.Actions, a)
 
-	for _, sp := range t.Spectators {
-		sp.Session.Emit("hypoAction", d.ActionJSON)
+	for _, actionJSON := range actions {
+		for _, sp := range t.Spectators {
+			sp.Session.Emit("hypoAction", actionJSON)
+		}
 	}
 }
 
@@ -232,26 +293,16 @@ func replayActionHypoBack(s *Session, d *CommandData, t *Table) {
 	// Local variables
 	g := t.Game
 
-	// The replay leader wants to go back one turn in the hypothetical
-	if len(g.HypoActions) == 0 {
+	if !g.Hypothetical {
 		return
 	}
 
-	// Starting from the end,
-	// remove hypothetical actions until we get to the 2nd to last "turn" action
-	for {
-		// Delete the final element in the slice
-		g.HypoActions = g.HypoActions[:len(g.HypoActions)-1]
-		if len(g.HypoActions) == 0 {
-			break
-		}
-
-		lastActionString := g.HypoActions[len(g.HypoActions)-1]
-		match := actionTypeTurnRegExp.FindStringSubmatch(lastActionString)
-		if match != nil {
-			break
-		}
+	// The replay leader wants to go back one turn in the hypothetical
+	branch := g.GetHypoBranch()
+	if len(branch.Actions) == 0 {
+		return
 	}
+	branch.Actions = branch.Actions[:len(branch.Actions)-1]
 
 	type HypoBackMessage struct {
 		TableID uint64
@@ -264,6 +315,160 @@ func replayActionHypoBack(s *Session, d *CommandData, t *Table) {
 	}
 }
 
+func replayActionHypoBranch(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	if !g.Hypothetical {
+		s.Warning("You are not in a hypothetical, so you cannot create a new branch.")
+		return
+	}
+
+	name := validateHypoName(s, d.Name)
+	if name == "" {
+		return
+	}
+	if _, ok := g.HypoBranches[name]; ok {
+		s.Warning("There is already a branch named \"" + name + "\".")
+		return
+	}
+	if len(g.HypoBranches) >= MaxHypoBranches {
+		s.Warning("You cannot have more than " + strconv.Itoa(MaxHypoBranches) + " branches.")
+		return
+	}
+
+	// The new branch starts from the current point of the current branch
+	g.HypoBranches[name] = NewHypoBranch(name, g.GetHypoBranch(), 0)
+	g.HypoCurrentBranch = name
+
+	t.NotifyHypothetical()
+}
+
+func replayActionHypoSwitch(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	if !g.Hypothetical {
+		s.Warning("You are not in a hypothetical, so you cannot switch branches.")
+		return
+	}
+
+	if _, ok := g.HypoBranches[d.Name]; !ok {
+		s.Warning("There is no branch named \"" + d.Name + "\".")
+		return
+	}
+
+	g.HypoCurrentBranch = d.Name
+
+	t.NotifyHypothetical()
+}
+
+func replayActionHypoSave(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	if !g.Hypothetical {
+		s.Warning("You are not in a hypothetical, so there is nothing to save.")
+		return
+	}
+
+	if t.ExtraOptions.DatabaseID <= 0 {
+		s.Warning("You can only save hypotheticals for games that are stored in the database.")
+		return
+	}
+
+	name := validateHypoName(s, d.Name)
+	if name == "" {
+		return
+	}
+
+	var branchesJSON []byte
+	if v, err := json.Marshal(g.HypoBranches); err != nil {
+		logger.Error(t.GetName() + "Failed to marshal the hypothetical branches: " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		branchesJSON = v
+	}
+
+	if err := models.GameHypotheticals.Set(
+		t.ExtraOptions.DatabaseID,
+		s.UserID,
+		name,
+		branchesJSON,
+	); err != nil {
+		logger.Error("Failed to save the hypothetical \"" + name + "\" for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	}
+
+	notifyHypoSaveList(s, t)
+}
+
+func replayActionHypoLoad(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	if t.ExtraOptions.DatabaseID <= 0 {
+		s.Warning("You can only load hypotheticals for games that are stored in the database.")
+		return
+	}
+
+	var branchesJSON []byte
+	if v1, v2, err := models.GameHypotheticals.Get(t.ExtraOptions.DatabaseID, d.Name); err != nil {
+		logger.Error("Failed to get the hypothetical \"" + d.Name + "\" for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else if !v2 {
+		s.Warning("There is no saved hypothetical named \"" + d.Name + "\" for this game.")
+		notifyHypoSaveList(s, t)
+		return
+	} else {
+		branchesJSON = v1
+	}
+
+	wasHypothetical := g.Hypothetical
+	if err := g.LoadHypoBranches(branchesJSON); err != nil {
+		logger.Error("Failed to load the hypothetical \"" + d.Name + "\" for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		s.Warning("The saved hypothetical \"" + d.Name + "\" is not valid.")
+		return
+	}
+	g.HypoShowDrawnCards = false
+
+	if !wasHypothetical {
+		notifyHypoStart(t)
+	}
+	t.NotifyHypothetical()
+}
+
+// notifyHypoSaveList sends the names of the hypotheticals that are saved for this game
+func notifyHypoSaveList(s *Session, t *Table) {
+	var names []string
+	if v, err := models.GameHypotheticals.GetNames(t.ExtraOptions.DatabaseID); err != nil {
+		logger.Error("Failed to get the saved hypotheticals for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		names = v
+	}
+
+	type HypoSaveListMessage struct {
+		TableID uint64   `json:"tableID"`
+		Names   []string `json:"names"`
+	}
+	hypoSaveListMessage := &HypoSaveListMessage{
+		TableID: t.ID,
+		Names:   names,
+	}
+	for _, sp := range t.Spectators {
+		sp.Session.Emit("hypoSaveList", hypoSaveListMessage)
+	}
+}
+
 func replayActionToggleRevealed(s *Session, d *CommandData, t *Table) {
 	// Local variables
 	g := t.Game
@@ -299,4 +504,11 @@ func replayActionEfficiencyMod(s *Session, d *CommandData, t *Table) {
 	for _, sp := range t.Spectators {
 		sp.Session.Emit("replayEfficiencyMod", replayEfficiencyModMessage)
 	}
+
+	t.SaveReplayAnnotation(
+		s,
+		g.State.Turn,
+		ReplayAnnotationTypeEfficiencyMod,
+		strconv.Itoa(g.EfficiencyMod),
+	)
 }
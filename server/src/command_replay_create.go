@@ -19,6 +19,7 @@ import (
 //   source: 'id',
 //   databaseID: 15103, // Only if source is "id"
 //   json: '{"actions"=[],"deck"=[]}', // Only if source is "json"
+//   gameText: 'players Alice Bob\ndeck r1 r2 ...', // Only if source is "text"
 //   visibility: 'solo', // Can also be "shared"
 // }
 func commandReplayCreate(ctx context.Context, s *Session, d *CommandData) {
@@ -46,6 +47,21 @@ func commandReplayCreate(ctx context.Context, s *Session, d *CommandData) {
 		if !validateJSON(s, d) {
 			return
 		}
+	} else if d.Source == "text" {
+		// Games in the text notation are converted to JSON and then treated like any other JSON game
+		if v, err := parseGameText(d.GameText); err != nil {
+			s.Warning("That is not a valid game: " + err.Error())
+			return
+		} else {
+			d.GameJSON = v
+		}
+		d.Source = "json"
+		if !validateJSON(s, d) {
+			return
+		}
+	} else {
+		s.Warning("That is not a valid replay source.")
+		return
 	}
 
 	replayCreate(ctx, s, d)
@@ -163,8 +179,8 @@ func replayCreate(ctx context.Context, s *Session, d *CommandData) {
 
 	// Do a mini-version of the steps in the "g.End()" function
 	t.Replay = true
-	g.EndTurn = g.Turn
-	g.Turn = 0 // We want to start viewing the replay at the beginning, not the end
+	g.State.EndTurn = g.State.Turn
+	g.State.Turn = 0 // We want to start viewing the replay at the beginning, not the end
 	t.Progress = 0
 
 	if d.Source == "id" {
@@ -179,6 +195,20 @@ func replayCreate(ctx context.Context, s *Session, d *CommandData) {
 			g.DatetimeStarted = v1
 			g.DatetimeFinished = v2
 		}
+
+		// Restore everything from the previous shared replays of this game
+		if t.Visible && !t.loadReplayAnnotations() {
+			s.Error(InitGameFail)
+			deleteTable(t)
+			return
+		}
+
+		// Show the statistics that were generated at the end of the game
+		if !t.loadAnalysis() {
+			s.Error(InitGameFail)
+			deleteTable(t)
+			return
+		}
 	}
 
 	// Join the user to the new replay
@@ -237,6 +267,11 @@ func validateJSON(s *Session, d *CommandData) bool {
 		variant = v
 	}
 
+	// Validate the custom rules
+	if !validateCustomRules(s, d.GameJSON.GetOptions()) {
+		return false
+	}
+
 	// Validate that there is at least one action
 	if len(d.GameJSON.Actions) < 1 {
 		s.Warning("There must be at least one game action in the JSON array.")
@@ -306,7 +341,7 @@ func validateJSON(s *Session, d *CommandData) bool {
 				" has an invalid suit number of " + strconv.Itoa(card.SuitIndex) + ".")
 			return false
 		}
-		if (card.Rank < 1 || card.Rank > 5) && card.Rank != StartCardRank {
+		if !intInSlice(card.Rank, variant.Ranks) {
 			s.Warning("The card at index " + strconv.Itoa(i) +
 				" has an invalid rank number of " + strconv.Itoa(card.Rank) + ".")
 			return false
@@ -314,8 +349,9 @@ func validateJSON(s *Session, d *CommandData) bool {
 	}
 
 	// Validate the amount of players
-	if len(d.GameJSON.Players) < 2 || len(d.GameJSON.Players) > 6 {
-		s.Warning("The number of players must be between 2 and 6.")
+	if len(d.GameJSON.Players) < MinPlayers || len(d.GameJSON.Players) > MaxPlayers {
+		s.Warning("The number of players must be between " + strconv.Itoa(MinPlayers) + " and " +
+			strconv.Itoa(MaxPlayers) + ".")
 		return false
 	}
 
@@ -433,6 +469,7 @@ func loadDatabaseOptionsToTable(s *Session, databaseID int, t *Table) ([]*DBPlay
 		SetSeedSuffix: "",
 		SetReplay:     false,
 		SetReplayTurn: 0,
+		PuzzleID:      0,
 	}
 
 	return dbPlayers, true
@@ -454,80 +491,9 @@ func getCharacterAssignmentsFromDBPlayers(dbPlayers []*DBPlayer) []*CharacterAss
 }
 
 func loadJSONOptionsToTable(d *CommandData, t *Table) {
-	// In order to avoid "runtime error: invalid memory address or nil pointer dereference",
-	// we must explicitly check to see if all pointers exist
-	startingPlayer := 0
-	if d.GameJSON.Options.StartingPlayer != nil {
-		startingPlayer = *d.GameJSON.Options.StartingPlayer
-	}
-	variantName := "No Variant"
-	if d.GameJSON.Options.Variant != nil {
-		variantName = *d.GameJSON.Options.Variant
-	}
-	timed := false
-	if d.GameJSON.Options.Timed != nil {
-		timed = *d.GameJSON.Options.Timed
-	}
-	timeBase := 0
-	if d.GameJSON.Options.Timed != nil {
-		timeBase = *d.GameJSON.Options.TimeBase
-	}
-	timePerTurn := 0
-	if d.GameJSON.Options.TimePerTurn != nil {
-		timePerTurn = *d.GameJSON.Options.TimePerTurn
-	}
-	speedrun := false
-	if d.GameJSON.Options.Speedrun != nil {
-		speedrun = *d.GameJSON.Options.Speedrun
-	}
-	cardCycle := false
-	if d.GameJSON.Options.CardCycle != nil {
-		cardCycle = *d.GameJSON.Options.CardCycle
-	}
-	deckPlays := false
-	if d.GameJSON.Options.DeckPlays != nil {
-		deckPlays = *d.GameJSON.Options.DeckPlays
-	}
-	emptyClues := false
-	if d.GameJSON.Options.EmptyClues != nil {
-		emptyClues = *d.GameJSON.Options.EmptyClues
-	}
-	oneExtraCard := false
-	if d.GameJSON.Options.OneExtraCard != nil {
-		oneExtraCard = *d.GameJSON.Options.OneExtraCard
-	}
-	oneLessCard := false
-	if d.GameJSON.Options.OneLessCard != nil {
-		oneLessCard = *d.GameJSON.Options.OneLessCard
-	}
-	allOrNothing := false
-	if d.GameJSON.Options.AllOrNothing != nil {
-		allOrNothing = *d.GameJSON.Options.AllOrNothing
-	}
-	detrimentalCharacters := false
-	if d.GameJSON.Options.DetrimentalCharacters != nil {
-		detrimentalCharacters = *d.GameJSON.Options.DetrimentalCharacters
-	}
-
 	// Store the options on the table
 	// (the variant was already validated in the "validateJSON()" function)
-	t.Options = &Options{
-		NumPlayers:            len(d.GameJSON.Players),
-		StartingPlayer:        startingPlayer,
-		VariantID:             variants[variantName].ID,
-		VariantName:           variantName,
-		Timed:                 timed,
-		TimeBase:              timeBase,
-		TimePerTurn:           timePerTurn,
-		Speedrun:              speedrun,
-		CardCycle:             cardCycle,
-		DeckPlays:             deckPlays,
-		EmptyClues:            emptyClues,
-		OneExtraCard:          oneExtraCard,
-		OneLessCard:           oneLessCard,
-		AllOrNothing:          allOrNothing,
-		DetrimentalCharacters: detrimentalCharacters,
-	}
+	t.Options = d.GameJSON.GetOptions()
 	t.ExtraOptions = &ExtraOptions{
 		// Normally, "DatabaseID" is set to either -1 (in an ongoing game)
 		// or a positive number (for a replay of a game in the database or a "!replay" game)
@@ -550,6 +516,7 @@ func loadJSONOptionsToTable(d *CommandData, t *Table) {
 		SetSeedSuffix: "",
 		SetReplay:     false,
 		SetReplayTurn: 0,
+		PuzzleID:      0,
 	}
 }
 
@@ -561,13 +528,16 @@ func loadFakePlayers(t *Table, playerNames []string) {
 		id := (i + 1) * -1
 
 		player := &Player{
-			UserID:    id,
-			Name:      name,
-			Session:   NewFakeSession(id, name),
-			Present:   true,
-			Stats:     &PregameStats{},
-			Typing:    false,
-			LastTyped: time.Time{},
+			UserID:         id,
+			Name:           name,
+			OriginalUserID: 0,
+			Session:        NewFakeSession(id, name),
+			Present:        true,
+			Bot:            false,
+			BotPlayer:      nil,
+			Stats:          &PregameStats{},
+			Typing:         false,
+			LastTyped:      time.Time{},
 		}
 		t.Players = append(t.Players, player)
 	}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// commandReport is sent when a user reports another user for abusive chat or griefing
+// (it is also sent on behalf of the user when they type "/report [username] [reason]")
+// The most recent chat messages in the room are saved along with the report
+//
+// Example data:
+// {
+//   name: 'Alice',
+//   msg: 'spoiling the game in the chat',
+//   room: 'table5', // Room can also be "lobby"
+// }
+func commandReport(ctx context.Context, s *Session, d *CommandData) {
+	// Sanitize and validate the reason
+	if v, valid := sanitizeChatInput(s, d.Msg, false); !valid {
+		return
+	} else {
+		d.Msg = v
+	}
+
+	// Validate that the reported user exists in the database
+	var reported User
+	if exists, v, err := models.Users.GetUserFromNormalizedUsername(
+		normalizeString(d.Name),
+	); err != nil {
+		logger.Error("Failed to validate that \"" + d.Name + "\" exists in the database: " +
+			err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else if !exists {
+		s.Warning("The username of \"" + d.Name + "\" does not exist in the database.")
+		return
+	} else {
+		reported = v
+	}
+
+	if reported.ID == s.UserID {
+		s.Warning("You cannot report yourself.")
+		return
+	}
+
+	// Prevent users from spamming the moderators with the same report
+	if hasOpen, err := models.Reports.HasOpen(s.UserID, reported.ID); err != nil {
+		logger.Error("Failed to check for an open report from user \"" + s.Username + "\": " +
+			err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else if hasOpen {
+		s.Warning("You already have a report against \"" + reported.Username + "\" that the " +
+			"moderators have not resolved yet.")
+		return
+	}
+
+	// Get the chat messages that led up to the report
+	var tableID uint64
+	var chat string
+	if d.Room == "lobby" {
+		if v, err := getReportChatFromDatabase(d.Room); err != nil {
+			logger.Error("Failed to get the chat history for room \"" + d.Room + "\": " +
+				err.Error())
+			s.Error(DefaultErrorMsg)
+			return
+		} else {
+			chat = v
+		}
+	} else if strings.HasPrefix(d.Room, "table") {
+		if v1, v2, success := getReportChatFromTableRoom(ctx, s, d); !success {
+			return
+		} else {
+			tableID = v1
+			chat = v2
+		}
+	} else {
+		s.Warning("That is not a valid room.")
+		return
+	}
+
+	var reportID int
+	if v, err := models.Reports.Insert(&ReportRow{
+		ReporterID: s.UserID,
+		ReportedID: reported.ID,
+		Room:       d.Room,
+		TableID:    tableID,
+		Reason:     d.Msg,
+		Chat:       chat,
+	}); err != nil {
+		logger.Error("Failed to insert the report from user \"" + s.Username + "\": " +
+			err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		reportID = v
+	}
+
+	notifyModerators("New report #" + strconv.Itoa(reportID) + ": \"" + s.Username + "\" " +
+		"reported \"" + reported.Username + "\" in #" + d.Room + " - " + d.Msg)
+
+	msg := "Thank you. Your report against \"" + reported.Username + "\" has been sent to the " +
+		"moderators. (The other players cannot see it.)"
+	chatServerSendPM(s, msg, d.Room)
+}
+
+// getReportChatFromTableRoom returns the table ID and the most recent chat messages at the table
+// The reporter must be playing or spectating at the table
+func getReportChatFromTableRoom(
+	ctx context.Context,
+	s *Session,
+	d *CommandData,
+) (uint64, string, bool) {
+	match := lobbyRoomRegExp.FindStringSubmatch(d.Room)
+	if match == nil {
+		s.Warning("That is not a valid room.")
+		return 0, "", false
+	}
+	var tableID uint64
+	if v, err := strconv.ParseUint(match[1], 10, 64); err != nil {
+		s.Warning("That is not a valid room.")
+		return 0, "", false
+	} else {
+		tableID = v
+	}
+
+	t, exists := getTableAndLock(ctx, s, tableID, !d.NoTableLock, !d.NoTablesLock)
+	if !exists {
+		return 0, "", false
+	}
+	if !d.NoTableLock {
+		defer t.Unlock(ctx)
+	}
+
+	if t.GetPlayerIndexFromID(s.UserID) == -1 && t.GetSpectatorIndexFromID(s.UserID) == -1 {
+		s.Warning("You are not playing or spectating at table " + strconv.FormatUint(t.ID, 10) +
+			", so you cannot report from it.")
+		return 0, "", false
+	}
+
+	return t.ID, getReportChatFromTable(t), true
+}
@@ -237,12 +237,18 @@ func commandTableCreate(ctx context.Context, s *Session, d *CommandData) {
 			s.Warning("\"" + strconv.Itoa(d.Options.TimePerTurn) + "\" is too large of a value for \"Time per Turn\".")
 			return
 		}
+		if !validateTimeControl(s, d.Options) {
+			return
+		}
 	}
 
 	// Validate that there can be no time controls if this is not a timed game
 	if !d.Options.Timed {
 		d.Options.TimeBase = 0
 		d.Options.TimePerTurn = 0
+		d.Options.TimeControl = TimeControlFischer
+		d.Options.ByoYomiPeriods = 0
+		d.Options.TeamClock = false
 	}
 
 	// Validate that a speedrun cannot be timed
@@ -250,6 +256,9 @@ func commandTableCreate(ctx context.Context, s *Session, d *CommandData) {
 		d.Options.Timed = false
 		d.Options.TimeBase = 0
 		d.Options.TimePerTurn = 0
+		d.Options.TimeControl = TimeControlFischer
+		d.Options.ByoYomiPeriods = 0
+		d.Options.TeamClock = false
 	}
 
 	// Validate that they did not send both the "One Extra Card" and the "One Less Card" option at
@@ -259,6 +268,21 @@ func commandTableCreate(ctx context.Context, s *Session, d *CommandData) {
 		d.Options.OneLessCard = false
 	}
 
+	// Validate the custom rules
+	if !validateCustomRules(s, d.Options) {
+		return
+	}
+
+	// Validate the correspondence options
+	if !validateCorrespondence(s, d.Options) {
+		return
+	}
+
+	// Validate the spectator delay
+	if !validateSpectatorDelay(s, d.Options) {
+		return
+	}
+
 	// Validate games with custom JSON
 	if d.GameJSON != nil {
 		if !validateJSON(s, d) {
@@ -269,6 +293,100 @@ func commandTableCreate(ctx context.Context, s *Session, d *CommandData) {
 	tableCreate(ctx, s, d, data)
 }
 
+// validateTimeControl checks the clock mode of a timed game
+// An empty clock mode is treated as Fischer (for clients that do not send one)
+func validateTimeControl(s *Session, options *Options) bool {
+	switch options.TimeControl {
+	case "":
+		options.TimeControl = TimeControlFischer
+	case TimeControlFischer, TimeControlDelay, TimeControlBronstein, TimeControlByoYomi:
+	default:
+		s.Warning("\"" + options.TimeControl + "\" is not a valid time control.")
+		return false
+	}
+
+	if options.TimeControl == TimeControlByoYomi {
+		if options.ByoYomiPeriods < 1 || options.ByoYomiPeriods > 10 {
+			s.Warning("The number of byo-yomi periods must be between 1 and 10.")
+			return false
+		}
+	} else {
+		options.ByoYomiPeriods = 0
+	}
+
+	return true
+}
+
+// validateCorrespondence checks the options of correspondence games,
+// which have a deadline for every move instead of a clock
+func validateCorrespondence(s *Session, options *Options) bool {
+	if !options.Correspondence {
+		options.CorrespondenceDeadline = 0
+		return true
+	}
+
+	if options.Timed || options.Speedrun {
+		s.Warning("Correspondence games cannot be timed games or speedruns.")
+		return false
+	}
+	if options.CorrespondenceDeadline < 1 || options.CorrespondenceDeadline > 336 { // 2 weeks
+		s.Warning("The deadline for each move must be between 1 and 336 hours.")
+		return false
+	}
+
+	return true
+}
+
+// validateSpectatorDelay checks the delay for the spectators of tournament games
+func validateSpectatorDelay(s *Session, options *Options) bool {
+	if options.SpectatorDelayTurns < 0 || options.SpectatorDelayTurns > MaxSpectatorDelayTurns {
+		s.Warning("The spectator delay must be between 0 and " +
+			strconv.Itoa(MaxSpectatorDelayTurns) + " turns.")
+		return false
+	}
+	if options.SpectatorDelaySeconds < 0 ||
+		options.SpectatorDelaySeconds > MaxSpectatorDelaySeconds {
+
+		s.Warning("The spectator delay must be between 0 and " +
+			strconv.Itoa(MaxSpectatorDelaySeconds) + " seconds.")
+		return false
+	}
+	if options.SpectatorDelayTurns > 0 && options.SpectatorDelaySeconds > 0 {
+		s.Warning("The spectator delay can be in turns or in seconds, but not both.")
+		return false
+	}
+
+	return true
+}
+
+// validateCustomRules checks the custom clue token, strike, and hand size options
+// A value of 0 for any of these means that the normal rules are used
+func validateCustomRules(s *Session, options *Options) bool {
+	engineOptions := options.EngineOptions()
+
+	if options.MaxClueTokens < 0 || options.MaxClueTokens > 20 {
+		s.Warning("The maximum amount of clue tokens must be between 1 and 20.")
+		return false
+	}
+	if options.StartingClueTokens < 0 ||
+		options.StartingClueTokens > engineOptions.GetMaxClueTokens() {
+
+		s.Warning("The starting amount of clue tokens must be between 1 and " +
+			strconv.Itoa(engineOptions.GetMaxClueTokens()) + ".")
+		return false
+	}
+	if options.StrikeLimit < 0 || options.StrikeLimit > 10 {
+		s.Warning("The strike limit must be between 1 and 10.")
+		return false
+	}
+	if options.HandSize < 0 || options.HandSize > 10 {
+		s.Warning("The hand size must be between 1 and 10.")
+		return false
+	}
+
+	return true
+}
+
 func tableCreate(ctx context.Context, s *Session, d *CommandData, data *SpecialGameData) {
 	// Since this is a function that changes a user's relationship to tables,
 	// we must acquires the tables lock to prevent race conditions
@@ -279,8 +397,10 @@ func tableCreate(ctx context.Context, s *Session, d *CommandData, data *SpecialG
 
 	// Validate that the player is not joined to another table
 	// (this cannot be in the "commandTableCreate()" function because we need the tables lock)
-	if !strings.HasPrefix(s.Username, "Bot-") {
-		if len(tables.GetTablesUserPlaying(s.UserID)) > 0 {
+	// (only bots have the ability to create more than one table; see "tableJoin()")
+	// (correspondence games do not count, since they can be played alongside other games)
+	if !s.Bot && !d.Options.Correspondence {
+		if len(tables.GetRealTimeTablesUserPlaying(s.UserID)) > 0 {
 			s.Warning("You cannot join more than one table at a time. " +
 				"Terminate your other game before creating a new one.")
 			return
@@ -319,6 +439,7 @@ func tableCreate(ctx context.Context, s *Session, d *CommandData, data *SpecialG
 		SetSeedSuffix:              data.SetSeedSuffix,
 		SetReplay:                  false,
 		SetReplayTurn:              0,
+		PuzzleID:                   0,
 	}
 
 	// If this is a "!replay" game, override the options with the ones found in the database
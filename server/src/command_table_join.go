@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/alexedwards/argon2id"
@@ -31,9 +30,10 @@ func commandTableJoin(ctx context.Context, s *Session, d *CommandData) {
 		return
 	}
 
-	// Validate that this table does not already have 6 players
-	if len(t.Players) >= 6 {
-		s.Warning("That table is already full. (You can not play with more than 6 players.)")
+	// Validate that this table does not already have the maximum amount of players
+	if len(t.Players) >= MaxPlayers {
+		s.Warning("That table is already full. " +
+			"(You can not play with more than " + strconv.Itoa(MaxPlayers) + " players.)")
 		return
 	}
 
@@ -68,6 +68,12 @@ func commandTableJoin(ctx context.Context, s *Session, d *CommandData) {
 		return
 	}
 
+	// Validate that the owner of the table has not blocked them
+	if _, ok := s.ReverseBlocks()[t.OwnerID]; ok {
+		s.Warning("You cannot join this table.")
+		return
+	}
+
 	tableJoin(ctx, s, d, t)
 }
 
@@ -85,8 +91,9 @@ func tableJoin(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	// Validate that the player is not joined to any table
 	// (this cannot be in the "commandTableJoin()" function because we need the tables lock)
 	// (only bots have the ability to join more than one table)
-	if !strings.HasPrefix(s.Username, "Bot-") {
-		if len(tables.GetTablesUserPlaying(s.UserID)) > 0 {
+	// (correspondence games do not count, since they can be played alongside other games)
+	if !s.Bot && !t.Options.Correspondence {
+		if len(tables.GetRealTimeTablesUserPlaying(s.UserID)) > 0 {
 			s.Warning("You cannot join more than one table at a time. " +
 				"Terminate your other game before joining a new one.")
 			return
@@ -123,6 +130,7 @@ func tableJoin(ctx context.Context, s *Session, d *CommandData, t *Table) {
 		Name:    s.Username,
 		Session: s,
 		Present: true,
+		Bot:     s.Bot,
 		Stats: &PregameStats{
 			NumGames: numGames,
 			Variant:  variantStats,
@@ -136,6 +144,7 @@ func tableJoin(ctx context.Context, s *Session, d *CommandData, t *Table) {
 
 	notifyAllTable(t)
 	t.NotifyPlayerChange()
+	t.NotifyBlockers(s, false)
 
 	// Set their status
 	s.SetStatus(StatusPregame)
@@ -19,6 +19,7 @@ var (
 // {
 //   tableID: 15103,
 // }
+// (if the table requires votes, this proposes a vote to restart instead)
 func commandTableRestart(ctx context.Context, s *Session, d *CommandData) {
 	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
 	if !exists {
@@ -120,6 +121,13 @@ func commandTableRestart(ctx context.Context, s *Session, d *CommandData) {
 		return
 	}
 
+	// Tables that require votes do not let the leader restart the game on their own
+	// ("Server" is set once the vote has passed)
+	if t.Options.VoteRequired && !d.Server {
+		startVote(ctx, s, d, t, VoteTypeRestart)
+		return
+	}
+
 	tableRestart(ctx, s, d, t, playerSessions, spectatorSessions)
 }
 
@@ -140,11 +148,14 @@ func tableRestart(
 
 	// Validate that the selected players are not playing in another game
 	// (this cannot be in the "commandTableRestart()" function because we need the tables lock)
-	for _, s2 := range playerSessions {
-		if len(tables.GetTablesUserPlaying(s2.UserID)) > 0 {
-			s.Warning("You cannot restart the game because " + s2.Username +
-				" is already playing in another game.")
-			return
+	// (correspondence games do not count, since they can be played alongside other games)
+	if !t.Options.Correspondence {
+		for _, s2 := range playerSessions {
+			if len(tables.GetRealTimeTablesUserPlaying(s2.UserID)) > 0 {
+				s.Warning("You cannot restart the game because " + s2.Username +
+					" is already playing in another game.")
+				return
+			}
 		}
 	}
 
@@ -43,6 +43,12 @@ func commandTableSpectate(ctx context.Context, s *Session, d *CommandData) {
 		}
 	}
 
+	// Validate that this is not a puzzle (they are meant to be attempted solo)
+	if t.ExtraOptions.PuzzleID != 0 {
+		s.Warning("You cannot spectate a puzzle.")
+		return
+	}
+
 	// Validate that they are not already spectating this table
 	for _, sp := range t.Spectators {
 		if sp.UserID == s.UserID {
@@ -58,6 +64,12 @@ func commandTableSpectate(ctx context.Context, s *Session, d *CommandData) {
 			s.Warning("That is an invalid player index to shadow.")
 			return
 		}
+
+		// Shadowing a player would show the spectator the notes of the player as they are written
+		if t.HasSpectatorDelay() {
+			s.Warning("You cannot shadow a player in a game with a spectator delay.")
+			return
+		}
 	}
 
 	tableSpectate(ctx, s, d, t)
@@ -108,6 +120,7 @@ func tableSpectate(ctx context.Context, s *Session, d *CommandData, t *Table) {
 
 	notifyAllTable(t)    // Update the spectator list for the row in the lobby
 	t.NotifySpectators() // Update the in-game spectator list
+	t.NotifyBlockers(s, t.isHiddenFromPlayers(len(t.Spectators)-1))
 
 	// Set their status
 	status := StatusSpectating
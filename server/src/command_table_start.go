@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
-	"math/rand"
 	"strconv"
 	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
 )
 
 // commandTableStart is sent when the owner of a table clicks on the "Start Game" button
@@ -32,11 +33,35 @@ func commandTableStart(ctx context.Context, s *Session, d *CommandData) {
 	}
 
 	// Validate that the table has at least 2 players
-	if len(t.Players) < 2 {
-		s.Warning("You need at least 2 players before you can start a game.")
+	if len(t.Players) < MinPlayers {
+		s.Warning("You need at least " + strconv.Itoa(MinPlayers) + " players " +
+			"before you can start a game.")
+		return
+	}
+
+	// Validate that the deck has enough cards to deal everyone a hand
+	// (e.g. this might not be the case for 8-player games with a small variant)
+	variant := variants[t.Options.VariantName]
+	engineOptions := t.Options.EngineOptions()
+	handSize := engine.GetHandSize(len(t.Players), engineOptions)
+	if handSize < 1 {
+		s.Warning("You cannot start a game with a hand size of " + strconv.Itoa(handSize) + ".")
+		return
+	}
+	if len(t.Players)*handSize >= variant.GetDeckSize() {
+		s.Warning("There are not enough cards in the deck to deal a hand to " +
+			strconv.Itoa(len(t.Players)) + " players.")
 		return
 	}
 
+	// A game where a maximum score is impossible from the start is still allowed,
+	// but the players should know about it
+	if engine.GetStartingPace(variant, len(t.Players), engineOptions) < 0 {
+		msg := "Warning: With " + strconv.Itoa(len(t.Players)) + " players, there are not " +
+			"enough cards left in the deck after the deal to get a maximum score in this variant."
+		chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+	}
+
 	// Validate that the game is not started yet
 	if t.Running {
 		s.Warning("The game has already started, so you cannot start it.")
@@ -91,7 +116,14 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	// Start the idle timeout
 	go t.CheckIdle(ctx)
 
-	g.InitDeck()
+	// Create the deck
+	// (if a custom deck was provided along with the game options, then use that instead;
+	// custom seeds override custom decks)
+	customDeck := t.ExtraOptions.CustomDeck
+	if t.ExtraOptions.CustomSeed != "" {
+		customDeck = nil
+	}
+	g.State.InitDeck(customDeck)
 
 	// Handle setting the seed
 	shuffleDeck := true
@@ -155,14 +187,12 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	logger.Info("Shuffling deck: " + strconv.FormatBool(shuffleDeck))
 	logger.Info("Shuffling players: " + strconv.FormatBool(shufflePlayers))
 
-	setSeed(g.Seed) // Seed the random number generator
+	// Shuffle the deck with the random number generator for the game
+	// (this must be done in exactly the same way as in the "getDeckFromSeed()" function,
+	// since exported games are rebuilt from the seed)
+	g.Rand = newSeededRand(g.Seed)
 	if shuffleDeck {
-		g.ShuffleDeck()
-	}
-
-	// Mark the order of all of the cards in the deck
-	for i, c := range g.Deck {
-		c.Order = i
+		g.State.ShuffleDeck(g.Rand.Intn)
 	}
 
 	// The 0th player will always go first
@@ -170,33 +200,30 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	// Additionally, we need to shuffle the order of the players so that the order that the players
 	// joined the game in does not correspond to the order of the players in the actual game
 	// https://stackoverflow.com/questions/12264789/shuffle-array-in-go
-	// For this step, we don't care about explicitly seeding the random number generator,
-	// since any random shuffling will do
+	// For this step, we keep using the random number generator for the game (after the deck
+	// shuffle), so that a seed always results in the same order of players
 	if shufflePlayers {
 		for i := range t.Players {
-			j := rand.Intn(i + 1) // nolint: gosec
+			j := g.Rand.Intn(i + 1)
 			t.Players[i], t.Players[j] = t.Players[j], t.Players[i]
 		}
 	}
 
 	// Games created prior to April 2020 do not always have the 0th player taking the first turn
 	if t.Options.StartingPlayer != 0 {
-		g.ActivePlayerIndex = t.Options.StartingPlayer
+		g.State.ActivePlayerIndex = t.Options.StartingPlayer
 	}
 
 	// Initialize the GamePlayer objects
-	for i, p := range t.Players {
+	for _, p := range t.Players {
 		gp := &GamePlayer{
-			Name:  p.Name,
-			Index: i,
-			Game:  g,
-
-			Hand:              make([]*Card, 0),
-			Time:              0,
-			Notes:             make([]string, g.GetNotesSize()),
-			RequestedPause:    false,
-			Character:         "",
-			CharacterMetadata: -1,
+			Player: g.State.AddPlayer(p.Name),
+			Game:   g,
+
+			Time:           0,
+			ByoYomiPeriods: 0,
+			Notes:          make([]string, g.GetNotesSize()),
+			RequestedPause: false,
 		}
 		gp.InitTime(t.Options)
 		g.Players = append(g.Players, gp)
@@ -211,6 +238,10 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	// (we will set them back to present once they send the "getGameInfo2" message)
 	listOfAwayPlayers := make([]int, 0)
 	for _, p := range t.Players {
+		if p.Bot && p.BotPlayer != nil {
+			// Bots that run inside of the server do not need to load anything
+			continue
+		}
 		if p.Present {
 			p.Present = false
 		} else {
@@ -219,12 +250,11 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 	}
 
 	// Deal the cards
-	handSize := g.GetHandSize()
-	for _, p := range g.Players {
-		for i := 0; i < handSize; i++ {
-			p.DrawCard()
-		}
+	for _, event := range g.State.Deal() {
+		g.Actions = append(g.Actions, event)
+		t.NotifyGameAction()
 	}
+	g.InitialState = g.State.Clone()
 
 	// Now that all of the initial game actions have been performed, mark that the game has started
 	t.Running = true
@@ -259,8 +289,9 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 		notifyAllTable(t)
 
 		// Set the status for all of the users in the game
+		// (bots that run inside of the server are not in the user list)
 		for _, p := range t.Players {
-			if p.Session != nil {
+			if p.Session != nil && p.BotPlayer == nil {
 				p.Session.SetStatus(StatusPlaying)
 				p.Session.SetTableID(t.ID)
 				notifyAllUser(p.Session)
@@ -268,7 +299,20 @@ func tableStart(ctx context.Context, s *Session, d *CommandData, t *Table) {
 		}
 	}
 
-	// The "CheckTimer()" function will be called when the starting player has finished loading
+	// Correspondence games are stored in the database so that they survive server restarts
+	t.SaveCorrespondence()
+
+	// The turn timer will be started when the starting player has finished loading
+	// (bots that run inside of the server never load, so we have to start the timer for them)
+	if !t.ExtraOptions.NoWriteToDatabase &&
+		t.Players[g.State.ActivePlayerIndex].BotPlayer != nil &&
+		g.State.EndCondition == EndConditionInProgress {
+
+		g.StartedTimer = true
+		g.DatetimeTurnBegin = time.Now()
+		g.StartTimer(ctx)
+		g.CheckBotTurn(ctx)
+	}
 }
 
 func emulateActions(ctx context.Context, s *Session, d *CommandData, t *Table) {
@@ -285,7 +329,7 @@ func emulateActions(ctx context.Context, s *Session, d *CommandData, t *Table) {
 			return
 		}
 
-		p := t.Players[g.ActivePlayerIndex]
+		p := t.Players[g.State.ActivePlayerIndex]
 
 		commandAction(ctx, p.Session, &CommandData{ // nolint: exhaustivestruct
 			TableID:      t.ID,
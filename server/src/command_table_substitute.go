@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// commandTableSubstitute is sent when the owner of a table proposes that a spectator takes over the
+// seat of a player in the middle of a game (e.g. because the player has to leave)
+// The substitution happens once every other player and the substitute have accepted it
+//
+// Example data:
+// {
+//   tableID: 5,
+//   playerIndex: 1, // The seat that the substitute will take over
+//   name: 'Alice', // The name of the spectator
+// }
+func commandTableSubstitute(ctx context.Context, s *Session, d *CommandData) {
+	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
+	if !exists {
+		return
+	}
+	if !d.NoTableLock {
+		defer t.Unlock(ctx)
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.Warning(NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You cannot substitute a player in a replay.")
+		return
+	}
+
+	// Validate that this is the owner of the table
+	if s.UserID != t.OwnerID {
+		s.Warning("Only the owner of a table can propose a substitution.")
+		return
+	}
+
+	// Validate that there is not a vote in progress
+	if t.Vote != nil {
+		s.Warning("You cannot propose a substitution while there is a pending vote.")
+		return
+	}
+
+	// Validate that there is not already a substitution in progress
+	if t.Substitution != nil {
+		s.Warning("There is already a pending substitution of " + t.Substitution.Name + " for " +
+			t.Players[t.Substitution.PlayerIndex].Name + ".")
+		return
+	}
+
+	// Validate the player index
+	if d.PlayerIndex < 0 || d.PlayerIndex > len(t.Players)-1 {
+		s.Warning("That is an invalid player index.")
+		return
+	}
+	p := t.Players[d.PlayerIndex]
+	if p.BotPlayer != nil {
+		s.Warning("You cannot substitute a bot that runs inside of the server.")
+		return
+	}
+
+	// Validate that the substitute is spectating the game
+	var substitute *Spectator
+	for _, sp := range t.Spectators {
+		if normalizeString(sp.Name) == normalizeString(d.Name) {
+			substitute = sp
+			break
+		}
+	}
+	if substitute == nil {
+		s.Warning("\"" + d.Name + "\" is not spectating this game.")
+		return
+	}
+
+	// Validate that the substitute is not already playing in another game
+	if !d.NoTablesLock {
+		tables.RLock()
+	}
+	playingAnotherTable := isPlayingAnotherRealTimeTable(substitute, t)
+	if !d.NoTablesLock {
+		tables.RUnlock()
+	}
+	if playingAnotherTable {
+		s.Warning(substitute.Name + " is already playing at another table, so they cannot " +
+			"take over a seat.")
+		return
+	}
+
+	tableSubstitute(ctx, s, d, t, substitute)
+}
+
+func tableSubstitute(
+	ctx context.Context,
+	s *Session,
+	d *CommandData,
+	t *Table,
+	substitute *Spectator,
+) {
+	// Local variables
+	p := t.Players[d.PlayerIndex]
+
+	t.Substitution = &Substitution{
+		PlayerIndex: d.PlayerIndex,
+		UserID:      substitute.UserID,
+		Name:        substitute.Name,
+		Accepted:    make(map[int]struct{}),
+		Timer:       NewTurnTimer(),
+	}
+
+	// The owner proposed the substitution, so they have already accepted it
+	// (unless they are the one being substituted, in which case they do not get a vote)
+	if d.PlayerIndex != t.GetPlayerIndexFromID(s.UserID) {
+		t.Substitution.Accepted[s.UserID] = struct{}{}
+	}
+
+	logger.Info(t.GetName() + "User \"" + s.Username + "\" proposed to substitute " +
+		substitute.Name + " for " + p.Name + " (in seat " + strconv.Itoa(d.PlayerIndex) + ").")
+
+	// Cancel the substitution if everyone does not accept in time
+	sub := t.Substitution
+	sub.Timer.Schedule(VoteTimeout, func() {
+		t.CheckSubstitutionExpired(ctx, sub)
+	})
+
+	msg := s.Username + " proposed that " + substitute.Name + " takes over the seat of " + p.Name +
+		". Use /accept or /decline to vote within " +
+		strconv.Itoa(int(VoteTimeout.Seconds())) + " seconds. Waiting on: " +
+		strings.Join(t.getSubstitutionWaitingOn(), ", ")
+	chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+)
+
+// commandTableSubstituteVote is sent when a player (or the proposed substitute) accepts or
+// declines the pending substitution
+//
+// Example data:
+// {
+//   tableID: 5,
+//   vote: true, // False to decline
+// }
+func commandTableSubstituteVote(ctx context.Context, s *Session, d *CommandData) {
+	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
+	if !exists {
+		return
+	}
+	if !d.NoTableLock {
+		defer t.Unlock(ctx)
+	}
+
+	// Validate that there is a substitution to vote on
+	// (it is canceled when the game ends)
+	if t.Substitution == nil || !t.Running || t.Replay {
+		s.Warning("There is no pending substitution.")
+		return
+	}
+
+	// Validate that they are allowed to vote
+	if !intInSlice(s.UserID, t.getSubstitutionVoters()) {
+		s.Warning("You do not have a vote on this substitution.")
+		return
+	}
+
+	// Validate that they have not already accepted
+	if _, ok := t.Substitution.Accepted[s.UserID]; ok && d.Vote {
+		s.Warning("You have already accepted this substitution.")
+		return
+	}
+
+	substitutionVote(ctx, s, d, t, d.Vote)
+}
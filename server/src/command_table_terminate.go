@@ -13,6 +13,7 @@ import (
 //   tableID: 5,
 //   server: true, // True if a server-initiated termination, otherwise omitted
 // }
+// (if the table requires votes, this proposes a vote to terminate instead)
 func commandTableTerminate(ctx context.Context, s *Session, d *CommandData) {
 	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
 	if !exists {
@@ -42,15 +43,23 @@ func commandTableTerminate(ctx context.Context, s *Session, d *CommandData) {
 		return
 	}
 
+	// Tables that require votes do not let a single player terminate the game
+	// ("Server" is set once the vote has passed)
+	if t.Options.VoteRequired && !d.Server {
+		startVote(ctx, s, d, t, VoteTypeTerminate)
+		return
+	}
+
 	terminate(ctx, s, d, t, playerIndex)
 }
 
 func terminate(ctx context.Context, s *Session, d *CommandData, t *Table, playerIndex int) {
 	commandAction(ctx, s, &CommandData{ // nolint: exhaustivestruct
-		TableID:     t.ID,
-		Type:        ActionTypeEndGame,
-		Target:      playerIndex,
-		Value:       EndConditionTerminated,
-		NoTableLock: true,
+		TableID:      t.ID,
+		Type:         ActionTypeEndGame,
+		Target:       playerIndex,
+		Value:        EndConditionTerminated,
+		NoTableLock:  true,
+		NoTablesLock: d.NoTablesLock,
 	})
 }
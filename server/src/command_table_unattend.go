@@ -95,6 +95,8 @@ func tableUnattendSpectator(ctx context.Context, s *Session, d *CommandData, t *
 
 	if t.Replay && len(t.Spectators) == 0 {
 		// This was the last person to leave the replay, so delete it
+		// (but keep the hypothetical that they were looking at, if any)
+		t.SaveReplayHypothetical(s)
 		deleteTable(t)
 		logger.Info("Ended replay #" + strconv.FormatUint(t.ID, 10) + " because everyone left.")
 		return
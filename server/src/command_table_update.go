@@ -129,12 +129,18 @@ func commandTableUpdate(ctx context.Context, s *Session, d *CommandData) {
 			s.Warning("\"" + strconv.Itoa(d.Options.TimePerTurn) + "\" is too large of a value for \"Time per Turn\".")
 			return
 		}
+		if !validateTimeControl(s, d.Options) {
+			return
+		}
 	}
 
 	// Validate that there can be no time controls if this is not a timed game
 	if !d.Options.Timed {
 		d.Options.TimeBase = 0
 		d.Options.TimePerTurn = 0
+		d.Options.TimeControl = TimeControlFischer
+		d.Options.ByoYomiPeriods = 0
+		d.Options.TeamClock = false
 	}
 
 	// Validate that a speedrun cannot be timed
@@ -142,6 +148,9 @@ func commandTableUpdate(ctx context.Context, s *Session, d *CommandData) {
 		d.Options.Timed = false
 		d.Options.TimeBase = 0
 		d.Options.TimePerTurn = 0
+		d.Options.TimeControl = TimeControlFischer
+		d.Options.ByoYomiPeriods = 0
+		d.Options.TeamClock = false
 	}
 
 	// Validate that they did not send both the "One Extra Card" and the "One Less Card" option at
@@ -151,6 +160,21 @@ func commandTableUpdate(ctx context.Context, s *Session, d *CommandData) {
 		d.Options.OneLessCard = false
 	}
 
+	// Validate the custom rules
+	if !validateCustomRules(s, d.Options) {
+		return
+	}
+
+	// Validate the correspondence options
+	if !validateCorrespondence(s, d.Options) {
+		return
+	}
+
+	// Validate the spectator delay
+	if !validateSpectatorDelay(s, d.Options) {
+		return
+	}
+
 	tableUpdate(ctx, s, d, data, t)
 }
 
@@ -175,6 +199,7 @@ func tableUpdate(ctx context.Context, s *Session, d *CommandData, data *SpecialG
 		SetSeedSuffix:              data.SetSeedSuffix,
 		SetReplay:                  false,
 		SetReplayTurn:              0,
+		PuzzleID:                   0,
 	}
 
 	// Update the variant-specific stats for each player at the table
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"strconv"
+)
+
+// commandTableVote is sent when a player proposes a vote to terminate, pause, unpause, or restart
+// the game, or to undo the last move (see "table_vote.go")
+//
+// Example data:
+// {
+//   tableID: 5,
+//   setting: 'terminate', // Can also be 'pause', 'unpause', 'restart', or 'undo'
+// }
+func commandTableVote(ctx context.Context, s *Session, d *CommandData) {
+	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
+	if !exists {
+		return
+	}
+	if !d.NoTableLock {
+		defer t.Unlock(ctx)
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.Warning(NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not propose a vote in a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	if t.GetPlayerIndexFromID(s.UserID) == -1 {
+		s.Warning("You are not playing at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot propose a vote.")
+		return
+	}
+
+	startVote(ctx, s, d, t, d.Setting)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+)
+
+// commandTableVoteCast is sent when a player accepts or declines the pending table vote
+//
+// Example data:
+// {
+//   tableID: 5,
+//   vote: true, // False to decline
+// }
+func commandTableVoteCast(ctx context.Context, s *Session, d *CommandData) {
+	t, exists := getTableAndLock(ctx, s, d.TableID, !d.NoTableLock, !d.NoTablesLock)
+	if !exists {
+		return
+	}
+	if !d.NoTableLock {
+		defer t.Unlock(ctx)
+	}
+
+	// Validate that there is a vote to cast
+	if t.Vote == nil {
+		s.Warning("There is no pending vote.")
+		return
+	}
+
+	// Validate that they are allowed to vote
+	if !intInSlice(s.UserID, t.getVoters()) {
+		s.Warning("You are not one of the players, so you cannot vote.")
+		return
+	}
+
+	// Validate that they have not already accepted
+	if _, ok := t.Vote.Accepted[s.UserID]; ok && d.Vote {
+		s.Warning("You have already accepted this vote.")
+		return
+	}
+
+	castVote(ctx, s, d, t, d.Vote)
+}
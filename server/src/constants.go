@@ -2,6 +2,8 @@ package main
 
 import (
 	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
 )
 
 // iota starts at 0 and counts upwards
@@ -18,32 +20,32 @@ const (
 	StatusSharedReplay
 )
 
-// When in a game, players can send certain types of "actions" to the server to communicate what
-// kind of move they want to perform
+// The constants that relate to the rules of the game are defined in the engine package
+// (they are mirrored here for convenience)
 const (
-	ActionTypePlay = iota
-	ActionTypeDiscard
-	ActionTypeColorClue
-	ActionTypeRankClue
-	ActionTypeEndGame // Players cannot send this (internal only)
+	ActionTypePlay      = engine.ActionTypePlay
+	ActionTypeDiscard   = engine.ActionTypeDiscard
+	ActionTypeColorClue = engine.ActionTypeColorClue
+	ActionTypeRankClue  = engine.ActionTypeRankClue
+	ActionTypeEndGame   = engine.ActionTypeEndGame // Players cannot send this (internal only)
 )
 
 const (
-	ClueTypeColor = iota
-	ClueTypeRank
+	ClueTypeColor = engine.ClueTypeColor
+	ClueTypeRank  = engine.ClueTypeRank
 )
 
 const (
-	EndConditionInProgress = iota
-	EndConditionNormal
-	EndConditionStrikeout
-	EndConditionTimeout
-	EndConditionTerminated
-	EndConditionSpeedrunFail
-	EndConditionIdleTimeout
-	EndConditionCharacterSoftlock
-	EndConditionAllOrNothingFail
-	EndConditionAllOrNothingSoftlock
+	EndConditionInProgress           = engine.EndConditionInProgress
+	EndConditionNormal               = engine.EndConditionNormal
+	EndConditionStrikeout            = engine.EndConditionStrikeout
+	EndConditionTimeout              = engine.EndConditionTimeout
+	EndConditionTerminated           = engine.EndConditionTerminated
+	EndConditionSpeedrunFail         = engine.EndConditionSpeedrunFail
+	EndConditionIdleTimeout          = engine.EndConditionIdleTimeout
+	EndConditionCharacterSoftlock    = engine.EndConditionCharacterSoftlock
+	EndConditionAllOrNothingFail     = engine.EndConditionAllOrNothingFail
+	EndConditionAllOrNothingSoftlock = engine.EndConditionAllOrNothingSoftlock
 )
 
 // When in a shared replay, spectators can send certain types of "actions" to the server to
@@ -67,6 +69,14 @@ const (
 	ReplayActionTypeToggleRevealed
 	// Players can manually adjust the efficiency to account for cards that are Finessed
 	ReplayActionTypeEfficiencyMod
+	// Create a new hypothetical branch from the current point of the current branch
+	ReplayActionTypeHypoBranch
+	// Switch to a different hypothetical branch
+	ReplayActionTypeHypoSwitch
+	// Save all of the hypothetical branches to the database
+	ReplayActionTypeHypoSave
+	// Load hypothetical branches that were saved previously
+	ReplayActionTypeHypoLoad
 )
 
 // Certain types of optional game settings can make the game easier
@@ -78,20 +88,35 @@ const (
 	ScoreModifierOneExtraCard
 	ScoreModifierOneLessCard
 	ScoreModifierAllOrNothing
+	ScoreModifierCustomRules // A custom amount of clue tokens, strikes, or cards in hand
 )
 
+// Timed games can use different methods of adding time to the clock
+// (the "Time per Turn" option is used differently for each one)
 const (
-	WebsiteName = "Hanab Live"
+	// The increment is added to the clock after every turn
+	TimeControlFischer = "fischer"
+	// The clock does not start to run until the delay has passed
+	TimeControlDelay = "delay"
+	// The time used is added back to the clock after every turn, up to the delay
+	TimeControlBronstein = "bronstein"
+	// Once the base time runs out, every turn must be completed within one period;
+	// going over a period uses it up
+	TimeControlByoYomi = "byoyomi"
+)
 
-	// The maximum amount of clues (and the amount of clues that players start the game with)
-	MaxClueNum = 8
+const (
+	WebsiteName = "Hanab Live"
 
-	// The maximum amount of strikes/misplays allowed before the game ends
-	MaxStrikeNum = 3
+	MinPlayers    = engine.MinPlayers
+	MaxPlayers    = engine.MaxPlayers
+	MaxClueNum    = engine.MaxClueNum
+	MaxStrikeNum  = engine.MaxStrikeNum
+	PointsPerSuit = engine.PointsPerSuit
+	StartCardRank = engine.StartCardRank
 
-	// Currently, in all variants, you get 5 points per suit/stack,
-	// but this may not always be the case
-	PointsPerSuit = 5
+	// There is a best score for every player count (e.g. 2-player, 3-player, etc.)
+	NumBestScores = MaxPlayers - MinPlayers + 1
 
 	// A "reversed" version of every suit exists
 	SuitReversedSuffix = " Reversed"
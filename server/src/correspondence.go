@@ -0,0 +1,175 @@
+// This file contains the functions for correspondence games,
+// which are played over the course of days with a deadline for every move
+// While they are ongoing, they are stored in the database (instead of only in memory) so that
+// they survive server restarts
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type CorrespondenceTableMessage struct {
+	TableID  uint64   `json:"tableID"`
+	Name     string   `json:"name"`
+	Variant  string   `json:"variant"`
+	Players  []string `json:"players"`
+	Turn     int      `json:"turn"`
+	YourTurn bool     `json:"yourTurn"`
+	// The time at which the active player will run out of time for their move
+	Deadline time.Time `json:"deadline"`
+}
+
+// IsOngoingCorrespondenceGame returns whether or not this table is a correspondence game that has
+// started and has not ended yet
+func (t *Table) IsOngoingCorrespondenceGame() bool {
+	return t.Options.Correspondence && t.Running && !t.Replay
+}
+
+// SaveCorrespondence writes the current state of a correspondence game to the database
+// The table lock is assumed to be acquired in this function
+func (t *Table) SaveCorrespondence() {
+	if !t.IsOngoingCorrespondenceGame() || t.ExtraOptions.NoWriteToDatabase {
+		return
+	}
+
+	// This uses the same JSON as when ongoing tables are saved to disk during a graceful restart
+	var tableJSON []byte
+	if v, err := json.Marshal(t); err != nil {
+		logger.Error(t.GetName() + "Failed to marshal the correspondence game: " + err.Error())
+		return
+	} else {
+		tableJSON = v
+	}
+
+	if err := models.CorrespondenceTables.Set(t.ID, tableJSON); err != nil {
+		logger.Error(t.GetName() + "Failed to save the correspondence game to the database: " +
+			err.Error())
+	}
+}
+
+// DeleteCorrespondence removes a correspondence game from the database after it has ended
+// (at which point it will be written to the "games" table like any other game)
+func (t *Table) DeleteCorrespondence() {
+	if !t.Options.Correspondence || t.ExtraOptions.NoWriteToDatabase {
+		return
+	}
+
+	if err := models.CorrespondenceTables.Delete(t.ID); err != nil {
+		logger.Error(t.GetName() + "Failed to delete the correspondence game from the database: " +
+			err.Error())
+	}
+}
+
+// restoreCorrespondenceTables recreates the correspondence games that are stored in the database
+// (this must be called after the "restoreTables()" function)
+func restoreCorrespondenceTables() {
+	ctx := NewMiscContext("restoreCorrespondenceTables")
+
+	// We first acquire the tables lock so that we can safely modify the tables map
+	tables.Lock(ctx)
+	defer tables.Unlock(ctx)
+
+	var tableJSONs [][]byte
+	if v, err := models.CorrespondenceTables.GetAll(); err != nil {
+		logger.Fatal("Failed to get the correspondence games from the database: " + err.Error())
+		return
+	} else {
+		tableJSONs = v
+	}
+
+	numTablesRestored := 0
+	for _, tableJSON := range tableJSONs {
+		t := &Table{} // We must initialize the table for "Unmarshal()" to work
+		if err := json.Unmarshal(tableJSON, t); err != nil {
+			logger.Error("Failed to unmarshal a correspondence game: " + err.Error())
+			continue
+		}
+		if _, ok := tables.Get(t.ID, false); ok {
+			logger.Error("Failed to restore correspondence game " + strconv.FormatUint(t.ID, 10) +
+				" since a table with that ID already exists.")
+			continue
+		}
+		restoreTableReferences(t)
+		g := t.Game
+
+		tables.Set(t.ID, t)
+		numTablesRestored++
+		logger.Info(t.GetName() + "Restored correspondence game.")
+
+		// Unlike timed games, the time that the server was offline counts towards the deadline
+		// (since the deadline is measured in hours, this is not a big deal)
+		if !g.Paused {
+			g.StartTimer(ctx)
+		}
+
+		// If it is a bot's turn, the bot will need to act
+		g.CheckBotTurn(ctx)
+
+		// The actions that are being held back from the spectators will need to be released
+		t.ReleaseSpectatorActions()
+	}
+
+	msg := "Restored " + strconv.Itoa(numTablesRestored) + " correspondence game"
+	if numTablesRestored != 1 {
+		msg += "s"
+	}
+	msg += "."
+	logger.Info(msg)
+}
+
+func makeCorrespondenceTableMessage(s *Session, t *Table) *CorrespondenceTableMessage {
+	// Local variables
+	g := t.Game
+	activePlayer := g.Players[g.State.ActivePlayerIndex]
+
+	players := make([]string, 0)
+	for _, p := range t.Players {
+		players = append(players, p.Name)
+	}
+
+	return &CorrespondenceTableMessage{
+		TableID:  t.ID,
+		Name:     t.Name,
+		Variant:  t.Options.VariantName,
+		Players:  players,
+		Turn:     g.State.Turn,
+		YourTurn: t.Players[activePlayer.Index].UserID == s.UserID,
+		Deadline: time.Now().Add(g.GetTimeUntilTimeout(activePlayer)),
+	}
+}
+
+// sendCorrespondenceTableList sends a "correspondenceTableList" message with all of the ongoing
+// correspondence games that the user is playing in
+// The games where it is their turn are listed first, ordered by how soon the deadline is
+func sendCorrespondenceTableList(ctx context.Context, s *Session) {
+	// We make a copy of the table IDs since the tables lock is released before we iterate over them
+	tables.RLock()
+	tableIDs := append([]uint64{}, tables.GetTablesUserPlaying(s.UserID)...)
+	tables.RUnlock()
+
+	messages := make([]*CorrespondenceTableMessage, 0)
+	for _, tableID := range tableIDs {
+		t, exists := getTableAndLock(ctx, nil, tableID, true, true)
+		if !exists {
+			continue
+		}
+		if t.IsOngoingCorrespondenceGame() {
+			messages = append(messages, makeCorrespondenceTableMessage(s, t))
+		}
+		t.Unlock(ctx)
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].YourTurn != messages[j].YourTurn {
+			return messages[i].YourTurn
+		}
+		return messages[i].Deadline.Before(messages[j].Deadline)
+	})
+
+	s.Emit("correspondenceTableList", messages)
+}
@@ -94,7 +94,7 @@ func updateStatsFromGameHistory(gameHistory *GameHistory) {
 	// Local variables
 	variant := variants[gameHistory.Options.VariantName]
 	// 2-player is at index 0, 3-player is at index 1, etc.
-	bestScoreIndex := gameHistory.Options.NumPlayers - 2
+	bestScoreIndex := gameHistory.Options.NumPlayers - MinPlayers
 
 	// Update the variant-specific stats for each player
 	modifier := gameHistory.Options.GetModifier()
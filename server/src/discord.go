@@ -14,6 +14,7 @@ var (
 	discordToken                string
 	discordGuildID              string
 	discordChannelSyncWithLobby string
+	discordChannelModerators    string
 	discordBotID                string
 	discordIsReady              = abool.New()
 )
@@ -44,6 +45,9 @@ func discordInit() {
 		return
 	}
 
+	// The moderator channel is optional (it is used for notifications about reports)
+	discordChannelModerators = os.Getenv("DISCORD_CHANNEL_MODERATORS")
+
 	// Initialize the command map
 	discordCommandInit()
 
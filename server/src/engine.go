@@ -0,0 +1,14 @@
+// The rules of the game are implemented in the engine package
+// The types that are used throughout the server are aliased here for convenience
+
+package main
+
+import (
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+type Variant = engine.Variant
+type Suit = engine.Suit
+type Card = engine.Card
+type CardIdentity = engine.CardIdentity
+type LegalAction = engine.LegalAction
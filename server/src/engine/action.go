@@ -0,0 +1,39 @@
+package engine
+
+// Action is a database-compatible representation of an in-game move
+// It is also used as the input to the "Apply()" method
+type Action struct {
+	Type   int `json:"type"`
+	Target int `json:"target"`
+	Value  int `json:"value"`
+}
+
+// NewAction returns an action with any nonsensical fields zeroed out
+// (e.g. the value of a play or a discard)
+func NewAction(actionType int, target int, value int) *Action {
+	if actionType == ActionTypePlay || actionType == ActionTypeDiscard {
+		// The value is unused for play and discard actions
+		value = 0
+	}
+
+	return &Action{
+		Type:   actionType,
+		Target: target,
+		Value:  value,
+	}
+}
+
+type Clue struct {
+	Type  int `json:"type"`
+	Value int `json:"value"`
+}
+
+func NewClue(a *Action) Clue {
+	return Clue{
+		// A color clue is action type 2
+		// A rank clue is action type 3
+		// Remap these to 0 and 1, respectively
+		Type:  a.Type - 2,
+		Value: a.Value,
+	}
+}
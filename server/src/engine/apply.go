@@ -0,0 +1,483 @@
+package engine
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	actionFunctions = map[int]func(*State, *Action, *Player) error{
+		ActionTypePlay:      (*State).actionPlay,
+		ActionTypeDiscard:   (*State).actionDiscard,
+		ActionTypeColorClue: (*State).actionClue,
+		ActionTypeRankClue:  (*State).actionClue,
+		ActionTypeEndGame:   (*State).actionEndGame,
+	}
+)
+
+// Apply performs an action on behalf of the active player
+// (or an "end game" action on behalf of the server)
+// It returns the resulting state along with the events that describe what happened
+// The receiver is never modified; if the action is illegal, an error is returned that explains why
+// (the error message is suitable to show to the player)
+func (s *State) Apply(a *Action) (*State, []Event, error) {
+	return s.ApplyAs(a, s.ActivePlayerIndex)
+}
+
+// ApplyAs is the same as "Apply()", but the action is performed on behalf of a specific player
+// Only "end game" actions can be performed by a player whose turn it is not
+// (e.g. when a player terminates the game)
+func (s *State) ApplyAs(a *Action, playerIndex int) (*State, []Event, error) {
+	s2 := s.Clone()
+	if err := s2.apply(a, playerIndex); err != nil {
+		return s, nil, err
+	}
+
+	return s2, s2.flushEvents(), nil
+}
+
+func (s *State) apply(a *Action, playerIndex int) error {
+	// Validate that the game is not over
+	if s.EndCondition > EndConditionInProgress {
+		return errors.New("The game is already over, so you cannot perform an action.")
+	}
+
+	// Validate the player
+	if playerIndex < 0 || playerIndex >= len(s.Players) {
+		return errors.New("That is not a valid player index.")
+	}
+	if a.Type != ActionTypeEndGame && playerIndex != s.ActivePlayerIndex {
+		return errors.New("It is not your turn, so you cannot perform an action.")
+	}
+	p := s.Players[playerIndex]
+
+	if a.Type != ActionTypeEndGame {
+		// Validate that a player is not doing an illegal action for their character
+		if err := s.characterValidateAction(a, p); err != nil {
+			return err
+		}
+		if err := s.characterValidateSecondAction(a, p); err != nil {
+			return err
+		}
+	}
+
+	// Do different tasks depending on the action
+	if actionFunction, ok := actionFunctions[a.Type]; ok {
+		if err := actionFunction(s, a, p); err != nil {
+			return err
+		}
+	} else {
+		return errors.New("That is not a valid action type.")
+	}
+
+	// Do post-action tasks
+	s.characterPostAction(a, p)
+	s.emit(EventStatus{
+		Type:     "status",
+		Clues:    s.ClueTokens,
+		Score:    s.Score,
+		MaxScore: s.MaxScore,
+	})
+
+	// If a player has just taken their final turn,
+	// mark all of the cards in their hand as not able to be played
+	// (but don't do this if we are in an end game that has a custom amount of turns)
+	if s.Options.DetrimentalCharacters {
+		if s.characterHasTakenLastTurn() {
+			for _, c := range p.Hand {
+				c.CannotBePlayed = true
+			}
+		}
+	} else if s.EndTurn != -1 &&
+		s.EndTurn != s.Turn+len(s.Players)+1 {
+
+		for _, c := range p.Hand {
+			c.CannotBePlayed = true
+		}
+	}
+
+	// Increment the turn
+	// (but don't increment it if we are on a characters that take two turns in a row)
+	if !s.characterNeedsToTakeSecondTurn(a, p) {
+		s.Turn++
+		if s.TurnsInverted {
+			// In Golang, "%" will give the remainder and not the modulus,
+			// so we need to ensure that the result is not negative or we will get a
+			// "index out of range" error
+			s.ActivePlayerIndex += len(s.Players)
+			s.ActivePlayerIndex = (s.ActivePlayerIndex - 1) % len(s.Players)
+		} else {
+			s.ActivePlayerIndex = (s.ActivePlayerIndex + 1) % len(s.Players)
+		}
+	}
+	nextPlayer := s.Players[s.ActivePlayerIndex]
+
+	// Check for character-related softlocks
+	// (we will set the strikes to 3 if there is a softlock)
+	s.characterCheckSoftlock(nextPlayer)
+
+	// Check for end game states
+	if s.CheckEnd() {
+		s.emit(EventGameOver{
+			Type:         "gameOver",
+			EndCondition: s.EndCondition,
+			PlayerIndex:  s.EndPlayer,
+		})
+	}
+
+	// Send the new turn
+	currentPlayerIndex := s.ActivePlayerIndex
+	if s.EndCondition > EndConditionInProgress {
+		currentPlayerIndex = -1
+	}
+	s.emit(EventTurn{
+		Type:               "turn",
+		Num:                s.Turn,
+		CurrentPlayerIndex: currentPlayerIndex,
+	})
+
+	return nil
+}
+
+func (s *State) actionPlay(a *Action, p *Player) error {
+	// Validate "Detrimental Character Assignment" restrictions
+	if err := s.characterCheckPlay(a, p); err != nil {
+		return err
+	}
+
+	// Validate deck plays
+	if s.Options.DeckPlays &&
+		s.DeckIndex == len(s.Deck)-1 && // There is 1 card left in the deck
+		a.Target == s.DeckIndex { // The target is the last card left in the deck
+
+		s.playDeck(p)
+		return nil
+	}
+
+	// Validate that the card is in their hand
+	if !p.InHand(a.Target) {
+		return errors.New("You cannot play a card that is not in your hand.")
+	}
+
+	c := s.removeCard(p, a.Target)
+	s.playCard(p, c)
+	s.drawCard(p)
+
+	return nil
+}
+
+func (s *State) actionDiscard(a *Action, p *Player) error {
+	// Validate that the card is in their hand
+	if !p.InHand(a.Target) {
+		return errors.New("You cannot play a card that is not in your hand.")
+	}
+
+	// Validate that the team is not at the maximum amount of clues
+	if s.AtMaxClueTokens() {
+		return errors.New("You cannot discard while the team has " +
+			strconv.Itoa(s.Options.GetMaxClueTokens()) + " clues.")
+	}
+
+	// Validate "Detrimental Character Assignment" restrictions
+	if err := s.characterCheckDiscard(p); err != nil {
+		return err
+	}
+
+	s.ClueTokens++
+	c := s.removeCard(p, a.Target)
+	s.discardCard(p, c)
+	s.drawCard(p)
+
+	return nil
+}
+
+func (s *State) actionClue(a *Action, p *Player) error {
+	// Validate that the target of the clue is sane
+	if a.Target < 0 || a.Target > len(s.Players)-1 {
+		return errors.New("That is an invalid clue target.")
+	}
+
+	// Validate that the player is not giving a clue to themselves
+	if s.ActivePlayerIndex == a.Target {
+		return errors.New("You cannot give a clue to yourself.")
+	}
+
+	// Validate that there are clues available to use
+	if s.ClueTokens < s.Variant.GetAdjustedClueTokens(1) {
+		return errors.New("You need at least 1 clue token available in order to give a clue.")
+	}
+
+	// Convert the incoming data to a clue object
+	clue := NewClue(a)
+
+	// Validate the clue value
+	if clue.Type == ClueTypeColor {
+		if clue.Value < 0 || clue.Value > len(s.Variant.ClueColors)-1 {
+			return errors.New("You cannot give a color clue with a value of " +
+				"\"" + strconv.Itoa(clue.Value) + "\".")
+		}
+	} else if clue.Type == ClueTypeRank {
+		if !intInSlice(clue.Value, s.Variant.ClueRanks) {
+			return errors.New("You cannot give a rank clue with a value of " +
+				"\"" + strconv.Itoa(clue.Value) + "\".")
+		}
+	} else {
+		return errors.New("The clue type of " + strconv.Itoa(clue.Type) + " is invalid.")
+	}
+
+	// Validate special variant restrictions
+	if s.Variant.AlternatingClues && clue.Type == s.LastClueTypeGiven {
+		return errors.New("You cannot give two clues of the same time in a row in this variant.")
+	}
+
+	// Validate "Detrimental Character Assignment" restrictions
+	if err := s.characterValidateClue(a, p); err != nil {
+		return err
+	}
+
+	// Validate that the clue touches at least one card
+	p2 := s.Players[a.Target] // The target of the clue
+	touchedAtLeastOneCard := false
+	for _, c := range p2.Hand {
+		// Prevent characters from cluing cards that they are not supposed to see
+		if !s.characterSeesCard(p, p2, c.Order) {
+			continue
+		}
+
+		if s.Variant.IsCardTouched(clue, c) {
+			touchedAtLeastOneCard = true
+			break
+		}
+	}
+	if !touchedAtLeastOneCard &&
+		// Make an exception if they have the optional setting for "Empty Clues" turned on
+		!s.Options.EmptyClues &&
+		// Make an exception for variants where color clues are always allowed
+		(!s.Variant.ColorCluesTouchNothing || clue.Type != ClueTypeColor) &&
+		// Make an exception for variants where rank clues are always allowed
+		(!s.Variant.RankCluesTouchNothing || clue.Type != ClueTypeRank) {
+
+		return errors.New("You cannot give a clue that touches 0 cards in the hand.")
+	}
+
+	s.giveClue(p, a)
+
+	return nil
+}
+
+func (s *State) actionEndGame(a *Action, p *Player) error {
+	// An "endGame" action is a special action type sent by the server to itself
+	// The value will correspond to the end condition (see "endCondition" in "constants.go")
+	// The target will correspond to the index of the player who ended the game
+
+	// Validate the value
+	if a.Value != EndConditionTimeout &&
+		a.Value != EndConditionTerminated &&
+		a.Value != EndConditionIdleTimeout {
+
+		return errors.New("That is not a valid value for the end game action.")
+	}
+
+	// Mark that the game should be ended
+	s.EndCondition = a.Value
+	s.EndPlayer = a.Target
+
+	return nil
+}
+
+/*
+	Subroutines
+*/
+
+func (s *State) giveClue(p *Player, a *Action) {
+	clue := NewClue(a) // Convert the incoming data to a clue object
+
+	// Keep track that someone clued (i.e. doing 1 clue costs 1 "Clue Token")
+	s.ClueTokens -= s.Variant.GetAdjustedClueTokens(1)
+	s.LastClueTypeGiven = clue.Type
+
+	// Apply the positive and negative clues to the cards in the hand
+	p2 := s.Players[a.Target] // The target of the clue
+	cardsTouched := make([]int, 0)
+	for _, c := range p2.Hand {
+		if s.Variant.IsCardTouched(clue, c) {
+			c.Touched = true
+			cardsTouched = append(cardsTouched, c.Order)
+		}
+	}
+
+	s.emit(EventClue{
+		Type:   "clue",
+		Clue:   clue,
+		Giver:  p.Index,
+		List:   cardsTouched,
+		Target: a.Target,
+		Turn:   s.Turn,
+	})
+
+	// Do post-clue tasks
+	s.characterPostClue(a, p)
+
+	// Handle the "Card Cycling" feature
+	if s.Options.CardCycle {
+		p.CycleHand()
+	}
+}
+
+func (s *State) removeCard(p *Player, target int) *Card {
+	// Get the target card
+	i := p.GetCardIndex(target)
+	c := p.Hand[i]
+
+	// Mark what the "slot" number is
+	// e.g. slot 1 is the newest (left-most) card, which is index 5 (in a 3-player game)
+	c.Slot = p.GetCardSlot(target)
+
+	// Remove it from the hand
+	p.Hand = append(p.Hand[:i], p.Hand[i+1:]...)
+
+	s.characterPostRemoveCard(p, c)
+
+	return c
+}
+
+func (s *State) playCard(p *Player, c *Card) {
+	// Find out if this successfully plays
+	var failed bool
+	if s.Variant.HasReversedSuits() {
+		// In the "Up or Down" and "Reversed" variants, cards might not play in order
+		failed = s.variantReversiblePlay(c)
+	} else {
+		failed = c.Rank != s.Stacks[c.SuitIndex]+1
+	}
+
+	// Handle "Detrimental Character Assignment" restrictions
+	if s.characterCheckMisplay(p, c) { // (this returns true if it should misplay)
+		failed = true
+	}
+
+	// Handle if the card does not play
+	if failed {
+		c.Failed = true
+		s.Strikes++
+
+		s.emit(EventStrike{
+			Type:  "strike",
+			Num:   s.Strikes,
+			Turn:  s.Turn,
+			Order: c.Order,
+		})
+
+		s.discardCard(p, c)
+		return
+	}
+
+	// Handle successful card plays
+	c.Played = true
+	s.Score++
+	s.Stacks[c.SuitIndex] = c.Rank
+	if c.Rank == 0 {
+		s.Stacks[c.SuitIndex] = -1 // A rank 0 card is the "START" card
+	}
+
+	s.emit(EventPlay{
+		Type:        "play",
+		PlayerIndex: p.Index,
+		Order:       c.Order,
+		SuitIndex:   c.SuitIndex,
+		Rank:        c.Rank,
+	})
+
+	// Give the team a clue if the final card of the suit was played
+	// (this will always be a 5 unless it is a custom variant)
+	extraClue := c.Rank == 5
+
+	// Handle custom variants that do not play in order from 1 to 5
+	if s.Variant.HasReversedSuits() {
+		extraClue = (c.Rank == 5 || c.Rank == 1) &&
+			s.PlayStackDirections[c.SuitIndex] == StackDirectionFinished
+	}
+
+	if extraClue {
+		// Some variants do not grant an extra clue when successfully playing a 5
+		if s.Variant.ShouldGiveClueTokenForPlaying5() {
+			s.ClueTokens++
+		}
+
+		// The extra clue is wasted if the team is at the maximum amount of clues already
+		clueLimit := s.GetClueTokenLimit()
+		if s.ClueTokens > clueLimit {
+			s.ClueTokens = clueLimit
+		}
+	}
+
+	// In some variants, playing a card has the potential to reduce the maximum score
+	newMaxScore := s.GetMaxScore()
+	if newMaxScore < s.MaxScore {
+		// Decrease the maximum score possible for this game
+		s.MaxScore = newMaxScore
+	}
+}
+
+func (s *State) discardCard(p *Player, c *Card) {
+	// Mark that the card is discarded
+	c.Discarded = true
+
+	s.emit(EventDiscard{
+		Type:        "discard",
+		PlayerIndex: p.Index,
+		Order:       c.Order,
+		Rank:        c.Rank,
+		SuitIndex:   c.SuitIndex,
+		Failed:      c.Failed,
+	})
+
+	// This could have been a discard (or misplay) or a card needed to get the maximum score
+	newMaxScore := s.GetMaxScore()
+	if newMaxScore < s.MaxScore {
+		// Decrease the maximum score possible for this game
+		s.MaxScore = newMaxScore
+	}
+}
+
+func (s *State) drawCard(p *Player) {
+	// Don't draw any more cards if the deck is empty
+	if s.DeckIndex >= len(s.Deck) {
+		return
+	}
+
+	// Put it in the player's hand
+	c := s.Deck[s.DeckIndex]
+	s.DeckIndex++
+	p.Hand = append(p.Hand, c)
+
+	s.emit(EventDraw{
+		Type:        "draw",
+		PlayerIndex: p.Index,
+		Order:       c.Order,
+		SuitIndex:   c.SuitIndex,
+		Rank:        c.Rank,
+	})
+
+	// If a card slides from slot 1 to slot 2, we might need to reveal the identity of the card to
+	// a player with the "Slow-Witted" detrimental character
+	s.characterSendCardIdentityOfSlot2(p)
+
+	// Check to see if that was the last card drawn
+	// (in "All or Nothing" games, the game goes on until all the cards are played)
+	if s.DeckIndex >= len(s.Deck) && !s.Options.AllOrNothing {
+		// Mark the turn upon which the game will end
+		s.EndTurn = s.Turn + len(s.Players) + 1
+		s.characterAdjustEndTurn()
+	}
+}
+
+func (s *State) playDeck(p *Player) {
+	// Make the player draw the final card in the deck
+	s.drawCard(p)
+
+	// Play the card freshly drawn
+	c := s.removeCard(p, len(s.Deck)-1) // The final card
+	c.Slot = -1
+	s.playCard(p, c)
+}
@@ -0,0 +1,429 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	// Alice has the cards 0 through 4 and Bob has the cards 5 through 9
+	deck := "r1 r2 y1 b4 g5 " +
+		"r1 y2 g1 b1 p1 " +
+		"r3 y3 g2 b2 p2 r4 y4 g3 b3 p3"
+
+	// Each player gives the other a 1 clue four times, which uses up every clue token
+	allClues := make([]*Action, 0)
+	for i := 0; i < 4; i++ {
+		allClues = append(allClues, NewAction(ActionTypeRankClue, 1, 1))
+		allClues = append(allClues, NewAction(ActionTypeRankClue, 0, 1))
+	}
+
+	// Alice misplays a red 2 and then Bob misplays a yellow 2
+	twoStrikes := []*Action{
+		NewAction(ActionTypePlay, 1, 0),
+		NewAction(ActionTypePlay, 6, 0),
+	}
+
+	testCases := []struct {
+		description string
+		// The actions to perform before the action that is being tested
+		actions []*Action
+		action  *Action
+		err     bool
+		// The expected state after the action (if it is legal)
+		score        int
+		strikes      int
+		clueTokens   int
+		endCondition int
+		// The cards that the clue touches (if the action is a clue)
+		touched []int
+	}{
+		{
+			description: "playing a card",
+			action:      NewAction(ActionTypePlay, 0, 0),
+			score:       1,
+			clueTokens:  8,
+		},
+		{
+			description: "misplaying a card",
+			action:      NewAction(ActionTypePlay, 1, 0),
+			strikes:     1,
+			clueTokens:  8,
+		},
+		{
+			description: "playing a card from another hand",
+			action:      NewAction(ActionTypePlay, 5, 0),
+			err:         true,
+		},
+		{
+			description: "discarding a card",
+			actions: []*Action{
+				NewAction(ActionTypeRankClue, 1, 1),
+				NewAction(ActionTypeRankClue, 0, 1),
+			},
+			action:     NewAction(ActionTypeDiscard, 3, 0),
+			clueTokens: 7,
+		},
+		{
+			description: "discarding at the maximum amount of clues",
+			action:      NewAction(ActionTypeDiscard, 3, 0),
+			err:         true,
+		},
+		{
+			description: "discarding a card from another hand",
+			actions:     []*Action{NewAction(ActionTypeRankClue, 1, 1)},
+			action:      NewAction(ActionTypeDiscard, 0, 0),
+			err:         true,
+		},
+		{
+			description: "giving a color clue",
+			action:      NewAction(ActionTypeColorClue, 1, 0),
+			clueTokens:  7,
+			touched:     []int{5},
+		},
+		{
+			description: "giving a rank clue",
+			action:      NewAction(ActionTypeRankClue, 1, 1),
+			clueTokens:  7,
+			touched:     []int{5, 7, 8, 9},
+		},
+		{
+			description: "giving a clue to yourself",
+			action:      NewAction(ActionTypeColorClue, 0, 0),
+			err:         true,
+		},
+		{
+			description: "giving a clue to a player who does not exist",
+			action:      NewAction(ActionTypeColorClue, 2, 0),
+			err:         true,
+		},
+		{
+			description: "giving a clue that touches no cards",
+			action:      NewAction(ActionTypeRankClue, 1, 3),
+			err:         true,
+		},
+		{
+			description: "giving a clue with an invalid color",
+			action:      NewAction(ActionTypeColorClue, 1, 5),
+			err:         true,
+		},
+		{
+			description: "giving a clue with an invalid rank",
+			action:      NewAction(ActionTypeRankClue, 1, 6),
+			err:         true,
+		},
+		{
+			description: "giving a clue without any clue tokens",
+			actions:     allClues,
+			action:      NewAction(ActionTypeRankClue, 1, 1),
+			err:         true,
+		},
+		{
+			description:  "getting the final strike",
+			actions:      twoStrikes,
+			action:       NewAction(ActionTypePlay, 4, 0),
+			strikes:      3,
+			clueTokens:   8,
+			endCondition: EndConditionStrikeout,
+		},
+		{
+			description: "acting after the game is over",
+			actions:     append(twoStrikes, NewAction(ActionTypePlay, 4, 0)),
+			action:      NewAction(ActionTypePlay, 0, 0),
+			err:         true,
+		},
+		{
+			description: "an invalid action type",
+			action:      NewAction(ActionTypeEndGame+1, 0, 0),
+			err:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s := newTestState(t, newTestNoVariant(), &Options{}, 2, deck) // nolint: exhaustivestruct
+		s = applyTestActions(t, tc.description, s, tc.actions)
+		turn := s.Turn
+		clueTokens := s.ClueTokens
+
+		s2, events, err := s.Apply(tc.action)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: the action was allowed", tc.description)
+			} else if s2 != s || events != nil {
+				t.Errorf("%s: an illegal action did not return the original state",
+					tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: the action was not allowed: %v", tc.description, err)
+			continue
+		}
+
+		// The original state must not be modified
+		if s.Turn != turn || s.ClueTokens != clueTokens {
+			t.Errorf("%s: the original state was modified", tc.description)
+		}
+
+		if s2.Turn != turn+1 {
+			t.Errorf("%s: the turn is %d instead of %d", tc.description, s2.Turn, turn+1)
+		}
+		if s2.Score != tc.score {
+			t.Errorf("%s: the score is %d instead of %d", tc.description, s2.Score, tc.score)
+		}
+		if s2.Strikes != tc.strikes {
+			t.Errorf("%s: there are %d strikes instead of %d", tc.description, s2.Strikes,
+				tc.strikes)
+		}
+		if s2.ClueTokens != tc.clueTokens {
+			t.Errorf("%s: there are %d clue tokens instead of %d", tc.description,
+				s2.ClueTokens, tc.clueTokens)
+		}
+		if s2.EndCondition != tc.endCondition {
+			t.Errorf("%s: the end condition is %d instead of %d", tc.description,
+				s2.EndCondition, tc.endCondition)
+		}
+		if tc.touched != nil {
+			touched := getTestClueTouched(events)
+			if !reflect.DeepEqual(touched, tc.touched) {
+				t.Errorf("%s: the clue touched %v instead of %v", tc.description, touched,
+					tc.touched)
+			}
+		}
+	}
+}
+
+func TestApplyAs(t *testing.T) {
+	deck := "r1 r2 y1 b4 g5 " +
+		"r1 y2 g1 b1 p1 " +
+		"r3 y3 g2 b2 p2"
+
+	testCases := []struct {
+		description string
+		playerIndex int
+		action      *Action
+		err         bool
+		// The expected state after the action (if it is legal)
+		endCondition int
+		endPlayer    int
+	}{
+		{
+			description:  "playing a card on your turn",
+			playerIndex:  0,
+			action:       NewAction(ActionTypePlay, 0, 0),
+			endCondition: EndConditionInProgress,
+			endPlayer:    -1,
+		},
+		{
+			description: "playing a card out of turn",
+			playerIndex: 1,
+			action:      NewAction(ActionTypePlay, 5, 0),
+			err:         true,
+		},
+		{
+			description: "giving a clue out of turn",
+			playerIndex: 1,
+			action:      NewAction(ActionTypeRankClue, 0, 1),
+			err:         true,
+		},
+		{
+			description:  "terminating the game out of turn",
+			playerIndex:  1,
+			action:       NewAction(ActionTypeEndGame, 1, EndConditionTerminated),
+			endCondition: EndConditionTerminated,
+			endPlayer:    1,
+		},
+		{
+			description:  "running out of time",
+			playerIndex:  0,
+			action:       NewAction(ActionTypeEndGame, 0, EndConditionTimeout),
+			endCondition: EndConditionTimeout,
+			endPlayer:    0,
+		},
+		{
+			description: "ending the game with an end condition that the server does not send",
+			playerIndex: 0,
+			action:      NewAction(ActionTypeEndGame, 0, EndConditionNormal),
+			err:         true,
+		},
+		{
+			description: "acting as a player who does not exist",
+			playerIndex: 2,
+			action:      NewAction(ActionTypeEndGame, 2, EndConditionTerminated),
+			err:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s := newTestState(t, newTestNoVariant(), &Options{}, 2, deck) // nolint: exhaustivestruct
+
+		s2, _, err := s.ApplyAs(tc.action, tc.playerIndex)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: the action was allowed", tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: the action was not allowed: %v", tc.description, err)
+			continue
+		}
+
+		if s2.EndCondition != tc.endCondition {
+			t.Errorf("%s: the end condition is %d instead of %d", tc.description,
+				s2.EndCondition, tc.endCondition)
+		}
+		if s2.EndPlayer != tc.endPlayer {
+			t.Errorf("%s: the end player is %d instead of %d", tc.description, s2.EndPlayer,
+				tc.endPlayer)
+		}
+	}
+}
+
+// When the last card is drawn, every player gets one more turn
+func TestApplyFinalRound(t *testing.T) {
+	// Alice has the cards 0 through 4 and Bob has the cards 5 through 9,
+	// and there are two cards left in the deck
+	deck := "r1 y2 g2 b2 p2 " +
+		"r2 y1 g1 b1 p1 " +
+		"r3 y3"
+
+	// Alice plays the red 1, Bob plays the red 2 (which draws the last card),
+	// then Alice plays the red 3 and Bob gives a clue
+	// With deck plays, the red 3 is the last card of the deck instead
+	deckPlaysDeck := "r1 y2 g2 b2 p2 " +
+		"r2 y1 g1 b1 p1 " +
+		"y3 g3 r3"
+
+	lastCardDrawn := []*Action{
+		NewAction(ActionTypePlay, 0, 0),
+		NewAction(ActionTypePlay, 5, 0),
+	}
+	finalRound := append(lastCardDrawn,
+		NewAction(ActionTypePlay, 10, 0),
+		NewAction(ActionTypeRankClue, 0, 2),
+	)
+
+	testCases := []struct {
+		description string
+		options     *Options
+		deck        string
+		actions     []*Action
+		// If true, the final action must be illegal
+		err bool
+		// The expected state after every legal action
+		score        int
+		endTurn      int
+		endCondition int
+		// The cards that were still in a hand after their player took their final turn
+		cannotBePlayed []int
+	}{
+		{
+			description:  "drawing the last card",
+			options:      &Options{}, // nolint: exhaustivestruct
+			deck:         deck,
+			actions:      lastCardDrawn,
+			score:        2,
+			endTurn:      4,
+			endCondition: EndConditionInProgress,
+		},
+		{
+			description:  "the final turn of the first player",
+			options:      &Options{}, // nolint: exhaustivestruct
+			deck:         deck,
+			actions:      finalRound[:3],
+			score:        3,
+			endTurn:      4,
+			endCondition: EndConditionInProgress,
+			// Bob still has a turn left, so only Alice's cards are marked
+			cannotBePlayed: []int{1, 2, 3, 4},
+		},
+		{
+			description:    "the end of the final round",
+			options:        &Options{}, // nolint: exhaustivestruct
+			deck:           deck,
+			actions:        finalRound,
+			score:          3,
+			endTurn:        4,
+			endCondition:   EndConditionNormal,
+			cannotBePlayed: []int{1, 2, 3, 4, 6, 7, 8, 9, 11},
+		},
+		{
+			description:    "acting after the final round",
+			options:        &Options{}, // nolint: exhaustivestruct
+			deck:           deck,
+			actions:        append(finalRound, NewAction(ActionTypeRankClue, 1, 2)),
+			err:            true,
+			score:          3,
+			endTurn:        4,
+			endCondition:   EndConditionNormal,
+			cannotBePlayed: []int{1, 2, 3, 4, 6, 7, 8, 9, 11},
+		},
+		{
+			description: "playing the deck",
+			options:     &Options{DeckPlays: true}, // nolint: exhaustivestruct
+			deck:        deckPlaysDeck,
+			// Alice draws the red 3 from the deck and plays it on the same turn
+			actions: []*Action{
+				NewAction(ActionTypePlay, 0, 0),
+				NewAction(ActionTypePlay, 5, 0),
+				NewAction(ActionTypePlay, 12, 0),
+			},
+			score:        3,
+			endTurn:      5,
+			endCondition: EndConditionInProgress,
+		},
+		{
+			description: "playing the deck when there is more than one card left",
+			options:     &Options{DeckPlays: true}, // nolint: exhaustivestruct
+			deck:        deckPlaysDeck,
+			actions:     []*Action{NewAction(ActionTypePlay, 10, 0)},
+			err:         true,
+			endTurn:     -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		s := newTestState(t, newTestNoVariant(), tc.options, 2, tc.deck)
+		actions := tc.actions
+		if tc.err {
+			actions = actions[:len(actions)-1]
+		}
+		s = applyTestActions(t, tc.description, s, actions)
+		if tc.err {
+			if _, _, err := s.Apply(tc.actions[len(tc.actions)-1]); err == nil {
+				t.Errorf("%s: the final action was allowed", tc.description)
+			}
+		}
+
+		if s.Score != tc.score {
+			t.Errorf("%s: the score is %d instead of %d", tc.description, s.Score, tc.score)
+		}
+		if s.EndTurn != tc.endTurn {
+			t.Errorf("%s: the end turn is %d instead of %d", tc.description, s.EndTurn,
+				tc.endTurn)
+		}
+		if s.EndCondition != tc.endCondition {
+			t.Errorf("%s: the end condition is %d instead of %d", tc.description,
+				s.EndCondition, tc.endCondition)
+		}
+		for _, c := range s.Deck {
+			if c.CannotBePlayed != intInSlice(c.Order, tc.cannotBePlayed) {
+				t.Errorf("%s: card %d has a \"CannotBePlayed\" value of %t", tc.description,
+					c.Order, c.CannotBePlayed)
+			}
+		}
+	}
+}
+
+// getTestClueTouched returns the cards that were touched by the clue in the provided events
+// (or nil if there is no clue)
+func getTestClueTouched(events []Event) []int {
+	for _, e := range events {
+		if clueEvent, ok := e.(EventClue); ok {
+			return clueEvent.List
+		}
+	}
+
+	return nil
+}
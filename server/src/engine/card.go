@@ -1,4 +1,4 @@
-package main
+package engine
 
 import (
 	"strconv"
@@ -36,8 +36,7 @@ func NewCard(suit int, rank int) *Card {
 	return c
 }
 
-func (c *Card) Name(g *Game) string {
-	variant := variants[g.Options.VariantName]
+func (c *Card) Name(variant *Variant) string {
 	suit := variant.Suits[c.SuitIndex]
 	name := suit.Name
 	name += " "
@@ -48,3 +47,9 @@ func (c *Card) Name(g *Game) string {
 	}
 	return name
 }
+
+// CardIdentity is a bare-bones version of a card
+type CardIdentity struct {
+	SuitIndex int `json:"suitIndex"`
+	Rank      int `json:"rank"`
+}
@@ -0,0 +1,522 @@
+// The rules for the "Detrimental Character Assignments" option
+// (the assignments themselves are decided by the caller)
+
+package engine
+
+import (
+	"errors"
+	"strconv"
+)
+
+// characterValidateAction returns an error if validation fails
+func (s *State) characterValidateAction(a *Action, p *Player) error {
+	if !s.Options.DetrimentalCharacters {
+		return nil
+	}
+
+	if p.Character == "Vindictive" && // 9
+		p.CharacterMetadata == 0 &&
+		(a.Type != ActionTypeColorClue && a.Type != ActionTypeRankClue) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you must give a clue if you have been given a clue on this go-around.")
+	} else if p.Character == "Insistent" && // 13
+		p.CharacterMetadata != -1 &&
+		(a.Type != ActionTypeColorClue && a.Type != ActionTypeRankClue) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you must continue to clue the same card until it is played or discarded.")
+	} else if p.Character == "Impulsive" && // 17
+		p.CharacterMetadata == 0 &&
+		(a.Type != ActionTypePlay ||
+			a.Target != p.Hand[len(p.Hand)-1].Order) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you must play your slot 1 card after it has been clued.")
+	} else if p.Character == "Indolent" && // 18
+		a.Type == ActionTypePlay &&
+		p.CharacterMetadata == 0 {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you cannot play a card if you played one in the last round.")
+	} else if p.Character == "Stubborn" && // 28
+		(a.Type == p.CharacterMetadata ||
+			(a.Type == ActionTypeColorClue && p.CharacterMetadata == ActionTypeRankClue) ||
+			(a.Type == ActionTypeRankClue && p.CharacterMetadata == ActionTypeColorClue)) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you cannot perform the same kind of action that the previous player did.")
+	}
+
+	return nil
+}
+
+// characterValidateSecondAction returns an error if validation fails
+func (s *State) characterValidateSecondAction(a *Action, p *Player) error {
+	if !s.Options.DetrimentalCharacters {
+		return nil
+	}
+
+	if p.CharacterMetadata == -1 {
+		return nil
+	}
+
+	if p.Character == "Genius" { // 24
+		if a.Type != ActionTypeRankClue {
+			return errors.New("You are " + p.Character + ", so you must now give a rank clue.")
+		}
+
+		if a.Target != p.CharacterMetadata {
+			return errors.New("You are " + p.Character + ", " +
+				"so you must give the second clue to the same player.")
+		}
+	} else if p.Character == "Panicky" && // 26
+		a.Type != ActionTypeDiscard {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you must discard again since there are 4 or less clues available.")
+	}
+
+	return nil
+}
+
+// characterValidateClue returns an error if validation fails
+func (s *State) characterValidateClue(a *Action, p *Player) error {
+	if !s.Options.DetrimentalCharacters {
+		return nil
+	}
+
+	// Local variables
+	clue := NewClue(a)        // Convert the incoming data to a clue object
+	p2 := s.Players[a.Target] // Get the target of the clue
+
+	if p.Character == "Fuming" && // 0
+		clue.Type == ClueTypeColor &&
+		clue.Value != p.CharacterMetadata {
+
+		return errors.New("You are " + p.Character + ", so you can not give that type of clue.")
+	} else if p.Character == "Dumbfounded" && // 1
+		clue.Type == ClueTypeRank &&
+		clue.Value != p.CharacterMetadata {
+
+		return errors.New("You are " + p.Character + ", so you can not give that type of clue.")
+	} else if p.Character == "Inept" { // 2
+		cardsTouched := p2.FindCardsTouchedByClue(s.Variant, clue)
+		for _, order := range cardsTouched {
+			c := s.Deck[order]
+			if c.SuitIndex == p.CharacterMetadata {
+				return errors.New("You are " + p.Character + ", " +
+					"so you cannot give clues that touch a specific suit.")
+			}
+		}
+	} else if p.Character == "Awkward" { // 3
+		cardsTouched := p2.FindCardsTouchedByClue(s.Variant, clue)
+		for _, order := range cardsTouched {
+			c := s.Deck[order]
+			if c.Rank == p.CharacterMetadata {
+				return errors.New("You are " + p.Character + ", " +
+					"so you cannot give clues that touch cards with a rank of " +
+					strconv.Itoa(p.CharacterMetadata) + ".")
+			}
+		}
+	} else if p.Character == "Conservative" && // 4
+		len(p2.FindCardsTouchedByClue(s.Variant, clue)) != 1 {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you can only give clues that touch a single card.")
+	} else if p.Character == "Greedy" && // 5
+		len(p2.FindCardsTouchedByClue(s.Variant, clue)) < 2 {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you can only give clues that touch 2+ cards.")
+	} else if p.Character == "Picky" && // 6
+		((clue.Type == ClueTypeRank &&
+			clue.Value%2 == 0) ||
+			(clue.Type == ClueTypeColor &&
+				(clue.Value+1)%2 == 0)) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you can only clue odd numbers or odd colors.")
+	} else if p.Character == "Spiteful" { // 7
+		leftIndex := p.Index + 1
+		if leftIndex == len(s.Players) {
+			leftIndex = 0
+		}
+		if a.Target == leftIndex {
+			return errors.New("You are " + p.Character + ", " +
+				"so you cannot clue the player to your left.")
+		}
+	} else if p.Character == "Insolent" { // 8
+		rightIndex := p.Index - 1
+		if rightIndex == -1 {
+			rightIndex = len(s.Players) - 1
+		}
+		if a.Target == rightIndex {
+			return errors.New("You are " + p.Character + ", " +
+				"so you cannot clue the player to your right.")
+		}
+	} else if p.Character == "Miser" && // 10
+		s.ClueTokens < s.Variant.GetAdjustedClueTokens(4) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you cannot give a clue unless there are 4 or more clues available.")
+	} else if p.Character == "Compulsive" && // 11
+		!p2.IsFirstCardTouchedByClue(s.Variant, clue) &&
+		!p2.IsLastCardTouchedByClue(s.Variant, clue) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you can only give a clue if it touches either the newest or oldest card in a hand.")
+	} else if p.Character == "Mood Swings" && // 12
+		p.CharacterMetadata == clue.Type {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so cannot give the same clue type twice in a row.")
+	} else if p.Character == "Insistent" && // 13
+		p.CharacterMetadata != -1 {
+
+		cardsTouched := p2.FindCardsTouchedByClue(s.Variant, clue)
+		touchedInsistentCard := false
+		for _, order := range cardsTouched {
+			c := s.Deck[order]
+			if c.InsistentTouched {
+				touchedInsistentCard = true
+				break
+			}
+		}
+		if !touchedInsistentCard {
+			return errors.New("You are " + p.Character + ", " +
+				"so you must continue to clue a card until it is played or discarded.")
+		}
+	} else if p.Character == "Genius" && // 24
+		p.CharacterMetadata == -1 {
+
+		if s.ClueTokens < s.Variant.GetAdjustedClueTokens(2) {
+			return errors.New("You are " + p.Character + ", " +
+				"so there needs to be at least two clues available for you to give a clue.")
+		}
+
+		if clue.Type != ClueTypeColor {
+			return errors.New("You are " + p.Character + ", so you must give a color clue first.")
+		}
+	}
+
+	if p2.Character == "Vulnerable" && // 14
+		clue.Type == ClueTypeRank &&
+		(clue.Value == 2 || clue.Value == 5) {
+
+		return errors.New("You cannot give a number 2 or number 5 clue to a " + p2.Character +
+			" character.")
+	} else if p2.Character == "Color-Blind" && // 15
+		clue.Type == ClueTypeColor {
+
+		return errors.New("You cannot give that color clue to a " + p2.Character + " character.")
+	}
+
+	return nil
+}
+
+// characterCheckPlay returns an error if the card cannot be played
+func (s *State) characterCheckPlay(a *Action, p *Player) error {
+	if !s.Options.DetrimentalCharacters {
+		return nil
+	}
+
+	if p.Character == "Hesitant" && // 19
+		p.GetCardSlot(a.Target) == 1 {
+
+		return errors.New("You cannot play that card since you are a " + p.Character +
+			" character.")
+	}
+
+	return nil
+}
+
+// characterCheckMisplay returns true if the card should misplay
+func (s *State) characterCheckMisplay(p *Player, c *Card) bool {
+	if !s.Options.DetrimentalCharacters {
+		return false
+	}
+
+	if p.Character == "Follower" { // 31
+		// Look through the stacks to see if two cards of this rank have already been played
+		numPlayedOfThisRank := 0
+		for _, stack := range s.Stacks {
+			if stack >= c.Rank {
+				numPlayedOfThisRank++
+			}
+		}
+		if numPlayedOfThisRank < 2 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// characterCheckDiscard returns an error if the player cannot currently discard
+func (s *State) characterCheckDiscard(p *Player) error {
+	if !s.Options.DetrimentalCharacters {
+		return nil
+	}
+
+	if p.Character == "Anxious" && // 21
+		s.ClueTokens%2 == 0 { // Even amount of clues
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you cannot discard when there is an even number of clues available.")
+	} else if p.Character == "Traumatized" && // 22
+		s.ClueTokens%2 == 1 { // Odd amount of clues
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you cannot discard when there is an odd number of clues available.")
+	} else if p.Character == "Wasteful" && // 23
+		s.ClueTokens >= s.Variant.GetAdjustedClueTokens(2) {
+
+		return errors.New("You are " + p.Character + ", " +
+			"so you cannot discard if there are 2 or more clues available.")
+	}
+
+	return nil
+}
+
+func (s *State) characterPostClue(a *Action, p *Player) {
+	if !s.Options.DetrimentalCharacters {
+		return
+	}
+
+	clue := NewClue(a)        // Convert the incoming data to a clue object
+	p2 := s.Players[a.Target] // Get the target of the clue
+
+	if p.Character == "Mood Swings" { // 12
+		p.CharacterMetadata = clue.Type
+	} else if p.Character == "Insistent" { // 13
+		// Don't do anything if they are already in their "Insistent" state
+		if p.CharacterMetadata == -1 {
+			// Mark that the cards that they clued must be continue to be clued
+			cardsTouched := p2.FindCardsTouchedByClue(s.Variant, clue)
+			for _, order := range cardsTouched {
+				c := s.Deck[order]
+				c.InsistentTouched = true
+			}
+			p.CharacterMetadata = 0 // 0 means that the "Insistent" state is activated
+		}
+	}
+
+	if p2.Character == "Vindictive" { // 9
+		// Store that they have had at least one clue given to them on this go-around of the table
+		p2.CharacterMetadata = 0
+	} else if p2.Character == "Impulsive" && // 17
+		p2.IsFirstCardTouchedByClue(s.Variant, clue) {
+
+		// Store that they had their slot 1 card clued
+		p2.CharacterMetadata = 0
+	}
+}
+
+func (s *State) characterPostRemoveCard(p *Player, c *Card) {
+	if !s.Options.DetrimentalCharacters {
+		return
+	}
+
+	if !c.InsistentTouched {
+		return
+	}
+
+	for _, c2 := range p.Hand {
+		c2.InsistentTouched = false
+	}
+
+	// Find the "Insistent" player and reset their state so that
+	// they are not forced to give a clue on their subsequent turn
+	for _, p2 := range s.Players {
+		if p2.Character == "Insistent" { // 13
+			p2.CharacterMetadata = -1
+			break // Only one player should be Insistent
+		}
+	}
+}
+
+func (s *State) characterPostAction(a *Action, p *Player) {
+	if !s.Options.DetrimentalCharacters {
+		return
+	}
+
+	// Clear the counter for characters that have abilities relating to
+	// a single go-around of the table
+	if p.Character == "Vindictive" { // 9
+		p.CharacterMetadata = -1
+	} else if p.Character == "Impulsive" { // 17
+		p.CharacterMetadata = -1
+	} else if p.Character == "Indolent" { // 18
+		if a.Type == ActionTypePlay {
+			p.CharacterMetadata = 0
+		} else {
+			p.CharacterMetadata = -1
+		}
+	} else if p.Character == "Contrarian" { // 27
+		s.TurnsInverted = !s.TurnsInverted
+	}
+
+	// Store the last action that was performed
+	for _, p2 := range s.Players {
+		if p2.Character == "Stubborn" { // 28
+			p2.CharacterMetadata = a.Type
+		}
+	}
+}
+
+func (s *State) characterNeedsToTakeSecondTurn(a *Action, p *Player) bool {
+	if !s.Options.DetrimentalCharacters {
+		return false
+	}
+
+	if p.Character == "Genius" { // 24
+		// Must clue both a color and a number (uses 2 clues)
+		// The clue target is stored in "p.CharacterMetadata"
+		if a.Type == ActionTypeColorClue {
+			p.CharacterMetadata = a.Target
+			return true
+		} else if a.Type == ActionTypeRankClue {
+			p.CharacterMetadata = -1
+			return false
+		}
+	} else if p.Character == "Panicky" && // 26
+		a.Type == ActionTypeDiscard {
+
+		// After discarding, discards again if there are 4 clues or less
+		// "p.CharacterMetadata" represents the state, which alternates between -1 and 0
+		if p.CharacterMetadata == -1 && s.ClueTokens <= s.Variant.GetAdjustedClueTokens(4) {
+			p.CharacterMetadata = 0
+			return true
+		} else if p.CharacterMetadata == 0 {
+			p.CharacterMetadata = -1
+			return false
+		}
+	}
+
+	return false
+}
+
+// CharacterHideCard returns true if the player should not be able to see the card that was drawn
+func (s *State) CharacterHideCard(e *EventDraw, p *Player) bool {
+	if !s.Options.DetrimentalCharacters {
+		return false
+	}
+
+	if p.Character == "Blind Spot" && e.PlayerIndex == s.GetNextPlayer(p.Index) { // 29
+		return true
+	} else if p.Character == "Oblivious" && e.PlayerIndex == s.GetPreviousPlayer(p.Index) { // 30
+		return true
+	} else if p.Character == "Slow-Witted" { // 33
+		return true
+	}
+
+	return false
+}
+
+func (s *State) characterSendCardIdentityOfSlot2(p *Player) {
+	if !s.Options.DetrimentalCharacters {
+		return
+	}
+
+	if len(p.Hand) <= 1 {
+		return
+	}
+
+	hasSlowWitted := false
+	for _, p2 := range s.Players {
+		if p2.Character == "Slow-Witted" { // 33
+			hasSlowWitted = true
+			break
+		}
+	}
+
+	if hasSlowWitted {
+		// Card information will be scrubbed from the event by the server
+		c := p.Hand[len(p.Hand)-2] // Slot 2
+		s.emit(EventCardIdentity{
+			Type:        "cardIdentity",
+			PlayerIndex: p.Index,
+			Order:       c.Order,
+			SuitIndex:   c.SuitIndex,
+			Rank:        c.Rank,
+		})
+	}
+}
+
+func (s *State) characterAdjustEndTurn() {
+	if !s.Options.DetrimentalCharacters {
+		return
+	}
+
+	// Check to see if anyone is playing as a character that will adjust
+	// the final go-around of the table
+	for _, p := range s.Players {
+		if p.Character == "Contrarian" { // 27
+			// 3 instead of 2 because it should be 2 turns after the final card is drawn
+			s.EndTurn = s.Turn + 3
+		}
+	}
+}
+
+func (s *State) characterHasTakenLastTurn() bool {
+	if s.EndTurn == -1 {
+		return false
+	}
+	originalPlayer := s.ActivePlayerIndex
+	activePlayer := s.ActivePlayerIndex
+	turnsInverted := s.TurnsInverted
+	for turn := s.Turn + 1; turn <= s.EndTurn; turn++ {
+		if turnsInverted {
+			activePlayer += len(s.Players)
+			activePlayer = (activePlayer - 1) % len(s.Players)
+		} else {
+			activePlayer = (activePlayer + 1) % len(s.Players)
+		}
+		if activePlayer == originalPlayer {
+			return false
+		}
+		if s.Players[activePlayer].Character == "Contrarian" { // 27
+			turnsInverted = !turnsInverted
+		}
+	}
+	return true
+}
+
+func (s *State) characterCheckSoftlock(p *Player) {
+	if !s.Options.DetrimentalCharacters {
+		return
+	}
+
+	if s.ClueTokens < s.Variant.GetAdjustedClueTokens(1) &&
+		p.CharacterMetadata == 0 && // The character's "special ability" is currently enabled
+		(p.Character == "Vindictive" || // 9
+			p.Character == "Insistent") { // 13
+
+		s.EndCondition = EndConditionCharacterSoftlock
+		s.EndPlayer = p.Index
+	}
+}
+
+func (s *State) characterSeesCard(p *Player, p2 *Player, cardOrder int) bool {
+	if !s.Options.DetrimentalCharacters {
+		return true
+	}
+
+	if p.Character == "Blind Spot" && p2.Index == s.GetNextPlayer(p.Index) { // 29
+		// Cannot see the cards of the next player
+		return false
+	}
+
+	if p.Character == "Oblivious" && p2.Index == s.GetPreviousPlayer(p.Index) { // 30
+		// Cannot see the cards of the previous player
+		return false
+	}
+
+	if p.Character == "Slow-Witted" && p2.GetCardSlot(cardOrder) == 1 { // 33
+		// Cannot see cards in slot 1
+		return false
+	}
+
+	return true
+}
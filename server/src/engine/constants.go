@@ -0,0 +1,63 @@
+package engine
+
+// iota starts at 0 and counts upwards
+// i.e. ActionTypePlay = 0, ActionTypeDiscard = 1, etc.
+
+// When in a game, players can perform certain types of "actions"
+const (
+	ActionTypePlay = iota
+	ActionTypeDiscard
+	ActionTypeColorClue
+	ActionTypeRankClue
+	ActionTypeEndGame // Players cannot send this (the server sends it to itself)
+)
+
+const (
+	ClueTypeColor = iota
+	ClueTypeRank
+)
+
+const (
+	EndConditionInProgress = iota
+	EndConditionNormal
+	EndConditionStrikeout
+	EndConditionTimeout
+	EndConditionTerminated
+	EndConditionSpeedrunFail
+	EndConditionIdleTimeout
+	EndConditionCharacterSoftlock
+	EndConditionAllOrNothingFail
+	EndConditionAllOrNothingSoftlock
+)
+
+// Stack directions are used for variants where suits have a non-standard playing direction
+// (e.g. 5 --> 4 --> 3 --> 2 --> 1)
+const (
+	StackDirectionUndecided = iota
+	StackDirectionUp
+	StackDirectionDown
+	StackDirectionFinished
+)
+
+const (
+	// The maximum amount of clues (and the amount of clues that players start the game with)
+	MaxClueNum = 8
+
+	// The maximum amount of strikes/misplays allowed before the game ends
+	MaxStrikeNum = 3
+
+	// The minimum and maximum amount of players that can be in a game
+	MinPlayers = 2
+	MaxPlayers = 8
+
+	// Currently, in all variants, you get 5 points per suit/stack,
+	// but this may not always be the case
+	PointsPerSuit = 5
+
+	// The "Up or Down" variants have "START" cards
+	// Rank 0 is the stack base
+	// Rank 1-5 are the normal cards
+	// Rank 6 is a card of unknown rank
+	// Rank 7 is a "START" card
+	StartCardRank = 7
+)
@@ -0,0 +1,73 @@
+package engine
+
+// InitDeck adds every card to the deck in a pre-determined order
+// If a custom deck is provided, then we can simply add every card to the deck as specified
+func (s *State) InitDeck(customDeck []*CardIdentity) {
+	if len(customDeck) != 0 {
+		for _, card := range customDeck {
+			s.Deck = append(s.Deck, NewCard(card.SuitIndex, card.Rank))
+			s.CardIdentities = append(s.CardIdentities, &CardIdentity{
+				SuitIndex: card.SuitIndex,
+				Rank:      card.Rank,
+			})
+		}
+		return
+	}
+
+	// Suits are represented as a slice of integers from 0 to the number of suits - 1
+	// (e.g. [0, 1, 2, 3, 4] for a "No Variant" game)
+	for suitIndex, suit := range s.Variant.Suits {
+		// Ranks are represented as a slice of integers
+		// (e.g. [1, 2, 3, 4, 5] for a "No Variant" game)
+		for _, rank := range s.Variant.Ranks {
+			amountToAdd := s.Variant.GetNumCopies(suit, rank)
+			for i := 0; i < amountToAdd; i++ {
+				// Add the card to the deck
+				s.Deck = append(s.Deck, NewCard(suitIndex, rank))
+				s.CardIdentities = append(s.CardIdentities, &CardIdentity{
+					SuitIndex: suitIndex,
+					Rank:      rank,
+				})
+			}
+		}
+	}
+}
+
+// ShuffleDeck shuffles the deck with the provided random number function
+// (e.g. "rand.Intn"), which must return a number in the range [0, n)
+// The engine does not have any random state of its own so that the caller can control how a
+// particular deck is derived from a seed
+func (s *State) ShuffleDeck(intn func(n int) int) {
+	// From: https://stackoverflow.com/questions/12264789/shuffle-array-in-go
+	for i := range s.Deck {
+		j := intn(i + 1)
+		s.Deck[i], s.Deck[j] = s.Deck[j], s.Deck[i]
+		s.CardIdentities[i], s.CardIdentities[j] = s.CardIdentities[j], s.CardIdentities[i]
+	}
+}
+
+// Deal marks the order of all of the cards in the deck and then deals the starting hands
+// It returns the events for the cards that were drawn
+func (s *State) Deal() []Event {
+	s.events = make([]Event, 0)
+
+	// Mark the order of all of the cards in the deck
+	for i, c := range s.Deck {
+		c.Order = i
+	}
+
+	handSize := s.GetHandSize()
+	for _, p := range s.Players {
+		for i := 0; i < handSize; i++ {
+			s.drawCard(p)
+		}
+	}
+
+	return s.flushEvents()
+}
+
+func (s *State) flushEvents() []Event {
+	events := s.events
+	s.events = make([]Event, 0)
+	return events
+}
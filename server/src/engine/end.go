@@ -0,0 +1,111 @@
+package engine
+
+// CheckEnd examines the game state and sets "EndCondition" to the appropriate value, if any
+func (s *State) CheckEnd() bool {
+	// Some ending conditions will already be set by the time we get here
+	if s.EndCondition == EndConditionTimeout ||
+		s.EndCondition == EndConditionTerminated ||
+		s.EndCondition == EndConditionIdleTimeout ||
+		s.EndCondition == EndConditionCharacterSoftlock {
+
+		return true
+	}
+
+	// Check for 3 strikes (or the custom strike limit)
+	if s.Strikes >= s.Options.GetStrikeLimit() {
+		s.EndCondition = EndConditionStrikeout
+		return true
+	}
+
+	// In a speedrun, check to see if a perfect score can still be achieved
+	if s.Options.Speedrun && s.MaxScore < s.Variant.MaxScore {
+		s.EndCondition = EndConditionSpeedrunFail
+		return true
+	}
+
+	// In an "All or Nothing" game, check to see if a maximum score can still be reached
+	if s.Options.AllOrNothing && s.MaxScore < s.Variant.MaxScore {
+		s.EndCondition = EndConditionAllOrNothingFail
+		return true
+	}
+
+	// In an "All or Nothing game",
+	// handle the case where a player would have to discard without any cards in their hand
+	if s.Options.AllOrNothing &&
+		len(s.Players[s.ActivePlayerIndex].Hand) == 0 &&
+		s.ClueTokens < s.Variant.GetAdjustedClueTokens(1) {
+
+		s.EndCondition = EndConditionAllOrNothingSoftlock
+		s.EndPlayer = s.Players[s.ActivePlayerIndex].Index
+		return true
+	}
+
+	// Check to see if the final go-around has completed
+	// (which is initiated after the last card is played from the deck)
+	if s.Turn == s.EndTurn {
+		s.EndCondition = EndConditionNormal
+		return true
+	}
+
+	// Check to see if the maximum score has been reached
+	if s.Score == s.MaxScore {
+		s.EndCondition = EndConditionNormal
+		return true
+	}
+
+	// Check to see if there are any cards remaining that can be played on the stacks
+	if s.Variant.HasReversedSuits() {
+		// Searching for the next card is much more complicated if we are playing an "Up or Down"
+		// or "Reversed" variant, so the logic for this is stored in a separate file
+		if !s.variantReversibleCheckAllDead() {
+			return false
+		}
+	} else {
+		for i, stackLen := range s.Stacks {
+			// Search through the deck
+			if stackLen == 5 {
+				continue
+			}
+			neededSuit := i
+			neededRank := stackLen + 1
+			for _, c := range s.Deck {
+				if c.SuitIndex == neededSuit &&
+					c.Rank == neededRank &&
+					!c.Discarded &&
+					!c.CannotBePlayed {
+
+					return false
+				}
+			}
+		}
+	}
+
+	// If we got this far, nothing can be played
+	s.EndCondition = EndConditionNormal
+	return true
+}
+
+// GetMaxScore calculates what the maximum score is,
+// accounting for stacks that cannot be completed due to discarded cards
+func (s *State) GetMaxScore() int {
+	// Getting the maximum score is much more complicated if we are playing a
+	// "Reversed" or "Up or Down" variant
+	if s.Variant.HasReversedSuits() {
+		return s.variantReversibleGetMaxScore()
+	}
+
+	maxScore := 0
+	for suit := range s.Stacks {
+		for rank := 1; rank <= 5; rank++ {
+			// Search through the deck to see if all the copies of this card are discarded already
+			total, discarded := s.GetSpecificCardNum(suit, rank)
+			if total > discarded {
+				maxScore++
+			} else {
+				break
+			}
+		}
+	}
+
+	return maxScore
+}
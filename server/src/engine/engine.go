@@ -0,0 +1,8 @@
+// Package engine contains the rules of the game
+// It has no knowledge of tables, sessions, or the database, so it can be embedded in bots,
+// analysis tools, and tests that need to reproduce the exact same behavior as the server
+//
+// A game is represented by a State object
+// Actions are performed with the "Apply()" method, which returns a new state along with a list of
+// events that describe what happened (the receiver is never modified)
+package engine
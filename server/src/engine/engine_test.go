@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// The variants are loaded from a JSON file by the server,
+// so the tests build the handful of suits and variants that they need by hand
+
+var (
+	testRed = &Suit{ // nolint: exhaustivestruct
+		Name:         "Red",
+		Abbreviation: "R",
+		ClueColors:   []string{"Red"},
+	}
+	testYellow = &Suit{ // nolint: exhaustivestruct
+		Name:         "Yellow",
+		Abbreviation: "Y",
+		ClueColors:   []string{"Yellow"},
+	}
+	testGreen = &Suit{ // nolint: exhaustivestruct
+		Name:         "Green",
+		Abbreviation: "G",
+		ClueColors:   []string{"Green"},
+	}
+	testBlue = &Suit{ // nolint: exhaustivestruct
+		Name:         "Blue",
+		Abbreviation: "B",
+		ClueColors:   []string{"Blue"},
+	}
+	testPurple = &Suit{ // nolint: exhaustivestruct
+		Name:         "Purple",
+		Abbreviation: "P",
+		ClueColors:   []string{"Purple"},
+	}
+	testRainbow = &Suit{ // nolint: exhaustivestruct
+		Name:          "Rainbow",
+		Abbreviation:  "M",
+		AllClueColors: true,
+	}
+	testWhite = &Suit{ // nolint: exhaustivestruct
+		Name:         "White",
+		Abbreviation: "W",
+		NoClueColors: true,
+	}
+	testBrown = &Suit{ // nolint: exhaustivestruct
+		Name:         "Brown",
+		Abbreviation: "N",
+		ClueColors:   []string{"Brown"},
+		NoClueRanks:  true,
+	}
+	testPink = &Suit{ // nolint: exhaustivestruct
+		Name:         "Pink",
+		Abbreviation: "I",
+		ClueColors:   []string{"Pink"},
+		AllClueRanks: true,
+	}
+	testPrism = &Suit{ // nolint: exhaustivestruct
+		Name:         "Prism",
+		Abbreviation: "I",
+		Prism:        true,
+	}
+)
+
+// newTestVariant returns a variant with the normal ranks and the normal amount of copies,
+// deriving the clue colors from the suits in the same way that the server does
+func newTestVariant(name string, suits ...*Suit) *Variant {
+	clueColors := make([]string, 0)
+	for _, suit := range suits {
+		if suit.AllClueColors {
+			continue
+		}
+		for _, color := range suit.ClueColors {
+			if !stringInSlice(color, clueColors) {
+				clueColors = append(clueColors, color)
+			}
+		}
+	}
+
+	return &Variant{ // nolint: exhaustivestruct
+		Name:         name,
+		Suits:        suits,
+		Ranks:        []int{1, 2, 3, 4, 5},
+		ClueColors:   clueColors,
+		ClueRanks:    []int{1, 2, 3, 4, 5},
+		SpecialRank:  -1,
+		MaxScore:     len(suits) * PointsPerSuit,
+		CardsPerRank: map[int]int{1: 3, 2: 2, 3: 2, 4: 2, 5: 1},
+	}
+}
+
+func newTestNoVariant() *Variant {
+	return newTestVariant("No Variant", testRed, testYellow, testGreen, testBlue, testPurple)
+}
+
+// newTestState deals a game with a stacked deck
+// The deck is written as a space-separated list of cards (e.g. "r1 y2 m5"),
+// where each card is the abbreviation of the suit followed by the rank
+// The cards are dealt in order, so e.g. in a 2-player game,
+// the first player gets the cards with the orders 0 through 4 (with 0 being their oldest card)
+func newTestState(
+	t *testing.T,
+	variant *Variant,
+	options *Options,
+	numPlayers int,
+	deck string,
+) *State {
+	customDeck := make([]*CardIdentity, 0)
+	for _, card := range strings.Fields(deck) {
+		suitIndex := -1
+		for i, suit := range variant.Suits {
+			if strings.EqualFold(suit.Abbreviation, card[:1]) {
+				suitIndex = i
+				break
+			}
+		}
+		if suitIndex == -1 {
+			t.Fatalf("%s: the card of \"%s\" has an invalid suit", variant.Name, card)
+		}
+
+		var rank int
+		if v, err := strconv.Atoi(card[1:]); err != nil {
+			t.Fatalf("%s: the card of \"%s\" has an invalid rank", variant.Name, card)
+		} else {
+			rank = v
+		}
+
+		customDeck = append(customDeck, &CardIdentity{
+			SuitIndex: suitIndex,
+			Rank:      rank,
+		})
+	}
+
+	s := NewState(variant, options)
+	s.InitDeck(customDeck)
+	for i := 0; i < numPlayers; i++ {
+		s.AddPlayer(testPlayerNames[i])
+	}
+	s.Deal()
+
+	return s
+}
+
+var testPlayerNames = []string{"Alice", "Bob", "Cathy", "Donald", "Emily", "Frank"}
+
+// applyTestActions performs every action in order on behalf of the active player
+func applyTestActions(t *testing.T, description string, s *State, actions []*Action) *State {
+	for _, a := range actions {
+		if v, _, err := s.Apply(a); err != nil {
+			t.Fatalf("%s: failed to apply the action %+v on turn %d: %v", description, *a, s.Turn,
+				err)
+		} else {
+			s = v
+		}
+	}
+
+	return s
+}
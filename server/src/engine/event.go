@@ -0,0 +1,79 @@
+// Events represent a change in the game state
+// Different events will have different fields
+// They are sent to the client as-is (as "gameAction" messages)
+
+package engine
+
+// Event is one of the "Event" structs below
+// We do not want this to be a pointer of interfaces because this simplifies event scrubbing
+type Event interface{}
+
+// Used to implement the "Slow-Witted" detrimental character
+type EventCardIdentity struct {
+	Type        string `json:"type"`
+	PlayerIndex int    `json:"playerIndex"` // Needed so that we can validate who holds the card
+	Order       int    `json:"order"`
+	SuitIndex   int    `json:"suitIndex"`
+	Rank        int    `json:"rank"`
+}
+
+type EventClue struct {
+	Type   string `json:"type"`
+	Clue   Clue   `json:"clue"`
+	Giver  int    `json:"giver"`
+	List   []int  `json:"list"` // The list of cards that the clue "touches"
+	Target int    `json:"target"`
+	// The client records the turn that each clue is given (for the clue log)
+	Turn int `json:"turn"`
+}
+
+type EventDiscard struct {
+	Type        string `json:"type"`
+	PlayerIndex int    `json:"playerIndex"`
+	Order       int    `json:"order"` // The ID of the card (based on its order in the deck)
+	SuitIndex   int    `json:"suitIndex"`
+	Rank        int    `json:"rank"`
+	Failed      bool   `json:"failed"`
+}
+
+type EventDraw struct {
+	Type        string `json:"type"`
+	PlayerIndex int    `json:"playerIndex"`
+	Order       int    `json:"order"` // The ID of the card, based on its ordering in the deck
+	SuitIndex   int    `json:"suitIndex"`
+	Rank        int    `json:"rank"`
+}
+
+type EventGameOver struct {
+	Type         string `json:"type"`
+	EndCondition int    `json:"endCondition"`
+	PlayerIndex  int    `json:"playerIndex"`
+}
+
+type EventPlay struct {
+	Type        string `json:"type"`
+	PlayerIndex int    `json:"playerIndex"`
+	Order       int    `json:"order"` // The ID of the card (based on its order in the deck)
+	SuitIndex   int    `json:"suitIndex"`
+	Rank        int    `json:"rank"`
+}
+
+type EventStrike struct {
+	Type  string `json:"type"`
+	Num   int    `json:"num"`   // Whether it was the first strike, the second strike, etc.
+	Turn  int    `json:"turn"`  // The turn that the strike happened
+	Order int    `json:"order"` // The order of the card that was played
+}
+
+type EventStatus struct {
+	Type     string `json:"type"`
+	Clues    int    `json:"clues"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"maxScore"`
+}
+
+type EventTurn struct {
+	Type               string `json:"type"`
+	Num                int    `json:"num"`
+	CurrentPlayerIndex int    `json:"currentPlayerIndex"`
+}
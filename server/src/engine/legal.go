@@ -0,0 +1,83 @@
+package engine
+
+// LegalAction is an action that the rules allow,
+// along with the cards that it would touch (if it is a clue)
+// (cards that the player's character is not allowed to see are never included)
+type LegalAction struct {
+	Action
+	Touched []int `json:"touched,omitempty"` // The orders of the cards that the clue touches
+}
+
+// LegalActions returns every play, discard, and clue that the rules allow the given player to
+// perform right now
+// The list is empty if it is not the player's turn or if the game is over
+// Every action is validated by actually applying it,
+// so the list will always agree with what "Apply()" accepts
+// (for every variant, every option, and every detrimental character)
+func (s *State) LegalActions(playerIndex int) []*LegalAction {
+	legalActions := make([]*LegalAction, 0)
+	if s.EndCondition > EndConditionInProgress ||
+		playerIndex != s.ActivePlayerIndex ||
+		playerIndex < 0 ||
+		playerIndex >= len(s.Players) {
+
+		return legalActions
+	}
+
+	for _, a := range s.candidateActions(playerIndex) {
+		_, events, err := s.Apply(a)
+		if err != nil {
+			continue
+		}
+
+		legalAction := &LegalAction{
+			Action:  *a,
+			Touched: nil,
+		}
+		for _, e := range events {
+			if clueEvent, ok := e.(EventClue); ok {
+				// Some characters are not allowed to see certain cards
+				p := s.Players[playerIndex]
+				p2 := s.Players[clueEvent.Target]
+				legalAction.Touched = make([]int, 0)
+				for _, order := range clueEvent.List {
+					if s.characterSeesCard(p, p2, order) {
+						legalAction.Touched = append(legalAction.Touched, order)
+					}
+				}
+				break
+			}
+		}
+		legalActions = append(legalActions, legalAction)
+	}
+
+	return legalActions
+}
+
+// candidateActions returns every action that could conceivably be legal for the given player
+// (without regard to the current state of the game)
+func (s *State) candidateActions(playerIndex int) []*Action {
+	candidates := make([]*Action, 0)
+	for _, c := range s.Players[playerIndex].Hand {
+		candidates = append(candidates, NewAction(ActionTypePlay, c.Order, 0))
+		candidates = append(candidates, NewAction(ActionTypeDiscard, c.Order, 0))
+	}
+	if s.Options.DeckPlays && s.DeckIndex < len(s.Deck) {
+		// Deck plays target the deck index instead of a card in their hand
+		// (this is only legal when there is 1 card left in the deck)
+		candidates = append(candidates, NewAction(ActionTypePlay, s.DeckIndex, 0))
+	}
+	for i := range s.Players {
+		if i == playerIndex {
+			continue
+		}
+		for j := range s.Variant.ClueColors {
+			candidates = append(candidates, NewAction(ActionTypeColorClue, i, j))
+		}
+		for _, rank := range s.Variant.ClueRanks {
+			candidates = append(candidates, NewAction(ActionTypeRankClue, i, rank))
+		}
+	}
+
+	return candidates
+}
@@ -0,0 +1,29 @@
+// Miscellaneous subroutines
+
+package engine
+
+func intInSlice(a int, slice []int) bool {
+	for _, b := range slice {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
+
+// From: https://mrekucci.blogspot.com/2015/07/dont-abuse-mathmax-mathmin.html
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func stringInSlice(a string, slice []string) bool {
+	for _, b := range slice {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package engine
+
+// Options are the subset of the game options that affect the rules of the game
+// (e.g. time controls are handled by the server and are not part of the rules)
+type Options struct {
+	Speedrun              bool `json:"speedrun"`
+	CardCycle             bool `json:"cardCycle"`
+	DeckPlays             bool `json:"deckPlays"`
+	EmptyClues            bool `json:"emptyClues"`
+	OneExtraCard          bool `json:"oneExtraCard"`
+	OneLessCard           bool `json:"oneLessCard"`
+	AllOrNothing          bool `json:"allOrNothing"`
+	DetrimentalCharacters bool `json:"detrimentalCharacters"`
+
+	// Custom rules
+	// (a value of 0 means that the normal rules are used)
+	StartingClueTokens int `json:"startingClueTokens"`
+	MaxClueTokens      int `json:"maxClueTokens"`
+	StrikeLimit        int `json:"strikeLimit"`
+	HandSize           int `json:"handSize"`
+}
+
+// GetMaxClueTokens returns the maximum amount of clue tokens that the team can have
+// (this is not adjusted for variants like "Clue Starved")
+func (o *Options) GetMaxClueTokens() int {
+	if o.MaxClueTokens > 0 {
+		return o.MaxClueTokens
+	}
+	return MaxClueNum
+}
+
+// GetStartingClueTokens returns the amount of clue tokens that the team starts the game with
+// (by default, the team starts with the maximum amount)
+func (o *Options) GetStartingClueTokens() int {
+	if o.StartingClueTokens > 0 {
+		return o.StartingClueTokens
+	}
+	return o.GetMaxClueTokens()
+}
+
+// GetStrikeLimit returns the amount of strikes that will end the game
+func (o *Options) GetStrikeLimit() int {
+	if o.StrikeLimit > 0 {
+		return o.StrikeLimit
+	}
+	return MaxStrikeNum
+}
+
+// HasCustomRules returns true if any of the custom rules are different from the normal rules
+// (explicitly choosing the normal value for a custom rule does not count)
+func (o *Options) HasCustomRules(numPlayers int) bool {
+	return (o.StartingClueTokens != 0 && o.StartingClueTokens != o.GetMaxClueTokens()) ||
+		(o.MaxClueTokens != 0 && o.MaxClueTokens != MaxClueNum) ||
+		(o.StrikeLimit != 0 && o.StrikeLimit != MaxStrikeNum) ||
+		(o.HandSize != 0 && o.HandSize != GetHandSizeForNormalGame(numPlayers))
+}
@@ -0,0 +1,97 @@
+package engine
+
+// Player is the object that represents the game state related aspects of a player
+type Player struct {
+	Name  string
+	Index int
+
+	Hand              []*Card
+	Character         string
+	CharacterMetadata int
+}
+
+// GetChopIndex gets the index of the oldest (right-most) unclued card
+// (used for the "Card Cycling" feature)
+func (p *Player) GetChopIndex() int {
+	for i := 0; i < len(p.Hand); i++ {
+		if !p.Hand[i].Touched {
+			return i
+		}
+	}
+
+	// Their hand is filled with clued cards,
+	// so the chop is considered to be their newest (left-most) card
+	return len(p.Hand) - 1
+}
+
+func (p *Player) InHand(order int) bool {
+	for _, c := range p.Hand {
+		if c.Order == order {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Player) GetCardIndex(order int) int {
+	for i, c := range p.Hand {
+		if c.Order == order {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (p *Player) GetCardSlot(order int) int {
+	// For example, slot 1 is the newest (left-most) card, which is at index 4 (in a 3-player game)
+	for i, c := range p.Hand {
+		if c.Order == order {
+			return len(p.Hand) - i
+		}
+	}
+
+	return -1
+}
+
+// FindCardsTouchedByClue returns a slice of card orders
+// (in this context, "orders" are the card positions in the deck, not in the hand)
+func (p *Player) FindCardsTouchedByClue(variant *Variant, clue Clue) []int {
+	list := make([]int, 0)
+	for _, c := range p.Hand {
+		if variant.IsCardTouched(clue, c) {
+			list = append(list, c.Order)
+		}
+	}
+
+	return list
+}
+
+func (p *Player) IsFirstCardTouchedByClue(variant *Variant, clue Clue) bool {
+	card := p.Hand[len(p.Hand)-1]
+	return variant.IsCardTouched(clue, card)
+}
+
+func (p *Player) IsLastCardTouchedByClue(variant *Variant, clue Clue) bool {
+	card := p.Hand[0]
+	return variant.IsCardTouched(clue, card)
+}
+
+func (p *Player) CycleHand() {
+	// Find the chop card
+	chopIndex := p.GetChopIndex()
+
+	// We don't need to reorder anything if the chop is slot 1 (the left-most card)
+	if chopIndex == len(p.Hand)-1 {
+		return
+	}
+
+	chopCard := p.Hand[chopIndex]
+
+	// Remove the chop card from their hand
+	p.Hand = append(p.Hand[:chopIndex], p.Hand[chopIndex+1:]...)
+
+	// Add it to the end (the left-most position)
+	p.Hand = append(p.Hand, chopCard)
+}
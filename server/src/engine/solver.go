@@ -0,0 +1,296 @@
+package engine
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Solution is the result of searching for the best score that the players could get if everyone
+// could see every card in the deck
+type Solution struct {
+	Score int `json:"score"`
+	// The upper bound of the score, based on the cards that have been discarded so far
+	MaxScore int `json:"maxScore"`
+	// True if it is certain that no better score is possible
+	// (this is false if the search ran out of nodes before it could check every line)
+	Proven bool      `json:"proven"`
+	Line   []*Action `json:"line"` // The actions that lead to the score
+	Nodes  int       `json:"nodes"`
+}
+
+type solver struct {
+	maxNodes    int
+	nodes       int
+	upperBound  int
+	best        *Solution
+	currentLine []*Action
+	exhausted   bool
+	// The states that have already been searched
+	// (only used when there are no detrimental characters,
+	// since otherwise two states with the same key might not be equivalent)
+	visited map[string]struct{}
+}
+
+// Solve searches for the best possible score from the current state with perfect information
+// The search is bounded by the provided number of nodes;
+// if the budget runs out, the best line that was found so far is returned
+func (s *State) Solve(maxNodes int) *Solution {
+	sv := &solver{
+		maxNodes:   maxNodes,
+		nodes:      0,
+		upperBound: s.MaxScore,
+		best: &Solution{
+			Score:    -1,
+			MaxScore: s.MaxScore,
+			Proven:   false,
+			Line:     make([]*Action, 0),
+			Nodes:    0,
+		},
+		currentLine: make([]*Action, 0),
+		exhausted:   false,
+		visited:     make(map[string]struct{}),
+	}
+	sv.search(s)
+
+	sv.best.Nodes = sv.nodes
+	// The only lines that are skipped without running out of nodes are the ones that cannot beat
+	// the best line (including every line once a line reaches the upper bound)
+	sv.best.Proven = !sv.exhausted
+	if sv.best.Score < 0 {
+		// We ran out of nodes before we found a single complete line
+		sv.best.Score = 0
+	}
+
+	return sv.best
+}
+
+// search returns true if the search should stop
+func (sv *solver) search(s *State) bool {
+	if s.EndCondition > EndConditionInProgress {
+		score := 0
+		if s.EndCondition == EndConditionNormal {
+			score = s.Score
+		}
+		if score > sv.best.Score {
+			sv.best.Score = score
+			sv.best.Line = make([]*Action, len(sv.currentLine))
+			copy(sv.best.Line, sv.currentLine)
+		}
+
+		// We can stop searching once we find a line that achieves the upper bound
+		return sv.best.Score == sv.upperBound
+	}
+
+	sv.nodes++
+	if sv.nodes > sv.maxNodes {
+		sv.exhausted = true
+		return true
+	}
+
+	// Prune lines that cannot possibly beat the best line found so far
+	if s.getScoreUpperBound() <= sv.best.Score {
+		return false
+	}
+
+	// Different orders of actions often lead to the same state
+	if !s.Options.DetrimentalCharacters {
+		key := s.getSolverKey()
+		if _, ok := sv.visited[key]; ok {
+			return false
+		}
+		sv.visited[key] = struct{}{}
+	}
+
+	for _, child := range s.getSolverChildren() {
+		sv.currentLine = append(sv.currentLine, child.action)
+		stop := sv.search(child.state)
+		sv.currentLine = sv.currentLine[:len(sv.currentLine)-1]
+		if stop {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getScoreUpperBound returns the best score that could possibly be achieved from this state
+// Every play after the first one must be preceded by a draw (until the deck runs out),
+// so there can not be more plays than there are cards left in the deck,
+// plus one final go-around
+// (we double the final go-around to account for characters that can take two actions in one turn)
+func (s *State) getScoreUpperBound() int {
+	remainingPlays := len(s.Deck) - s.DeckIndex + len(s.Players)*2
+	if s.EndTurn != -1 {
+		remainingPlays = (s.EndTurn - s.Turn + 1) * 2
+	}
+
+	upperBound := s.Score + remainingPlays
+	if s.MaxScore < upperBound {
+		upperBound = s.MaxScore
+	}
+
+	return upperBound
+}
+
+// getSolverKey returns a string that is identical for two states that have the same future
+// Cards with the same identity are interchangeable,
+// so the cards in each hand are identified by their suit and rank
+// (along with whether or not they can still be played)
+// The cards that are not in a hand or in the deck are either on the stacks or in the discard pile,
+// which is captured by the stacks and the maximum score
+// With card cycling, the order of the hand and the cards that are touched decide which card
+// is moved by the next clue, so they are part of the key as well
+func (s *State) getSolverKey() string {
+	key := strconv.Itoa(s.DeckIndex) + "|" +
+		strconv.Itoa(s.ActivePlayerIndex) + "|" +
+		strconv.Itoa(s.ClueTokens) + "|" +
+		strconv.Itoa(s.Strikes) + "|" +
+		strconv.Itoa(s.Score) + "|" +
+		strconv.Itoa(s.MaxScore) + "|" +
+		strconv.Itoa(s.LastClueTypeGiven) + "|"
+	if s.EndTurn != -1 {
+		key += strconv.Itoa(s.EndTurn-s.Turn) + "|"
+	}
+	for i := range s.Stacks {
+		key += strconv.Itoa(s.Stacks[i]) + "," + strconv.Itoa(s.PlayStackDirections[i]) + ";"
+	}
+	for _, p := range s.Players {
+		key += "|"
+		cards := make([]string, 0, len(p.Hand))
+		for _, c := range p.Hand {
+			card := strconv.Itoa(c.SuitIndex*10 + c.Rank)
+			if c.CannotBePlayed {
+				card += "x"
+			}
+			if s.Options.CardCycle && c.Touched {
+				card += "t"
+			}
+			cards = append(cards, card)
+		}
+		if !s.Options.CardCycle {
+			sort.Strings(cards)
+		}
+		for _, card := range cards {
+			key += card + ","
+		}
+	}
+
+	return key
+}
+
+type solverChild struct {
+	action *Action
+	state  *State
+}
+
+// getSolverChildren returns the states that are worth searching from this state,
+// in the order that they are most likely to lead to a good score
+// (successful plays, then discards, then clues, then misplays)
+func (s *State) getSolverChildren() []*solverChild {
+	plays := make([]*solverChild, 0)
+	discards := make([]*solverChild, 0)
+	clues := make([]*solverChild, 0)
+	misplays := make([]*solverChild, 0)
+
+	// With card cycling, the position of a card in the hand and whether or not it is touched
+	// decide which card is cycled later on
+	// With detrimental characters, the position of a card and the content of a clue can matter
+	// in other ways
+	// In both cases, we cannot assume that two actions lead to equivalent states
+	everyAction := s.Options.CardCycle || s.Options.DetrimentalCharacters
+
+	// Cards with the same identity are interchangeable
+	actions := make([]*Action, 0)
+	seen := make(map[string]struct{})
+	p := s.Players[s.ActivePlayerIndex]
+	for _, c := range p.Hand {
+		for _, actionType := range []int{ActionTypePlay, ActionTypeDiscard} {
+			key := strconv.Itoa(actionType) + "-" +
+				strconv.Itoa(c.SuitIndex) + "-" +
+				strconv.Itoa(c.Rank)
+			if everyAction {
+				key += "-" + strconv.Itoa(c.Order)
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			actions = append(actions, NewAction(actionType, c.Order, 0))
+		}
+	}
+
+	// Deck plays target the deck index instead of a card in their hand
+	// (this is only legal when there is 1 card left in the deck)
+	if s.Options.DeckPlays && s.DeckIndex < len(s.Deck) {
+		actions = append(actions, NewAction(ActionTypePlay, s.DeckIndex, 0))
+	}
+
+	for _, a := range actions {
+		s2, events, err := s.Apply(a)
+		if err != nil {
+			continue
+		}
+		child := &solverChild{
+			action: a,
+			state:  s2,
+		}
+
+		if a.Type == ActionTypeDiscard {
+			discards = append(discards, child)
+			continue
+		}
+
+		misplayed := false
+		for _, e := range events {
+			if _, ok := e.(EventStrike); ok {
+				misplayed = true
+				break
+			}
+		}
+		if misplayed {
+			misplays = append(misplays, child)
+		} else {
+			plays = append(plays, child)
+		}
+	}
+
+	// Since every player can see every card, the content of a clue usually does not matter;
+	// a clue is only a way to pass the turn, so only one clue of each type is needed
+	// Otherwise, we need one clue for every different state that can be reached
+	// (with detrimental characters, the states cannot be compared, so every clue is needed)
+	clueKeys := make(map[string]struct{})
+	for _, actionType := range []int{ActionTypeColorClue, ActionTypeRankClue} {
+		for _, a := range s.candidateActions(s.ActivePlayerIndex) {
+			if a.Type != actionType {
+				continue
+			}
+			s2, _, err := s.Apply(a)
+			if err != nil {
+				continue
+			}
+			if s.Options.CardCycle && !s.Options.DetrimentalCharacters {
+				key := s2.getSolverKey()
+				if _, ok := clueKeys[key]; ok {
+					continue
+				}
+				clueKeys[key] = struct{}{}
+			}
+
+			clues = append(clues, &solverChild{
+				action: a,
+				state:  s2,
+			})
+			if !everyAction {
+				break
+			}
+		}
+	}
+
+	children := make([]*solverChild, 0, len(plays)+len(discards)+len(clues)+len(misplays))
+	children = append(children, plays...)
+	children = append(children, discards...)
+	children = append(children, clues...)
+	children = append(children, misplays...)
+
+	return children
+}
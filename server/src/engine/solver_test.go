@@ -0,0 +1,208 @@
+package engine
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	redYellow := newTestVariant("Red & Yellow", testRed, testYellow)
+	red := newTestVariant("Red", testRed)
+
+	testCases := []struct {
+		description string
+		variant     *Variant
+		deck        string
+		maxNodes    int
+		score       int
+		proven      bool
+	}{
+		{
+			// Alice and Bob can each play one suit in order
+			description: "a deck that can be won",
+			variant:     redYellow,
+			deck: "r1 r2 r3 r4 r5 " +
+				"y1 y2 y3 y4 y5 " +
+				"r1 r1 r2 r3 r4 y1 y1 y2 y3 y4",
+			maxNodes: 1000,
+			score:    10,
+			proven:   true,
+		},
+		{
+			// Every card is dealt, so there are only three turns left
+			// (and Bob does not have a card that can be played until the red 2 is played)
+			description: "a deck that cannot be won",
+			variant:     red,
+			deck: "r1 r1 r1 r2 r2 " +
+				"r3 r3 r4 r4 r5",
+			maxNodes: 1000,
+			score:    2,
+			proven:   true,
+		},
+		{
+			description: "running out of nodes",
+			variant:     redYellow,
+			deck: "r1 r2 r3 r4 r5 " +
+				"y1 y2 y3 y4 y5 " +
+				"r1 r1 r2 r3 r4 y1 y1 y2 y3 y4",
+			maxNodes: 1,
+			score:    0,
+			proven:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		s := newTestState(t, tc.variant, &Options{}, 2, tc.deck) // nolint: exhaustivestruct
+		solution := s.Solve(tc.maxNodes)
+
+		if solution.Score != tc.score {
+			t.Errorf("%s: the score is %d instead of %d", tc.description, solution.Score,
+				tc.score)
+		}
+		if solution.Proven != tc.proven {
+			t.Errorf("%s: the score has a proven value of %t", tc.description, solution.Proven)
+		}
+		assertSolutionLine(t, tc.description, s, solution)
+	}
+}
+
+// The solver skips actions and states that it assumes are equivalent to ones that it has already
+// searched, so it must agree with a search that tries every legal action
+func TestSolveSmallGames(t *testing.T) {
+	variant := newTestVariant("Red", testRed)
+
+	testCases := []struct {
+		description string
+		options     *Options
+		numPlayers  int
+	}{
+		{
+			description: "no options",
+			options:     &Options{}, // nolint: exhaustivestruct
+			numPlayers:  2,
+		},
+		{
+			description: "three players",
+			options:     &Options{}, // nolint: exhaustivestruct
+			numPlayers:  3,
+		},
+		{
+			description: "card cycling",
+			options:     &Options{CardCycle: true}, // nolint: exhaustivestruct
+			numPlayers:  2,
+		},
+		{
+			description: "deck plays",
+			options:     &Options{DeckPlays: true}, // nolint: exhaustivestruct
+			numPlayers:  2,
+		},
+	}
+
+	for _, tc := range testCases {
+		// Keep the games small enough to search every legal action
+		tc.options.HandSize = 2
+		tc.options.MaxClueTokens = 1
+		tc.options.StrikeLimit = 1
+
+		for i := 0; i < 10; i++ {
+			description := tc.description + " (deck " + strconv.Itoa(i) + ")"
+
+			// Take 8 random cards from a full deck
+			s := NewState(variant, tc.options)
+			s.InitDeck(nil)
+			s.ShuffleDeck(rand.New(rand.NewSource(int64(i))).Intn) // nolint: gosec
+			s.Deck = s.Deck[:8]
+			s.CardIdentities = s.CardIdentities[:8]
+			for j := 0; j < tc.numPlayers; j++ {
+				s.AddPlayer(testPlayerNames[j])
+			}
+			s.Deal()
+
+			score := searchEveryTestAction(t, s, make(map[string]int))
+			solution := s.Solve(1000000)
+			if !solution.Proven {
+				t.Errorf("%s: the solver ran out of nodes", description)
+				continue
+			}
+			if solution.Score != score {
+				t.Errorf("%s: the solver found a score of %d instead of %d", description,
+					solution.Score, score)
+			}
+			assertSolutionLine(t, description, s, solution)
+		}
+	}
+}
+
+// assertSolutionLine checks that the line of a solution is legal and gets the score of the solution
+func assertSolutionLine(t *testing.T, description string, s *State, solution *Solution) {
+	if solution.Score == 0 {
+		return
+	}
+
+	s = applyTestActions(t, description, s, solution.Line)
+	if s.EndCondition != EndConditionNormal {
+		t.Errorf("%s: the line of the solution ends with an end condition of %d",
+			description, s.EndCondition)
+	} else if s.Score != solution.Score {
+		t.Errorf("%s: the line of the solution gets a score of %d instead of %d", description,
+			s.Score, solution.Score)
+	}
+}
+
+// searchEveryTestAction returns the best score that can be achieved from a state by trying every
+// legal action (without any shortcuts, other than remembering the states that it has searched)
+func searchEveryTestAction(t *testing.T, s *State, scores map[string]int) int {
+	if s.EndCondition > EndConditionInProgress {
+		if s.EndCondition == EndConditionNormal {
+			return s.Score
+		}
+		return 0
+	}
+
+	// The key contains everything about the state that the rules use
+	// (the turn only matters in relation to the end turn)
+	key := strconv.Itoa(s.DeckIndex) + "|" +
+		strconv.Itoa(s.ActivePlayerIndex) + "|" +
+		strconv.Itoa(s.ClueTokens) + "|" +
+		strconv.Itoa(s.Strikes) + "|" +
+		strconv.Itoa(s.Score) + "|" +
+		strconv.Itoa(s.MaxScore) + "|" +
+		strconv.Itoa(s.LastClueTypeGiven) + "|"
+	if s.EndTurn != -1 {
+		key += strconv.Itoa(s.EndTurn-s.Turn) + "|"
+	}
+	for _, c := range s.Deck {
+		key += strconv.FormatBool(c.Touched) + "," +
+			strconv.FormatBool(c.Discarded) + "," +
+			strconv.FormatBool(c.Played) + "," +
+			strconv.FormatBool(c.CannotBePlayed) + ";"
+	}
+	for _, p := range s.Players {
+		key += "|"
+		for _, c := range p.Hand {
+			key += strconv.Itoa(c.Order) + ","
+		}
+	}
+	if score, ok := scores[key]; ok {
+		return score
+	}
+
+	bestScore := 0
+	for _, legalAction := range s.LegalActions(s.ActivePlayerIndex) {
+		var s2 *State
+		if v, _, err := s.Apply(&legalAction.Action); err != nil {
+			t.Fatalf("failed to apply a legal action on turn %d: %v", s.Turn, err)
+		} else {
+			s2 = v
+		}
+
+		score := searchEveryTestAction(t, s2, scores)
+		if score > bestScore {
+			bestScore = score
+		}
+	}
+	scores[key] = bestScore
+
+	return bestScore
+}
@@ -0,0 +1,251 @@
+package engine
+
+// State represents all of the particular state associated with the rules of a game
+// A tag of `json:"-"` denotes that the JSON serializer should skip the field when serializing
+type State struct {
+	// This is a reference to the variant that is being played
+	// (it must be restored after the state is unserialized)
+	Variant *Variant `json:"-"`
+	Options *Options
+
+	Players             []*Player
+	Deck                []*Card
+	CardIdentities      []*CardIdentity // A bare-bones version of the deck
+	DeckIndex           int
+	Stacks              []int
+	PlayStackDirections []int // The values for this are listed in "constants.go"
+	Turn                int   // Starts at 0; the client will represent turn 0 as turn 1 to the user
+	TurnsInverted       bool
+	ActivePlayerIndex   int // Every game always starts with the 0th player going first
+	ClueTokens          int
+	Score               int
+	MaxScore            int
+	Strikes             int
+	LastClueTypeGiven   int // Used in "Alternating Clues" variants
+	EndCondition        int // The values for this are listed in "constants.go"
+	// The index of the player who ended the game, if any
+	// (needed for writing a "game over" terminate action to the database)
+	EndPlayer int
+	// Initialized to -1 and set when the final card is drawn
+	// (to determine when the game should end)
+	EndTurn int
+
+	// The events that have occurred during the action that is currently being applied
+	events []Event
+}
+
+// NewState creates the state for a game that has not started yet
+// Players must be added with "AddPlayer()" and the deck must be created with "InitDeck()" before
+// the cards are dealt with "Deal()"
+func NewState(variant *Variant, options *Options) *State {
+	s := &State{
+		Variant: variant,
+		Options: options,
+
+		Players:             make([]*Player, 0),
+		Deck:                make([]*Card, 0),
+		CardIdentities:      make([]*CardIdentity, 0),
+		DeckIndex:           0,
+		Stacks:              make([]int, len(variant.Suits)),
+		PlayStackDirections: make([]int, len(variant.Suits)),
+		Turn:                0,
+		TurnsInverted:       false,
+		ActivePlayerIndex:   0,
+		ClueTokens:          variant.GetAdjustedClueTokens(options.GetStartingClueTokens()),
+		Score:               0,
+		MaxScore:            len(variant.Suits) * PointsPerSuit,
+		Strikes:             0,
+		LastClueTypeGiven:   -1,
+		EndCondition:        EndConditionInProgress,
+		EndPlayer:           -1,
+		EndTurn:             -1,
+
+		events: make([]Event, 0),
+	}
+
+	// Reverse the stack direction of reversed suits, except on the "Up or Down" variant
+	// that uses the "Undecided" direction.
+	if variant.HasReversedSuits() && !variant.UpOrDown {
+		for i, suit := range variant.Suits {
+			if suit.Reversed {
+				s.PlayStackDirections[i] = StackDirectionDown
+			} else {
+				s.PlayStackDirections[i] = StackDirectionUp
+			}
+		}
+	}
+
+	return s
+}
+
+// AddPlayer adds a new seat to a game that has not started yet
+func (s *State) AddPlayer(name string) *Player {
+	p := &Player{
+		Name:  name,
+		Index: len(s.Players),
+
+		Hand:              make([]*Card, 0),
+		Character:         "",
+		CharacterMetadata: -1,
+	}
+	s.Players = append(s.Players, p)
+
+	return p
+}
+
+// Clone returns a deep copy of the state
+// (the variant is immutable, so it is shared between the two copies)
+func (s *State) Clone() *State {
+	s2 := *s // In Go, this dereference assignment is a shallow copy
+
+	options := *s.Options
+	s2.Options = &options
+
+	s2.Deck = make([]*Card, len(s.Deck))
+	for i, c := range s.Deck {
+		c2 := *c
+		s2.Deck[i] = &c2
+	}
+
+	s2.CardIdentities = make([]*CardIdentity, len(s.CardIdentities))
+	copy(s2.CardIdentities, s.CardIdentities) // Card identities never change
+
+	s2.Players = make([]*Player, len(s.Players))
+	for i, p := range s.Players {
+		p2 := *p
+		p2.Hand = make([]*Card, len(p.Hand))
+		for j, c := range p.Hand {
+			// Cards in the hand must point to the same objects as the cards in the deck
+			p2.Hand[j] = s2.Deck[c.Order]
+		}
+		s2.Players[i] = &p2
+	}
+
+	s2.Stacks = make([]int, len(s.Stacks))
+	copy(s2.Stacks, s.Stacks)
+	s2.PlayStackDirections = make([]int, len(s.PlayStackDirections))
+	copy(s2.PlayStackDirections, s.PlayStackDirections)
+
+	s2.events = make([]Event, 0)
+
+	return &s2
+}
+
+// RelinkHands ensures that the cards in each hand point to the same objects as the cards in the
+// deck
+// This must be called after a state is unserialized, since the JSON encoder has no notion of
+// shared pointers
+func (s *State) RelinkHands() {
+	for _, p := range s.Players {
+		for i, c := range p.Hand {
+			p.Hand[i] = s.Deck[c.Order]
+		}
+	}
+}
+
+/*
+	Miscellaneous functions
+*/
+
+// GetClueTokenLimit returns the maximum amount of clue tokens that the team can have,
+// adjusted for the variant
+func (s *State) GetClueTokenLimit() int {
+	return s.Variant.GetAdjustedClueTokens(s.Options.GetMaxClueTokens())
+}
+
+func (s *State) AtMaxClueTokens() bool {
+	return s.ClueTokens >= s.GetClueTokenLimit()
+}
+
+func (s *State) GetHandSize() int {
+	return GetHandSize(len(s.Players), s.Options)
+}
+
+func (s *State) GetHandSizeForNormalGame() int {
+	return GetHandSizeForNormalGame(len(s.Players))
+}
+
+// GetHandSize returns the amount of cards that each player starts the game with,
+// accounting for the options that modify the hand size
+func GetHandSize(numPlayers int, options *Options) int {
+	handSize := GetHandSizeForNormalGame(numPlayers)
+	if options.HandSize > 0 {
+		handSize = options.HandSize
+	}
+	if options.OneExtraCard {
+		handSize++
+	}
+	if options.OneLessCard {
+		handSize--
+	}
+	return handSize
+}
+
+func GetHandSizeForNormalGame(numPlayers int) int {
+	if numPlayers == 2 || numPlayers == 3 {
+		return 5
+	} else if numPlayers == 4 || numPlayers == 5 {
+		return 4
+	} else if numPlayers >= 6 && numPlayers <= MaxPlayers {
+		// 7-player and 8-player games also use 3 cards
+		// (the "One Less Card" option can be used for 2 cards)
+		return 3
+	}
+
+	// The server does not allow games with any other amount of players
+	return 4
+}
+
+// GetStartingPace returns the amount of cards that can be discarded over the course of the game
+// while still being able to get the maximum score
+// (e.g. with 8 players, a hand size of 3 and a 3-suit variant, 24 out of the 30 cards are dealt
+// and a maximum score is impossible before anyone has taken a turn)
+// If the value is negative, a maximum score is impossible
+func GetStartingPace(variant *Variant, numPlayers int, options *Options) int {
+	cardsLeftInDeck := variant.GetDeckSize() - numPlayers*GetHandSize(numPlayers, options)
+	return cardsLeftInDeck + numPlayers - variant.MaxScore
+}
+
+// GetSpecificCardNum returns the total cards in the deck of the specified suit and rank
+// as well as how many of those that have been already discarded
+func (s *State) GetSpecificCardNum(suitIndex int, rank int) (int, int) {
+	total := 0
+	discarded := 0
+	for _, c := range s.Deck {
+		if c.SuitIndex == suitIndex && c.Rank == rank {
+			total++
+			if c.Discarded {
+				discarded++
+			}
+		}
+	}
+
+	return total, discarded
+}
+
+func (s *State) GetNotesSize() int {
+	// There are notes for every card in the deck + the stack bases for each suit
+	numCards := len(s.Deck)
+	numSuits := len(s.Variant.Suits)
+	return numCards + numSuits
+}
+
+func (s *State) GetNextPlayer(playerIndex int) int {
+	i := playerIndex + 1
+	if i == len(s.Players) {
+		return 0
+	}
+	return i
+}
+
+func (s *State) GetPreviousPlayer(playerIndex int) int {
+	i := playerIndex - 1
+	if i == -1 {
+		return len(s.Players) - 1
+	}
+	return i
+}
+
+func (s *State) emit(e Event) {
+	s.events = append(s.events, e)
+}
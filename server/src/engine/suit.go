@@ -1,4 +1,4 @@
-package main
+package engine
 
 type Suit struct {
 	Name         string
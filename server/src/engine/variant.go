@@ -0,0 +1,183 @@
+package engine
+
+type Variant struct {
+	Name string
+	// Each variant must have a unique numerical ID for seed generation purposes
+	// (and for the database)
+	ID                     int
+	Suits                  []*Suit
+	Ranks                  []int
+	ClueColors             []string
+	ClueRanks              []int
+	ColorCluesTouchNothing bool
+	RankCluesTouchNothing  bool
+	SpecialRank            int // For e.g. Rainbow-Ones
+	SpecialAllClueColors   bool
+	SpecialAllClueRanks    bool
+	SpecialNoClueColors    bool
+	SpecialNoClueRanks     bool
+	SpecialDeceptive       bool
+	MaxScore               int
+
+	// Clue rules
+	AlternatingClues     bool // Players cannot give two clues of the same type in a row
+	ColorCluesTouchRanks bool // Color clues also touch cards based on their rank (e.g. Synesthesia)
+
+	// Clue token economy
+	DiscardsGiveHalfClue bool // e.g. Clue Starved
+	NoClueForPlaying5    bool // e.g. Throw It in a Hole
+
+	// Stack direction
+	// Each stack can be built either up from 1 or down from 5 (with a START card to begin either)
+	UpOrDown bool
+
+	// Hidden information
+	// (most of these are enforced by the client;
+	// the server only needs to avoid sending the information in the first place)
+	HidePlays      bool // Players do not see what was played (e.g. Throw It in a Hole)
+	HideClueTypes  bool // Players do not see the type of a clue (e.g. Duck)
+	HideClueValues bool // Players do not see the color or rank of a clue (e.g. Cow & Pig)
+
+	// Deck composition
+	// The keys are the ranks and the values are the number of copies of each card in a normal suit
+	// (reversed suits use the number of copies of the opposite rank;
+	// one-of-each suits only have one copy of every rank)
+	CardsPerRank map[int]int
+}
+
+func (v *Variant) HasReversedSuits() bool {
+	if v.UpOrDown {
+		return true
+	}
+
+	for _, s := range v.Suits {
+		if s.Reversed {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNumCopies returns the number of copies of a card that are in the deck
+func (v *Variant) GetNumCopies(suit *Suit, rank int) int {
+	if suit.OneOfEach {
+		return 1
+	}
+
+	if suit.Reversed && rank >= 1 && rank <= 5 {
+		// A reversed suit has as many 5's as a normal suit has 1's, and so forth
+		return v.CardsPerRank[6-rank]
+	}
+
+	return v.CardsPerRank[rank]
+}
+
+func (v *Variant) GetDeckSize() int {
+	deckSize := 0
+	for _, suit := range v.Suits {
+		for _, rank := range v.Ranks {
+			deckSize += v.GetNumCopies(suit, rank)
+		}
+	}
+	return deckSize
+}
+
+func (v *Variant) GetAdjustedClueTokens(clueTokens int) int {
+	// In "Clue Starved" variants, each discard only grants 0.5 clue tokens
+	// This is represented on the server by discards granting 1 clue token and clues costing 2 tokens
+	// (to avoid having to use floating point numbers)
+	if v.DiscardsGiveHalfClue {
+		return clueTokens * 2
+	}
+
+	return clueTokens
+}
+
+func (v *Variant) ShouldGiveClueTokenForPlaying5() bool {
+	return !v.NoClueForPlaying5
+}
+
+// IsCardTouched returns true if a clue will touch a particular suit
+// For example, a yellow clue will not touch a green card in a normal game,
+// but it will the "Dual-Color" variant
+// This mirrors the function "touchesCard()" in "clues.ts"
+func (v *Variant) IsCardTouched(clue Clue, card *Card) bool {
+	suit := v.Suits[card.SuitIndex]
+
+	if clue.Type == ClueTypeColor {
+		clueColorName := v.ClueColors[clue.Value]
+
+		if v.ColorCluesTouchRanks && !suit.NoClueRanks {
+			// In addition to any other matching, match color based on rank.
+			prismColorIndex := (card.Rank - 1) % len(v.ClueColors)
+			prismColorName := v.ClueColors[prismColorIndex]
+			if clueColorName == prismColorName {
+				return true
+			}
+		}
+
+		if v.ColorCluesTouchNothing {
+			return false
+		}
+
+		if suit.AllClueColors {
+			return true
+		}
+		if suit.NoClueColors {
+			return false
+		}
+
+		if v.SpecialRank == card.Rank {
+			if v.SpecialAllClueColors {
+				return true
+			}
+			if v.SpecialNoClueColors {
+				return false
+			}
+		}
+
+		if suit.Prism {
+			// The color that touches a prism card is contingent upon the card's rank
+			prismColorIndex := (card.Rank - 1) % len(v.ClueColors)
+			if card.Rank == StartCardRank {
+				// "START" cards count as rank 0, so they are touched by the final color
+				prismColorIndex = len(v.ClueColors) - 1
+			}
+			prismColorName := v.ClueColors[prismColorIndex]
+			return clueColorName == prismColorName
+		}
+
+		return stringInSlice(clueColorName, suit.ClueColors)
+	}
+
+	if clue.Type == ClueTypeRank {
+		if v.RankCluesTouchNothing {
+			return false
+		}
+
+		if v.Suits[card.SuitIndex].AllClueRanks {
+			return true
+		}
+		if v.Suits[card.SuitIndex].NoClueRanks {
+			return false
+		}
+
+		if v.SpecialRank == card.Rank {
+			if v.SpecialAllClueRanks {
+				return true
+			}
+			if v.SpecialNoClueRanks {
+				return false
+			}
+			if v.SpecialDeceptive {
+				// The rank that touches a deceptive card is contingent upon the card's suit
+				deceptiveRank := v.ClueRanks[card.SuitIndex%len(v.ClueRanks)]
+				return clue.Value == deceptiveRank
+			}
+		}
+
+		return clue.Value == card.Rank
+	}
+
+	return false
+}
@@ -0,0 +1,310 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The variants change which cards a clue touches,
+// and some of them change which clues are allowed in the first place
+func TestApplyClueTouches(t *testing.T) {
+	noVariant := newTestNoVariant()
+	rainbow := newTestVariant("Rainbow (5 Suits)", testRed, testYellow, testGreen, testBlue,
+		testRainbow)
+	white := newTestVariant("White (5 Suits)", testRed, testYellow, testGreen, testBlue,
+		testWhite)
+	brown := newTestVariant("Brown (5 Suits)", testRed, testYellow, testGreen, testBlue,
+		testBrown)
+	pink := newTestVariant("Pink (5 Suits)", testRed, testYellow, testGreen, testBlue, testPink)
+	prism := newTestVariant("Prism (5 Suits)", testRed, testYellow, testGreen, testBlue,
+		testPrism)
+
+	rainbowOnes := newTestNoVariant()
+	rainbowOnes.Name = "Rainbow-Ones (5 Suits)"
+	rainbowOnes.SpecialRank = 1
+	rainbowOnes.SpecialAllClueColors = true
+
+	pinkOnes := newTestNoVariant()
+	pinkOnes.Name = "Pink-Ones (5 Suits)"
+	pinkOnes.SpecialRank = 1
+	pinkOnes.SpecialAllClueRanks = true
+
+	whiteOnes := newTestNoVariant()
+	whiteOnes.Name = "White-Ones (5 Suits)"
+	whiteOnes.SpecialRank = 1
+	whiteOnes.SpecialNoClueColors = true
+
+	deceptiveOnes := newTestNoVariant()
+	deceptiveOnes.Name = "Deceptive-Ones (5 Suits)"
+	deceptiveOnes.SpecialRank = 1
+	deceptiveOnes.SpecialDeceptive = true
+
+	synesthesia := newTestNoVariant()
+	synesthesia.Name = "Synesthesia (5 Suits)"
+	synesthesia.ColorCluesTouchRanks = true
+
+	colorBlind := newTestNoVariant()
+	colorBlind.Name = "Color Blind (5 Suits)"
+	colorBlind.ColorCluesTouchNothing = true
+
+	numberBlind := newTestNoVariant()
+	numberBlind.Name = "Number Blind (5 Suits)"
+	numberBlind.RankCluesTouchNothing = true
+
+	alternatingClues := newTestNoVariant()
+	alternatingClues.Name = "Alternating Clues (5 Suits)"
+	alternatingClues.AlternatingClues = true
+
+	clueStarved := newTestNoVariant()
+	clueStarved.Name = "Clue Starved (5 Suits)"
+	clueStarved.DiscardsGiveHalfClue = true
+
+	testCases := []struct {
+		description string
+		variant     *Variant
+		options     *Options
+		// The hand of Bob (the cards 5 through 9)
+		hand string
+		// The actions to perform before the clue (if any)
+		actions []*Action
+		clue    *Action
+		// If true, the clue must be illegal
+		err     bool
+		touched []int
+	}{
+		{
+			description: "a color clue",
+			variant:     noVariant,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{5, 6, 9},
+		},
+		{
+			description: "a rank clue",
+			variant:     noVariant,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{5, 7},
+		},
+		{
+			description: "an empty clue",
+			variant:     noVariant,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeRankClue, 1, 4),
+			err:         true,
+		},
+		{
+			description: "an empty clue with the \"Empty Clues\" option",
+			variant:     noVariant,
+			options:     &Options{EmptyClues: true}, // nolint: exhaustivestruct
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeRankClue, 1, 4),
+			touched:     []int{},
+		},
+		{
+			description: "a red clue in a rainbow variant",
+			variant:     rainbow,
+			hand:        "r1 m2 y1 b3 m5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{5, 6, 9},
+		},
+		{
+			description: "a blue clue in a rainbow variant",
+			variant:     rainbow,
+			hand:        "r1 m2 y1 b3 m5",
+			clue:        NewAction(ActionTypeColorClue, 1, 3),
+			touched:     []int{6, 8, 9},
+		},
+		{
+			description: "a color clue in a white variant",
+			variant:     white,
+			hand:        "w1 r1 w2 y3 w5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{6},
+		},
+		{
+			description: "a rank clue in a white variant",
+			variant:     white,
+			hand:        "w1 r1 w2 y3 w5",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{5, 6},
+		},
+		{
+			description: "a color clue to a hand of white cards",
+			variant:     white,
+			hand:        "w1 w2 w3 w4 w5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			err:         true,
+		},
+		{
+			description: "a brown clue in a brown variant",
+			variant:     brown,
+			hand:        "n1 r1 n2 y3 n1",
+			clue:        NewAction(ActionTypeColorClue, 1, 4),
+			touched:     []int{5, 7, 9},
+		},
+		{
+			description: "a rank clue in a brown variant",
+			variant:     brown,
+			hand:        "n1 r1 n2 y3 n1",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{6},
+		},
+		{
+			description: "a rank clue in a pink variant",
+			variant:     pink,
+			hand:        "i1 r1 i2 y3 i5",
+			clue:        NewAction(ActionTypeRankClue, 1, 3),
+			touched:     []int{5, 7, 8, 9},
+		},
+		{
+			// Prism cards are touched by the color that matches their rank
+			// (red for 1's and 5's, yellow for 2's, and so on)
+			description: "a red clue in a prism variant",
+			variant:     prism,
+			hand:        "i1 i2 i5 y1 r3",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{5, 7, 9},
+		},
+		{
+			description: "a yellow clue in a prism variant",
+			variant:     prism,
+			hand:        "i1 i2 i5 y1 r3",
+			clue:        NewAction(ActionTypeColorClue, 1, 1),
+			touched:     []int{6, 8},
+		},
+		{
+			description: "a color clue in a rainbow-ones variant",
+			variant:     rainbowOnes,
+			hand:        "r1 y1 g2 b3 p5",
+			clue:        NewAction(ActionTypeColorClue, 1, 2),
+			touched:     []int{5, 6, 7},
+		},
+		{
+			description: "a rank clue in a pink-ones variant",
+			variant:     pinkOnes,
+			hand:        "r1 y1 g2 b3 p5",
+			clue:        NewAction(ActionTypeRankClue, 1, 5),
+			touched:     []int{5, 6, 9},
+		},
+		{
+			description: "a color clue in a white-ones variant",
+			variant:     whiteOnes,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{6, 9},
+		},
+		{
+			// A deceptive 1 is touched by the rank that matches its suit
+			// (1 for red, 2 for yellow, and so on)
+			description: "a 1 clue in a deceptive-ones variant",
+			variant:     deceptiveOnes,
+			hand:        "r1 y1 g1 y2 p5",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{5},
+		},
+		{
+			description: "a 2 clue in a deceptive-ones variant",
+			variant:     deceptiveOnes,
+			hand:        "r1 y1 g1 y2 p5",
+			clue:        NewAction(ActionTypeRankClue, 1, 2),
+			touched:     []int{6, 8},
+		},
+		{
+			// Color clues also touch the cards with the rank that matches the color
+			// (red for 1's, yellow for 2's, and so on)
+			description: "a red clue in a synesthesia variant",
+			variant:     synesthesia,
+			hand:        "r1 y1 g2 b3 p5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{5, 6},
+		},
+		{
+			description: "a yellow clue in a synesthesia variant",
+			variant:     synesthesia,
+			hand:        "r1 y1 g2 b3 p5",
+			clue:        NewAction(ActionTypeColorClue, 1, 1),
+			touched:     []int{6, 7},
+		},
+		{
+			description: "a color clue in a color blind variant",
+			variant:     colorBlind,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeColorClue, 1, 0),
+			touched:     []int{},
+		},
+		{
+			description: "a rank clue in a color blind variant",
+			variant:     colorBlind,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{5, 7},
+		},
+		{
+			description: "a rank clue in a number blind variant",
+			variant:     numberBlind,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{},
+		},
+		{
+			// Clues cost 2 clue tokens, since each discard only gives half of a clue
+			description: "a rank clue in a clue starved variant",
+			variant:     clueStarved,
+			hand:        "r1 r2 y1 b3 r5",
+			clue:        NewAction(ActionTypeRankClue, 1, 1),
+			touched:     []int{5, 7},
+		},
+		{
+			description: "two rank clues in a row in an alternating clues variant",
+			variant:     alternatingClues,
+			hand:        "r1 r2 y1 b3 r5",
+			actions:     []*Action{NewAction(ActionTypeRankClue, 1, 1)},
+			clue:        NewAction(ActionTypeRankClue, 0, 1),
+			err:         true,
+		},
+		{
+			description: "a color clue after a rank clue in an alternating clues variant",
+			variant:     alternatingClues,
+			hand:        "r1 r2 y1 b3 r5",
+			actions:     []*Action{NewAction(ActionTypeRankClue, 1, 1)},
+			clue:        NewAction(ActionTypeColorClue, 0, 0),
+			touched:     []int{0, 1, 2, 3, 4},
+		},
+	}
+
+	for _, tc := range testCases {
+		options := tc.options
+		if options == nil {
+			options = &Options{} // nolint: exhaustivestruct
+		}
+
+		// Alice has the cards 0 through 4 and there are a few cards left in the deck
+		deck := "r1 r1 r2 r2 r3 " + tc.hand + " r3 r4 r4 r5"
+		s := newTestState(t, tc.variant, options, 2, deck)
+		s = applyTestActions(t, tc.description, s, tc.actions)
+
+		s2, events, err := s.Apply(tc.clue)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: the clue was allowed", tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: the clue was not allowed: %v", tc.description, err)
+			continue
+		}
+
+		touched := getTestClueTouched(events)
+		if !reflect.DeepEqual(touched, tc.touched) {
+			t.Errorf("%s: the clue touched %v instead of %v", tc.description, touched,
+				tc.touched)
+		}
+		expectedClueTokens := s.ClueTokens - tc.variant.GetAdjustedClueTokens(1)
+		if s2.ClueTokens != expectedClueTokens {
+			t.Errorf("%s: there are %d clue tokens instead of %d", tc.description,
+				s2.ClueTokens, expectedClueTokens)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// This file contains the functions for exporting many games from the database at once
+// Games are written as newline-delimited JSON (one "GameJSON" object per line) inside of a gzip
+// archive
+// The games are read from the database one page at a time so that the entire export never has to
+// be held in memory
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// The number of games that are read from the database at once
+	BulkExportPageSize = 500
+	// The maximum number of games that can be exported in a single HTTP request
+	// (use the "after" parameter to get the next set of games)
+	MaxBulkExportGames = 1000
+	// The maximum number of HTTP requests that can be exporting games at the same time
+	// (the endpoint does not require a login and every game is a few database queries)
+	MaxConcurrentBulkExports = 2
+	BulkExportDateFormat     = "2006-01-02"
+)
+
+var (
+	// Every HTTP request that is exporting games holds a slot in this channel until it is finished
+	bulkExportSlots = make(chan struct{}, MaxConcurrentBulkExports)
+)
+
+// GameExportFilter contains the conditions that a game must match to be exported
+// (the zero value of each field means that there is no condition)
+type GameExportFilter struct {
+	VariantID  int // -1 for every variant
+	NumPlayers int
+	Start      time.Time // Inclusive
+	End        time.Time // Exclusive
+	PlayerID   int
+	Tag        string
+	Seed       string
+}
+
+// parseGameExportFilter reads a filter from URL query parameters
+// e.g. "?variant=No Variant&players=3&start=2020-01-01&end=2021-01-01&player=Alice&tag=bdr"
+// (a seed can also be specified with e.g. "&seed=p3v0s1")
+func parseGameExportFilter(values url.Values) (*GameExportFilter, error) {
+	filter := &GameExportFilter{
+		VariantID:  -1,
+		NumPlayers: 0,
+		Start:      time.Time{},
+		End:        time.Time{},
+		PlayerID:   0,
+		Tag:        "",
+		Seed:       values.Get("seed"),
+	}
+
+	if variantName := values.Get("variant"); variantName != "" {
+		if variant, ok := variants[variantName]; !ok {
+			return nil, errors.New("\"" + variantName + "\" is not a valid variant.")
+		} else {
+			filter.VariantID = variant.ID
+		}
+	}
+
+	if numPlayersString := values.Get("players"); numPlayersString != "" {
+		if v, err := strconv.Atoi(numPlayersString); err != nil ||
+			v < MinPlayers || v > MaxPlayers {
+
+			return nil, errors.New("\"" + numPlayersString +
+				"\" is not a valid number of players.")
+		} else {
+			filter.NumPlayers = v
+		}
+	}
+
+	if startString := values.Get("start"); startString != "" {
+		if v, err := time.Parse(BulkExportDateFormat, startString); err != nil {
+			return nil, errors.New("The start date must be in the format of YYYY-MM-DD.")
+		} else {
+			filter.Start = v
+		}
+	}
+
+	if endString := values.Get("end"); endString != "" {
+		if v, err := time.Parse(BulkExportDateFormat, endString); err != nil {
+			return nil, errors.New("The end date must be in the format of YYYY-MM-DD.")
+		} else {
+			filter.End = v
+		}
+	}
+
+	if username := values.Get("player"); username != "" {
+		if exists, user, err := models.Users.Get(username); err != nil {
+			return nil, errors.New("Failed to get user \"" + username + "\": " + err.Error())
+		} else if !exists {
+			return nil, errors.New("The player \"" + username + "\" does not exist in the database.")
+		} else {
+			filter.PlayerID = user.ID
+		}
+	}
+
+	if tag := values.Get("tag"); tag != "" {
+		if v, err := sanitizeTag(tag); err != nil {
+			return nil, err
+		} else {
+			filter.Tag = v
+		}
+	}
+
+	return filter, nil
+}
+
+// writeGameExport writes every game that matches the filter (with an ID greater than "after") to
+// the writer as a gzip archive
+// A limit of 0 means that there is no limit
+// It returns the number of games that were written and the ID of the last game
+// (which can be used as the "after" value to continue the export)
+func writeGameExport(
+	w io.Writer,
+	filter *GameExportFilter,
+	after int,
+	limit int,
+) (int, int, error) {
+	gzipWriter := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gzipWriter) // "Encode()" ends every game with a newline
+
+	numGames := 0
+	cursor := after
+	for limit == 0 || numGames < limit {
+		pageSize := BulkExportPageSize
+		if limit != 0 && limit-numGames < pageSize {
+			pageSize = limit - numGames
+		}
+
+		var gameIDs []int
+		if v, err := models.Games.GetGameIDsExport(filter, cursor, pageSize); err != nil {
+			return numGames, cursor, err
+		} else {
+			gameIDs = v
+		}
+		if len(gameIDs) == 0 {
+			break
+		}
+
+		for _, gameID := range gameIDs {
+			var gameJSON *GameJSON
+			if v, err := getGameJSON(gameID); err != nil {
+				return numGames, cursor, errors.New("failed to export game " + strconv.Itoa(gameID) +
+					": " + err.Error())
+			} else {
+				gameJSON = v
+			}
+
+			if err := encoder.Encode(gameJSON); err != nil {
+				return numGames, cursor, err
+			}
+			numGames++
+			cursor = gameID
+		}
+
+		// Send the games that have been compressed so far before reading the next page
+		if err := gzipWriter.Flush(); err != nil {
+			return numGames, cursor, err
+		}
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return numGames, cursor, err
+	}
+
+	return numGames, cursor, nil
+}
+
+// exportCLI is run when the server is started with "export" as the first argument
+// It writes the games to a file instead of serving them over HTTP, e.g.
+// ./hanabi-live export -variant "No Variant" -players 3 -output games.ndjson.gz
+func exportCLI(args []string) {
+	flagSet := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flagSet.String("output", "games.ndjson.gz", "the path of the file to write")
+	after := flagSet.Int("after", 0, "only export games with an ID greater than this")
+	limit := flagSet.Int("limit", 0, "the maximum number of games to export (0 for no limit)")
+	filterFlags := map[string]*string{
+		"variant": flagSet.String("variant", "", "the name of the variant"),
+		"players": flagSet.String("players", "", "the number of players"),
+		"start":   flagSet.String("start", "", "the first date to include (YYYY-MM-DD)"),
+		"end":     flagSet.String("end", "", "the date to stop at (YYYY-MM-DD)"),
+		"player":  flagSet.String("player", "", "the name of a player in the game"),
+		"tag":     flagSet.String("tag", "", "a tag on the game"),
+		"seed":    flagSet.String("seed", "", "the seed of the game"),
+	}
+	if err := flagSet.Parse(args); err != nil {
+		logger.Fatal("Failed to parse the export arguments: " + err.Error())
+		return
+	}
+
+	// The filter is specified in the same way as it is for the "/export-bulk" endpoint
+	values := url.Values{}
+	for name, value := range filterFlags {
+		if *value != "" {
+			values.Set(name, *value)
+		}
+	}
+	var filter *GameExportFilter
+	if v, err := parseGameExportFilter(values); err != nil {
+		logger.Fatal("Failed to parse the export filter: " + err.Error())
+		return
+	} else {
+		filter = v
+	}
+
+	var file *os.File
+	if v, err := os.Create(*output); err != nil {
+		logger.Fatal("Failed to create \"" + *output + "\": " + err.Error())
+		return
+	} else {
+		file = v
+	}
+	defer file.Close()
+
+	numGames, lastGameID, err := writeGameExport(file, filter, *after, *limit)
+	if err != nil {
+		logger.Fatal("Failed to export the games (after " + strconv.Itoa(numGames) +
+			" games, up to game " + strconv.Itoa(lastGameID) + "): " + err.Error())
+		return
+	}
+	logger.Info("Exported " + strconv.Itoa(numGames) + " games to \"" + *output + "\". " +
+		"(The ID of the last game was " + strconv.Itoa(lastGameID) + ".)")
+}
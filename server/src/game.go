@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
-	"strconv"
+	"math/rand"
 	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
 )
 
 // Game is a sub-object of a table
@@ -32,21 +34,23 @@ type Game struct {
 	// The seed specifies how the deck is dealt
 	// It is either entered manually by players before the game starts or
 	// randomly selected by the server upon starting a game
-	Seed                string
-	Deck                []*Card
-	CardIdentities      []*CardIdentity // A bare-bones version of the deck
-	DeckIndex           int
-	Stacks              []int
-	PlayStackDirections []int // The values for this are listed in "constants.go"
-	Turn                int   // Starts at 0; the client will represent turn 0 as turn 1 to the user
-	DatetimeTurnBegin   time.Time
-	TurnsInverted       bool
-	ActivePlayerIndex   int // Every game always starts with the 0th player going first
-	ClueTokens          int
-	Score               int
-	MaxScore            int
-	Strikes             int
-	LastClueTypeGiven   int // Used in "Alternating Clues" variants
+	Seed string
+	// Rand is the random number generator for this game, derived from the seed
+	// It is used for the deck shuffle and the order of the players
+	// (it is only needed when the game starts, so it is not restored after a server restart)
+	Rand *rand.Rand `json:"-"`
+	// State contains everything that relates to the rules of the game
+	// It is replaced with a new state every time that an action is applied by the engine
+	State *engine.State
+	// InitialState is a copy of the state right after the cards were dealt
+	// (it is used to recreate the state at any point in the game, e.g. in a replay)
+	// Both this field and "TurnDurations" are saved along with the table,
+	// but they are missing from tables that were saved by an older version of the server
+	InitialState      *engine.State
+	DatetimeTurnBegin time.Time
+	// TurnTimeTaken is the time that the active player spent on the current turn before the game
+	// was last paused
+	TurnTimeTaken time.Duration
 	// Actions is a list of all of the in-game moves that players have taken thus far
 	// Different actions will have different fields, so we need this to be an generic interface
 	// Furthermore, we do not want this to be a pointer of interfaces because
@@ -55,28 +59,43 @@ type Game struct {
 	Actions []interface{}
 	// Actions2 is a database-compatible representation of in-game moves
 	// (it is much less verbose when compared with Actions)
-	Actions2              []*GameAction
+	Actions2 []*GameAction
+	// TurnDurations is how long each of the actions in Actions2 took,
+	// so there is always exactly one duration for every action
+	// (the action that ends the game takes no time)
+	TurnDurations         []time.Duration
 	InvalidActionOccurred bool // Used when emulating game actions in replays
-	EndCondition          int  // The values for this are listed in "constants.go"
-	// The index of the player who ended the game, if any
-	// (needed for writing a "game over" terminate action to the database)
-	EndPlayer int
-	// Initialized to -1 and set when the final card is drawn
-	// (to determine when the game should end)
-	EndTurn int
+	// NumUndos is the number of actions that were taken back (see "undo.go")
+	NumUndos int
 
 	// Time & Pause related fields
 	StartedTimer     bool // The timer is only started when the initial player has finished loading
 	Paused           bool
 	PausePlayerIndex int
 	PauseCount       int
+	// Timer is the scheduler that checks to see if the active player has run out of time
+	// (it must be recreated after the game is unserialized)
+	Timer *TurnTimer `json:"-"`
+
+	// Spectator delay fields (see "spectator_delay.go")
+	// The actions that have not been sent to the spectators yet
+	DelayedActions []*DelayedAction
+	// SpectatorTimer is the scheduler that releases actions after a delay in seconds
+	// (it must be recreated after the game is unserialized)
+	SpectatorTimer *TurnTimer `json:"-"`
 
 	// Shared replay fields
 	EfficiencyMod int
+	// Annotations that have been saved from shared replays of this game (see "replay_annotations.go")
+	ReplayAnnotations []*ReplayAnnotation
+	// The statistics that were generated when the game ended (see "game_analysis.go")
+	Analysis *GameAnalysis
 
 	// Hypothetical-related fields
-	Hypothetical       bool // Whether or not we are in a post-game hypothetical
-	HypoActions        []string
+	Hypothetical bool // Whether or not we are in a post-game hypothetical
+	// Hypotheticals are stored as a tree of named branches (see "hypothetical.go")
+	HypoBranches       map[string]*HypoBranch // Keyed by the name of the branch
+	HypoCurrentBranch  string
 	HypoShowDrawnCards bool // Whether or not drawn cards should be revealed (false by default)
 
 	// Keep track of user-defined tags; they will be written to the database upon game completion
@@ -96,53 +115,38 @@ func NewGame(t *Table) *Game {
 
 		Players:               make([]*GamePlayer, 0),
 		Seed:                  "",
-		Deck:                  make([]*Card, 0),
-		CardIdentities:        make([]*CardIdentity, 0),
-		DeckIndex:             0,
-		Stacks:                make([]int, len(variant.Suits)),
-		PlayStackDirections:   make([]int, len(variant.Suits)),
-		Turn:                  0,
+		Rand:                  nil,
+		State:                 engine.NewState(variant, t.Options.EngineOptions()),
+		InitialState:          nil,
 		DatetimeTurnBegin:     time.Time{},
-		TurnsInverted:         false,
-		ActivePlayerIndex:     0,
-		ClueTokens:            variant.GetAdjustedClueTokens(MaxClueNum),
-		Score:                 0,
-		MaxScore:              len(variant.Suits) * PointsPerSuit,
-		Strikes:               0,
-		LastClueTypeGiven:     -1,
+		TurnTimeTaken:         0,
 		Actions:               make([]interface{}, 0),
 		Actions2:              make([]*GameAction, 0),
+		TurnDurations:         make([]time.Duration, 0),
 		InvalidActionOccurred: false,
-		EndCondition:          0,
-		EndPlayer:             -1,
-		EndTurn:               -1,
+		NumUndos:              0,
 
 		StartedTimer:     false,
 		Paused:           false,
 		PausePlayerIndex: -1,
 		PauseCount:       0,
+		Timer:            NewTurnTimer(),
 
-		EfficiencyMod: 0,
+		DelayedActions: make([]*DelayedAction, 0),
+		SpectatorTimer: NewTurnTimer(),
+
+		EfficiencyMod:     0,
+		ReplayAnnotations: make([]*ReplayAnnotation, 0),
+		Analysis:          nil,
 
 		Hypothetical:       false,
-		HypoActions:        make([]string, 0),
+		HypoBranches:       make(map[string]*HypoBranch),
+		HypoCurrentBranch:  "",
 		HypoShowDrawnCards: false,
 
 		Tags: make(map[string]int),
 	}
 
-	// Reverse the stack direction of reversed suits, except on the "Up or Down" variant
-	// that uses the "Undecided" direction.
-	if variant.HasReversedSuits() && !variant.IsUpOrDown() {
-		for i, s := range variant.Suits {
-			if s.Reversed {
-				g.PlayStackDirections[i] = StackDirectionDown
-			} else {
-				g.PlayStackDirections[i] = StackDirectionUp
-			}
-		}
-	}
-
 	// Also, attach this new Game object to the parent table
 	g.Table.Game = g
 
@@ -153,17 +157,9 @@ func NewGame(t *Table) *Game {
 	Major functions
 */
 
-// CheckTimer is meant to be called in a new goroutine
-func (g *Game) CheckTimer(
-	ctx context.Context,
-	timeToSleep time.Duration,
-	turn int,
-	pauseCount int,
-	gp *GamePlayer,
-) {
-	// Sleep until the active player runs out of time
-	time.Sleep(timeToSleep)
-
+// CheckTimer is called by the turn timer when the active player runs out of time
+// (see the "StartTimer()" function)
+func (g *Game) CheckTimer(ctx context.Context, turn int, pauseCount int, gp *GamePlayer) {
 	// Local variables
 	t := g.Table
 
@@ -176,7 +172,8 @@ func (g *Game) CheckTimer(
 	defer t.Unlock(ctx)
 
 	// Check to see if we have made a move in the meanwhile
-	if turn != g.Turn {
+	// (the timer may have fired right before it was rescheduled)
+	if turn != g.State.Turn {
 		return
 	}
 
@@ -191,7 +188,7 @@ func (g *Game) CheckTimer(
 	}
 
 	// Check to see if the game ended already
-	if g.EndCondition > EndConditionInProgress {
+	if g.State.EndCondition > EndConditionInProgress {
 		return
 	}
 
@@ -209,6 +206,10 @@ func (g *Game) EndTimer(ctx context.Context, gp *GamePlayer) {
 
 	// Adjust the final player's time (for the purposes of displaying the correct ending times)
 	gp.Time = 0
+	gp.ByoYomiPeriods = 0
+	if g.Options.TeamClock {
+		g.SyncTeamClock(gp)
+	}
 
 	// Get the session of this player
 	p := t.Players[gp.Index]
@@ -231,186 +232,18 @@ func (g *Game) EndTimer(ctx context.Context, gp *GamePlayer) {
 	})
 }
 
-// CheckEnd examines the game state and sets "EndCondition" to the appropriate value, if any
-func (g *Game) CheckEnd() bool {
-	// Local variables
-	t := g.Table
-	variant := variants[g.Options.VariantName]
-
-	// Some ending conditions will already be set by the time we get here
-	if g.EndCondition == EndConditionTimeout ||
-		g.EndCondition == EndConditionTerminated ||
-		g.EndCondition == EndConditionIdleTimeout ||
-		g.EndCondition == EndConditionCharacterSoftlock {
-
-		return true
-	}
-
-	// Check for 3 strikes
-	if g.Strikes == MaxStrikeNum {
-		logger.Info(t.GetName() + "3 strike maximum reached; ending the game.")
-		g.EndCondition = EndConditionStrikeout
-		return true
-	}
-
-	// In a speedrun, check to see if a perfect score can still be achieved
-	if g.Options.Speedrun && g.MaxScore < variant.MaxScore {
-		logger.Info(t.GetName() + "A perfect score is impossible in a speedrun; ending the game.")
-		g.EndCondition = EndConditionSpeedrunFail
-		return true
-	}
-
-	// In an "All or Nothing" game, check to see if a maximum score can still be reached
-	if g.Options.AllOrNothing && g.MaxScore < variant.MaxScore {
-		logger.Info(t.GetName() + "A perfect score is impossible in an \"All or Nothing\" game; ending the game.")
-		g.EndCondition = EndConditionAllOrNothingFail
-		return true
-	}
-
-	// In an "All or Nothing game",
-	// handle the case where a player would have to discard without any cards in their hand
-	if g.Options.AllOrNothing &&
-		len(g.Players[g.ActivePlayerIndex].Hand) == 0 &&
-		g.ClueTokens < variant.GetAdjustedClueTokens(1) {
-
-		logger.Info(t.GetName() + "The current player has no cards and no clue tokens in an \"All or Nothing\" game; ending the game.")
-		g.EndCondition = EndConditionAllOrNothingSoftlock
-		g.EndPlayer = g.Players[g.ActivePlayerIndex].Index
-		return true
-	}
-
-	// Check to see if the final go-around has completed
-	// (which is initiated after the last card is played from the deck)
-	if g.Turn == g.EndTurn {
-		logger.Info(t.GetName() + "Final turn reached; ending the game.")
-		g.EndCondition = EndConditionNormal
-		return true
-	}
-
-	// Check to see if the maximum score has been reached
-	if g.Score == g.MaxScore {
-		logger.Info(t.GetName() + "Maximum score reached; ending the game.")
-		g.EndCondition = EndConditionNormal
-		return true
-	}
-
-	// Check to see if there are any cards remaining that can be played on the stacks
-	if variant.HasReversedSuits() {
-		// Searching for the next card is much more complicated if we are playing an "Up or Down"
-		// or "Reversed" variant, so the logic for this is stored in a separate file
-		if !variantReversibleCheckAllDead(g) {
-			return false
-		}
-	} else {
-		for i, stackLen := range g.Stacks {
-			// Search through the deck
-			if stackLen == 5 {
-				continue
-			}
-			neededSuit := i
-			neededRank := stackLen + 1
-			for _, c := range g.Deck {
-				if c.SuitIndex == neededSuit &&
-					c.Rank == neededRank &&
-					!c.Discarded &&
-					!c.CannotBePlayed {
-
-					return false
-				}
-			}
-		}
-	}
-
-	// If we got this far, nothing can be played
-	logger.Info(t.GetName() + "No remaining cards can be played; ending the game.")
-	g.EndCondition = EndConditionNormal
-	return true
-}
-
 /*
 	Miscellaneous functions
 */
 
-func (g *Game) GetHandSize() int {
-	handSize := g.GetHandSizeForNormalGame()
-	if g.Options.OneExtraCard {
-		handSize++
+// SetState replaces the engine state and points every player to their new engine counterpart
+func (g *Game) SetState(state *engine.State) {
+	g.State = state
+	for i, gp := range g.Players {
+		gp.Player = state.Players[i]
 	}
-	if g.Options.OneLessCard {
-		handSize--
-	}
-	return handSize
-}
-
-func (g *Game) GetHandSizeForNormalGame() int {
-	// Local variables
-	t := g.Table
-	numPlayers := len(g.Players)
-
-	if numPlayers == 2 || numPlayers == 3 {
-		return 5
-	} else if numPlayers == 4 || numPlayers == 5 {
-		return 4
-	} else if numPlayers == 6 {
-		return 3
-	}
-
-	logger.Error("Failed to get the hand size for " + strconv.Itoa(numPlayers) +
-		" players for game: " + t.Name)
-	return 4
-}
-
-// GetMaxScore calculates what the maximum score is,
-// accounting for stacks that cannot be completed due to discarded cards
-func (g *Game) GetMaxScore() int {
-	// Local variables
-	variant := variants[g.Options.VariantName]
-
-	// Getting the maximum score is much more complicated if we are playing a
-	// "Reversed" or "Up or Down" variant
-	if variant.HasReversedSuits() {
-		return variantReversibleGetMaxScore(g)
-	}
-
-	maxScore := 0
-	for suit := range g.Stacks {
-		for rank := 1; rank <= 5; rank++ {
-			// Search through the deck to see if all the copies of this card are discarded already
-			total, discarded := g.GetSpecificCardNum(suit, rank)
-			if total > discarded {
-				maxScore++
-			} else {
-				break
-			}
-		}
-	}
-
-	return maxScore
-}
-
-// GetSpecificCardNum returns the total cards in the deck of the specified suit and rank
-// as well as how many of those that have been already discarded
-func (g *Game) GetSpecificCardNum(suitIndex int, rank int) (int, int) {
-	total := 0
-	discarded := 0
-	for _, c := range g.Deck {
-		if c.SuitIndex == suitIndex && c.Rank == rank {
-			total++
-			if c.Discarded {
-				discarded++
-			}
-		}
-	}
-
-	return total, discarded
 }
 
 func (g *Game) GetNotesSize() int {
-	// Local variables
-	variant := variants[g.Options.VariantName]
-
-	// There are notes for every card in the deck + the stack bases for each suit
-	numCards := len(g.Deck)
-	numSuits := len(variant.Suits)
-	return numCards + numSuits
+	return g.State.GetNotesSize()
 }
@@ -0,0 +1,303 @@
+// This file contains the functions for the analysis that is generated at the end of every game
+// The analysis is made by simulating every action of the game again and looking at the events that
+// were generated
+// Efficiency is calculated in the same way as it is in the client:
+// the number of cards that were "gotten" divided by the number of potential clues that were used up
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+type GameAnalysis struct {
+	// Cards that were played or are clued in a hand and not trash (up to the maximum score)
+	CardsGotten int `json:"cardsGotten"`
+	// Clues that were given, strikes, and clues that were wasted by playing a 5 at maximum clues
+	PotentialCluesLost float64 `json:"potentialCluesLost"`
+	// This is 0 if no potential clues were lost
+	Efficiency float64 `json:"efficiency"`
+	// Games that were not recorded live (e.g. games that are imported from JSON) have no times
+	HasTimes bool              `json:"hasTimes"`
+	Players  []*PlayerAnalysis `json:"players"`
+}
+
+type PlayerAnalysis struct {
+	Name             string `json:"name"`
+	CluesGiven       int    `json:"cluesGiven"`
+	CardsPlayed      int    `json:"cardsPlayed"`
+	CardsDiscarded   int    `json:"cardsDiscarded"`
+	Misplays         int    `json:"misplays"`
+	CriticalDiscards int    `json:"criticalDiscards"` // Including misplays of critical cards
+	// A card is credited to the player who clued it first
+	// (or to the player who played it, if it was never clued)
+	CardsGotten        int     `json:"cardsGotten"`
+	PotentialCluesLost float64 `json:"potentialCluesLost"`
+	Efficiency         float64 `json:"efficiency"`
+	NumTurns           int     `json:"numTurns"`
+	// JavaScript expects time in milliseconds
+	TurnTimes       []int64 `json:"turnTimes"`
+	TimeUsed        int64   `json:"timeUsed"`
+	AverageTurnTime int64   `json:"averageTurnTime"`
+}
+
+// analyzeGame simulates all of the actions of a game from the provided initial state
+// "turnDurations" should contain the time taken for each action, or be nil if it is not known
+func analyzeGame(
+	initialState *engine.State,
+	playerNames []string,
+	actions []*GameAction,
+	turnDurations []time.Duration,
+) (*GameAnalysis, error) {
+	if initialState == nil {
+		return nil, errors.New("the initial state of the game is not available")
+	}
+
+	// Local variables
+	variant := initialState.Variant
+	hasTimes := turnDurations != nil && len(turnDurations) == len(actions)
+
+	// In "Clue Starved" variants, each discard only grants half of a clue
+	discardValue := 1.0
+	if variant.DiscardsGiveHalfClue {
+		discardValue = 0.5
+	}
+
+	analysis := &GameAnalysis{
+		CardsGotten:        0,
+		PotentialCluesLost: 0,
+		Efficiency:         0,
+		HasTimes:           hasTimes,
+		Players:            make([]*PlayerAnalysis, 0),
+	}
+	for _, name := range playerNames {
+		analysis.Players = append(analysis.Players, &PlayerAnalysis{
+			Name:               name,
+			CluesGiven:         0,
+			CardsPlayed:        0,
+			CardsDiscarded:     0,
+			Misplays:           0,
+			CriticalDiscards:   0,
+			CardsGotten:        0,
+			PotentialCluesLost: 0,
+			Efficiency:         0,
+			NumTurns:           0,
+			TurnTimes:          make([]int64, 0),
+			TimeUsed:           0,
+			AverageTurnTime:    0,
+		})
+	}
+
+	// Keep track of who is responsible for each card (keyed by the order of the card)
+	cluedBy := make(map[int]int)
+	playedBy := make(map[int]int)
+
+	state := initialState
+	for i, a := range actions {
+		var newState *engine.State
+		var events []engine.Event
+		if v1, v2, err := state.Apply(a); err != nil {
+			return nil, errors.New("failed to apply action " + strconv.Itoa(i) + ": " +
+				err.Error())
+		} else {
+			newState = v1
+			events = v2
+		}
+
+		// The action that ends the game is not a turn
+		if a.Type == ActionTypeEndGame {
+			state = newState
+			continue
+		}
+
+		p := analysis.Players[state.ActivePlayerIndex]
+		p.NumTurns++
+		if hasTimes {
+			milliseconds := int64(turnDurations[i] / time.Millisecond)
+			p.TurnTimes = append(p.TurnTimes, milliseconds)
+			p.TimeUsed += milliseconds
+		}
+
+		for _, event := range events {
+			switch e := event.(type) {
+			case engine.EventClue:
+				p.CluesGiven++
+				p.PotentialCluesLost++
+				for _, order := range e.List {
+					if _, ok := cluedBy[order]; !ok && !state.Deck[order].Touched {
+						cluedBy[order] = state.ActivePlayerIndex
+					}
+				}
+
+			case engine.EventPlay:
+				p.CardsPlayed++
+				playedBy[e.Order] = state.ActivePlayerIndex
+
+				// Completing a stack while at the maximum amount of clues wastes the extra clue
+				// (except in variants that do not grant a clue for completing a stack)
+				completedStack := e.Rank == 5
+				if variant.HasReversedSuits() {
+					completedStack = newState.PlayStackDirections[e.SuitIndex] ==
+						engine.StackDirectionFinished
+				}
+				if completedStack && state.AtMaxClueTokens() && !variant.NoClueForPlaying5 {
+					p.PotentialCluesLost += discardValue
+				}
+
+			case engine.EventDiscard:
+				if e.Failed {
+					p.Misplays++
+				} else {
+					p.CardsDiscarded++
+				}
+
+				// A card is critical if losing it lowers the maximum score
+				if newState.MaxScore < state.MaxScore {
+					p.CriticalDiscards++
+				}
+
+			case engine.EventStrike:
+				p.PotentialCluesLost += discardValue
+			}
+		}
+
+		state = newState
+	}
+
+	// Credit every card that was gotten to the player who was responsible for it
+	teamCardsGotten := 0
+	for _, c := range state.Deck {
+		gotten := c.Played
+		if !c.Played && !c.Discarded && c.Order < state.DeckIndex {
+			// This card is still in someone's hand
+			gotten = c.Touched && !isCardTrash(state, c)
+		}
+		if !gotten {
+			continue
+		}
+		teamCardsGotten++
+
+		if playerIndex, ok := cluedBy[c.Order]; ok {
+			analysis.Players[playerIndex].CardsGotten++
+		} else if playerIndex, ok := playedBy[c.Order]; ok {
+			analysis.Players[playerIndex].CardsGotten++
+		}
+	}
+	if teamCardsGotten > state.MaxScore {
+		teamCardsGotten = state.MaxScore
+	}
+	analysis.CardsGotten = teamCardsGotten
+
+	for _, p := range analysis.Players {
+		analysis.PotentialCluesLost += p.PotentialCluesLost
+		p.Efficiency = getEfficiency(p.CardsGotten, p.PotentialCluesLost)
+		if len(p.TurnTimes) > 0 {
+			p.AverageTurnTime = p.TimeUsed / int64(len(p.TurnTimes))
+		}
+	}
+	analysis.Efficiency = getEfficiency(analysis.CardsGotten, analysis.PotentialCluesLost)
+
+	return analysis, nil
+}
+
+func getEfficiency(cardsGotten int, potentialCluesLost float64) float64 {
+	if potentialCluesLost == 0 {
+		return 0
+	}
+
+	return float64(cardsGotten) / potentialCluesLost
+}
+
+// isCardTrash returns whether or not a card can no longer be played on its stack
+func isCardTrash(state *engine.State, c *engine.Card) bool {
+	stack := state.Stacks[c.SuitIndex]
+	switch state.PlayStackDirections[c.SuitIndex] {
+	case engine.StackDirectionUp:
+		return c.Rank <= stack
+
+	case engine.StackDirectionDown:
+		return stack != 0 && c.Rank >= stack
+
+	case engine.StackDirectionFinished:
+		return true
+
+	default:
+		// In variants that are not reversible, every stack is played from 1 to 5
+		if !state.Variant.HasReversedSuits() {
+			return c.Rank <= stack
+		}
+
+		// The direction of the stack has not been decided yet
+		return false
+	}
+}
+
+// WriteAnalysis analyzes the game and records the analysis in the database
+// (this must be called after the game is written to the database)
+func (g *Game) WriteAnalysis() {
+	// Local variables
+	t := g.Table
+
+	playerNames := make([]string, 0)
+	for _, p := range g.Players {
+		playerNames = append(playerNames, p.Name)
+	}
+
+	if g.InitialState == nil {
+		logger.Warn(t.GetName() + "Skipping the game analysis since the initial state of the " +
+			"game is not available.")
+		return
+	}
+
+	if v, err := analyzeGame(g.InitialState, playerNames, g.Actions2, g.TurnDurations); err != nil {
+		logger.Error(t.GetName() + "Failed to analyze the game: " + err.Error())
+		return
+	} else {
+		g.Analysis = v
+	}
+
+	var analysisJSON []byte
+	if v, err := json.Marshal(g.Analysis); err != nil {
+		logger.Error(t.GetName() + "Failed to marshal the game analysis: " + err.Error())
+		return
+	} else {
+		analysisJSON = v
+	}
+
+	if err := models.GameAnalyses.Insert(g.ExtraOptions.DatabaseID, analysisJSON); err != nil {
+		logger.Error(t.GetName() + "Failed to insert the game analysis: " + err.Error())
+	}
+}
+
+// loadAnalysis gets the analysis of a game from the database (if it exists)
+// The table lock is assumed to be acquired in this function
+func (t *Table) loadAnalysis() bool {
+	// Local variables
+	g := t.Game
+
+	var analysisJSON []byte
+	if v, exists, err := models.GameAnalyses.Get(t.ExtraOptions.DatabaseID); err != nil {
+		logger.Error("Failed to get the analysis for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		return false
+	} else if !exists {
+		return true
+	} else {
+		analysisJSON = v
+	}
+
+	analysis := &GameAnalysis{}
+	if err := json.Unmarshal(analysisJSON, analysis); err != nil {
+		logger.Error("Failed to unmarshal the analysis for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		return false
+	}
+	g.Analysis = analysis
+
+	return true
+}
@@ -0,0 +1,216 @@
+// This file contains the clock logic for timed games
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TurnTimer is a per-table scheduler that fires when the active player runs out of time
+// There is only ever one pending timer for a table;
+// it is rescheduled whenever the turn changes or the game is unpaused
+type TurnTimer struct {
+	timer *time.Timer
+	mutex *sync.Mutex
+}
+
+func NewTurnTimer() *TurnTimer {
+	return &TurnTimer{
+		timer: nil,
+		mutex: &sync.Mutex{},
+	}
+}
+
+// Schedule replaces the pending timer (if any) with one that will call the provided function after
+// the provided duration
+func (tt *TurnTimer) Schedule(d time.Duration, f func()) {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	if tt.timer != nil {
+		tt.timer.Stop()
+	}
+	tt.timer = time.AfterFunc(d, f)
+}
+
+// Stop cancels the pending timer (if any)
+func (tt *TurnTimer) Stop() {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+
+	if tt.timer != nil {
+		tt.timer.Stop()
+		tt.timer = nil
+	}
+}
+
+/*
+	Game clock functions
+*/
+
+// StartTimer schedules the check for when the active player runs out of time
+// The table lock is assumed to be acquired in this function
+func (g *Game) StartTimer(ctx context.Context) {
+	if (!g.Options.Timed && !g.Options.Correspondence) || g.ExtraOptions.NoWriteToDatabase {
+		return
+	}
+
+	// The closure must not look at the game state,
+	// since it will run later on without the table lock
+	turn := g.State.Turn
+	pauseCount := g.PauseCount
+	activePlayer := g.Players[g.State.ActivePlayerIndex]
+	g.Timer.Schedule(g.GetTimeUntilTimeout(activePlayer), func() {
+		g.CheckTimer(ctx, turn, pauseCount, activePlayer)
+	})
+}
+
+// StopTimer cancels the check for when the active player runs out of time
+// (e.g. when the game is paused or has ended)
+func (g *Game) StopTimer() {
+	g.Timer.Stop()
+}
+
+// GetTimePerTurn returns the increment, the delay, or the length of a byo-yomi period,
+// depending on the time control of the game
+func (g *Game) GetTimePerTurn() time.Duration {
+	return time.Duration(g.Options.TimePerTurn) * time.Second
+}
+
+// GetTurnTimeTaken returns how long the active player has spent on the current turn,
+// not counting the time that the game was paused
+func (g *Game) GetTurnTimeTaken() time.Duration {
+	timeTaken := g.TurnTimeTaken
+	if !g.Paused {
+		timeTaken += time.Since(g.DatetimeTurnBegin)
+	}
+
+	return timeTaken
+}
+
+// ChargeTime subtracts the time that a player took on their turn from their clock
+// (and adds time back to it, depending on the time control of the game)
+func (g *Game) ChargeTime(p *GamePlayer, timeTaken time.Duration) {
+	if !g.Options.Timed {
+		// In non-timed games,
+		// "Time" will decrement into negative numbers to show how much time they are taking
+		p.Time -= timeTaken
+		return
+	}
+
+	timePerTurn := g.GetTimePerTurn()
+	switch g.Options.TimeControl {
+	case TimeControlDelay:
+		// The clock does not run until the delay has passed
+		if timeTaken > timePerTurn {
+			p.Time -= timeTaken - timePerTurn
+		}
+
+	case TimeControlBronstein:
+		// The time that was used is given back, up to the delay
+		p.Time -= timeTaken
+		if timeTaken < timePerTurn {
+			p.Time += timeTaken
+		} else {
+			p.Time += timePerTurn
+		}
+
+	case TimeControlByoYomi:
+		// Once the base time runs out, every period that was used up in full is lost
+		// (a period that was only partially used is restored for the next turn)
+		p.Time -= timeTaken
+		if p.Time < 0 {
+			p.ByoYomiPeriods -= int(-p.Time / timePerTurn)
+			p.Time = 0
+		}
+
+	default:
+		// Fischer is the default time control
+		// A player gains additional time after performing an action
+		p.Time -= timeTaken
+		p.Time += timePerTurn
+	}
+
+	if g.Options.TeamClock {
+		g.SyncTeamClock(p)
+	}
+}
+
+// SyncTeamClock copies the clock of the provided player to every other player
+// (when the team shares a clock, every player always has the same amount of time left)
+func (g *Game) SyncTeamClock(p *GamePlayer) {
+	for _, gp := range g.Players {
+		gp.Time = p.Time
+		gp.ByoYomiPeriods = p.ByoYomiPeriods
+	}
+}
+
+// GetTimeUntilTimeout returns how long the provided player can take before they run out of time
+func (g *Game) GetTimeUntilTimeout(p *GamePlayer) time.Duration {
+	// In correspondence games, every move has the same deadline
+	if g.Options.Correspondence {
+		deadline := time.Duration(g.Options.CorrespondenceDeadline) * time.Hour
+		return deadline - g.GetTurnTimeTaken()
+	}
+
+	timeLeft := p.Time
+	switch g.Options.TimeControl {
+	case TimeControlDelay:
+		timeLeft += g.GetTimePerTurn()
+
+	case TimeControlByoYomi:
+		timeLeft += time.Duration(p.ByoYomiPeriods) * g.GetTimePerTurn()
+	}
+
+	if p.Index == g.State.ActivePlayerIndex {
+		timeLeft -= g.GetTurnTimeTaken()
+	}
+
+	return timeLeft
+}
+
+// GetClock returns what should be shown on the clock of the provided player,
+// the number of byo-yomi periods that they have left,
+// and the amount of delay that is left before their clock starts to run
+// (this accounts for the time that has been taken so far on the current turn)
+func (g *Game) GetClock(p *GamePlayer) (time.Duration, int, time.Duration) {
+	timeLeft := p.Time
+	periodsLeft := p.ByoYomiPeriods
+	delayLeft := time.Duration(0)
+
+	// With a shared clock, the time on the current turn counts against everyone
+	if p.Index != g.State.ActivePlayerIndex && !g.Options.TeamClock {
+		return timeLeft, periodsLeft, delayLeft
+	}
+
+	timeTaken := g.GetTurnTimeTaken()
+	if !g.Options.Timed {
+		return timeLeft - timeTaken, periodsLeft, delayLeft
+	}
+
+	timePerTurn := g.GetTimePerTurn()
+	switch g.Options.TimeControl {
+	case TimeControlDelay:
+		if timeTaken < timePerTurn {
+			delayLeft = timePerTurn - timeTaken
+		} else {
+			timeLeft -= timeTaken - timePerTurn
+		}
+
+	case TimeControlByoYomi:
+		timeLeft -= timeTaken
+		if timeLeft < 0 && timePerTurn > 0 {
+			// The base time has run out, so show how much time is left in the current period
+			overtime := -timeLeft
+			periodsLeft -= int(overtime / timePerTurn)
+			timeLeft = timePerTurn - overtime%timePerTurn
+		}
+
+	default:
+		timeLeft -= timeTaken
+	}
+
+	return timeLeft, periodsLeft, delayLeft
+}
@@ -12,10 +12,22 @@ func (g *Game) End(ctx context.Context, d *CommandData) {
 	t := g.Table
 
 	g.DatetimeFinished = time.Now()
-	if g.EndCondition > EndConditionNormal {
-		g.Score = 0
+	g.StopTimer()
+	t.EndSpectatorDelay()
+	t.CancelVote()
+	t.CancelSubstitution()
+	t.DeleteCorrespondence()
+	if g.State.EndCondition > EndConditionNormal {
+		g.State.Score = 0
+	}
+	logger.Info(t.GetName() + "Ended with a score of " + strconv.Itoa(g.State.Score) + ".")
+
+	// Puzzles are not recorded anywhere, so the table can be deleted right away
+	// (e.g. if the player terminated the puzzle instead of making a move)
+	if g.ExtraOptions.PuzzleID != 0 {
+		t.EndPuzzle(ctx, d)
+		return
 	}
-	logger.Info(t.GetName() + "Ended with a score of " + strconv.Itoa(g.Score) + ".")
 
 	// There will be no times associated with a replay, so don't bother with the rest of the code
 	if g.ExtraOptions.NoWriteToDatabase {
@@ -47,7 +59,7 @@ func (g *Game) End(ctx context.Context, d *CommandData) {
 	// they will be manually set to having a "Shared Replay" status later
 	// after the game is converted)
 	for _, p := range t.Players {
-		if p.Session != nil {
+		if p.Session != nil && p.BotPlayer == nil {
 			p.Session.SetStatus(StatusLobby)
 			p.Session.SetTableID(uint64(0))
 			notifyAllUser(p.Session)
@@ -59,6 +71,10 @@ func (g *Game) End(ctx context.Context, d *CommandData) {
 		return
 	}
 
+	// Analyze the game so that the players can review it in the shared replay
+	// (this uses the ID that is recorded in the "WriteDatabase()" function)
+	g.WriteAnalysis()
+
 	// Send a "gameHistory" message to all the players in the game
 	var numGamesOnThisSeed int
 	if v, err := models.Seeds.GetNumGames(g.Seed); err != nil {
@@ -78,9 +94,10 @@ func (g *Game) End(ctx context.Context, d *CommandData) {
 		ID:                 g.ExtraOptions.DatabaseID,
 		Options:            g.Options,
 		Seed:               g.Seed,
-		Score:              g.Score,
-		NumTurns:           g.Turn,
-		EndCondition:       g.EndCondition,
+		Score:              g.State.Score,
+		NumTurns:           g.State.Turn,
+		EndCondition:       g.State.EndCondition,
+		NumUndos:           g.NumUndos,
 		DatetimeStarted:    g.DatetimeStarted,
 		DatetimeFinished:   g.DatetimeFinished,
 		NumGamesOnThisSeed: numGamesOnThisSeed,
@@ -109,9 +126,10 @@ func (g *Game) WriteDatabase() error {
 		Name:             t.Name,
 		Options:          g.Options,
 		Seed:             g.Seed,
-		Score:            g.Score,
-		NumTurns:         g.Turn,
-		EndCondition:     g.EndCondition,
+		Score:            g.State.Score,
+		NumTurns:         g.State.Turn,
+		EndCondition:     g.State.EndCondition,
+		NumUndos:         g.NumUndos,
 		DatetimeStarted:  g.DatetimeStarted,
 		DatetimeFinished: g.DatetimeFinished,
 	}
@@ -158,6 +176,7 @@ func (g *Game) WriteDatabase() error {
 		gameParticipantsRows = append(gameParticipantsRows, &GameParticipantsRow{
 			GameID:              t.ExtraOptions.DatabaseID,
 			UserID:              p.UserID,
+			OriginalUserID:      p.OriginalUserID,
 			Seat:                gp.Index,
 			CharacterAssignment: characterID,
 			CharacterMetadata:   characterMetadata,
@@ -269,7 +288,13 @@ func (g *Game) WriteDatabaseStats() {
 	t := g.Table
 	variant := variants[g.Options.VariantName]
 	// 2-player is at index 0, 3-player is at index 1, etc.
-	bestScoreIndex := g.Options.NumPlayers - 2
+	bestScoreIndex := g.Options.NumPlayers - MinPlayers
+
+	// By default, games with bots in them are still recorded, but they do not affect any stats
+	if t.HasBots() && !botGamesUpdateStats {
+		logger.Info(t.GetName() + "Skipping the stats update since there is a bot in the game.")
+		return
+	}
 
 	// Update the variant-specific stats for each player
 	modifier := g.Options.GetModifier()
@@ -285,12 +310,13 @@ func (g *Game) WriteDatabaseStats() {
 
 		thisScore := &BestScore{ // nolint: exhaustivestruct
 			NumPlayers: g.Options.NumPlayers,
-			Score:      g.Score,
+			Score:      g.State.Score,
 			Modifier:   modifier,
 		}
 		bestScore := userStats.BestScores[bestScoreIndex]
-		if thisScore.IsBetterThan(bestScore) {
-			bestScore.Score = g.Score
+		// Games with undos do not count towards best scores
+		if thisScore.IsBetterThan(bestScore) && g.NumUndos == 0 {
+			bestScore.Score = g.State.Score
 			bestScore.Modifier = modifier
 		}
 
@@ -313,11 +339,11 @@ func (g *Game) WriteDatabaseStats() {
 		variantStats = v
 	}
 
-	// If the game was played with no modifiers, update the stats for this variant
-	if modifier == 0 {
+	// If the game was played with no modifiers (and no undos), update the stats for this variant
+	if modifier == 0 && g.NumUndos == 0 {
 		bestScore := variantStats.BestScores[bestScoreIndex]
-		if g.Score > bestScore.Score {
-			bestScore.Score = g.Score
+		if g.State.Score > bestScore.Score {
+			bestScore.Score = g.State.Score
 		}
 	}
 
@@ -345,9 +371,9 @@ func (t *Table) ConvertToSharedReplay(ctx context.Context, d *CommandData) {
 	t.InitialName = t.Name
 	t.Name = "Shared replay for game #" + strconv.Itoa(t.ExtraOptions.DatabaseID)
 	// Update the "EndTurn" field (since we incremented the final turn above in an artificial way)
-	g.EndTurn = g.Turn
+	g.State.EndTurn = g.State.Turn
 	// Initialize the shared replay on the 2nd to last turn (since the end times are not important)
-	g.Turn--
+	g.State.Turn--
 	t.Progress = 100
 
 	// Turn the players into spectators
@@ -366,9 +392,15 @@ func (t *Table) ConvertToSharedReplay(ctx context.Context, d *CommandData) {
 			continue
 		}
 
+		// Bots that run inside of the server do not watch replays
+		if p.BotPlayer != nil {
+			tables.DeletePlaying(p.UserID, t.ID)
+			continue
+		}
+
 		// If this game was ended due to idleness,
 		// skip conversion so that the shared replay gets deleted below
-		if g.EndCondition == EndConditionIdleTimeout {
+		if g.State.EndCondition == EndConditionIdleTimeout {
 			continue
 		}
 
@@ -406,7 +438,7 @@ func (t *Table) ConvertToSharedReplay(ctx context.Context, d *CommandData) {
 		// Default to making the first player the leader,
 		// or the second player if the first is away, etc.
 		for _, p := range t.Players {
-			if p.Present {
+			if p.Present && p.BotPlayer == nil {
 				t.OwnerID = p.UserID
 				logger.Info("Set the new leader to be: " + p.Name)
 				break
@@ -445,6 +477,11 @@ func (t *Table) ConvertToSharedReplay(ctx context.Context, d *CommandData) {
 
 		// Send them the notes from all the players & spectators
 		sp.Session.NotifyNoteList(t, -1)
+
+		// Send them the statistics for the game
+		if g.Analysis != nil {
+			sp.Session.NotifyGameAnalysis(t)
+		}
 	}
 
 	notifyAllTable(t)    // Update the spectator list for the row in the lobby
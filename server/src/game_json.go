@@ -1,5 +1,12 @@
 package main
 
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
 type GameJSON struct {
 	ID      int             `json:"id,omitempty"` // Optional element only used for game exports
 	Players []string        `json:"players"`
@@ -17,9 +24,189 @@ type GameJSON struct {
 	// This allows the server to reconstruct the game without the deck being present and to properly
 	// write the game back to the database
 	Seed string `json:"seed,omitempty"`
+	// Annotations is an optional element that contains everything that was saved from the shared
+	// replays of the game (only used for game exports)
+	Annotations []*ReplayAnnotation `json:"annotations,omitempty"`
 }
 
 type CharacterAssignment struct {
 	Name     string `json:"name"`
 	Metadata int    `json:"metadata"`
 }
+
+// GetOptions converts the optional JSON options to a full set of options
+// (the variant must be validated before calling this function)
+func (gameJSON *GameJSON) GetOptions() *Options {
+	options := gameJSON.Options
+	if options == nil {
+		options = &OptionsJSON{}
+	}
+
+	// In order to avoid "runtime error: invalid memory address or nil pointer dereference",
+	// we must explicitly check to see if all pointers exist
+	startingPlayer := 0
+	if options.StartingPlayer != nil {
+		startingPlayer = *options.StartingPlayer
+	}
+	variantName := "No Variant"
+	if options.Variant != nil {
+		variantName = *options.Variant
+	}
+	timed := false
+	if options.Timed != nil {
+		timed = *options.Timed
+	}
+	timeBase := 0
+	if options.TimeBase != nil {
+		timeBase = *options.TimeBase
+	}
+	timePerTurn := 0
+	if options.TimePerTurn != nil {
+		timePerTurn = *options.TimePerTurn
+	}
+	timeControl := TimeControlFischer
+	if options.TimeControl != nil {
+		timeControl = *options.TimeControl
+	}
+	byoYomiPeriods := 0
+	if options.ByoYomiPeriods != nil {
+		byoYomiPeriods = *options.ByoYomiPeriods
+	}
+	teamClock := false
+	if options.TeamClock != nil {
+		teamClock = *options.TeamClock
+	}
+	correspondence := false
+	if options.Correspondence != nil {
+		correspondence = *options.Correspondence
+	}
+	correspondenceDeadline := 0
+	if options.CorrespondenceDeadline != nil {
+		correspondenceDeadline = *options.CorrespondenceDeadline
+	}
+	speedrun := false
+	if options.Speedrun != nil {
+		speedrun = *options.Speedrun
+	}
+	cardCycle := false
+	if options.CardCycle != nil {
+		cardCycle = *options.CardCycle
+	}
+	deckPlays := false
+	if options.DeckPlays != nil {
+		deckPlays = *options.DeckPlays
+	}
+	emptyClues := false
+	if options.EmptyClues != nil {
+		emptyClues = *options.EmptyClues
+	}
+	oneExtraCard := false
+	if options.OneExtraCard != nil {
+		oneExtraCard = *options.OneExtraCard
+	}
+	oneLessCard := false
+	if options.OneLessCard != nil {
+		oneLessCard = *options.OneLessCard
+	}
+	allOrNothing := false
+	if options.AllOrNothing != nil {
+		allOrNothing = *options.AllOrNothing
+	}
+	detrimentalCharacters := false
+	if options.DetrimentalCharacters != nil {
+		detrimentalCharacters = *options.DetrimentalCharacters
+	}
+	startingClueTokens := 0
+	if options.StartingClueTokens != nil {
+		startingClueTokens = *options.StartingClueTokens
+	}
+	maxClueTokens := 0
+	if options.MaxClueTokens != nil {
+		maxClueTokens = *options.MaxClueTokens
+	}
+	strikeLimit := 0
+	if options.StrikeLimit != nil {
+		strikeLimit = *options.StrikeLimit
+	}
+	handSize := 0
+	if options.HandSize != nil {
+		handSize = *options.HandSize
+	}
+
+	return &Options{
+		NumPlayers:            len(gameJSON.Players),
+		StartingPlayer:        startingPlayer,
+		VariantID:             variants[variantName].ID,
+		VariantName:           variantName,
+		Timed:                 timed,
+		TimeBase:              timeBase,
+		TimePerTurn:           timePerTurn,
+		TimeControl:           timeControl,
+		ByoYomiPeriods:        byoYomiPeriods,
+		TeamClock:             teamClock,
+		Speedrun:              speedrun,
+		CardCycle:             cardCycle,
+		DeckPlays:             deckPlays,
+		EmptyClues:            emptyClues,
+		OneExtraCard:          oneExtraCard,
+		OneLessCard:           oneLessCard,
+		AllOrNothing:          allOrNothing,
+		DetrimentalCharacters: detrimentalCharacters,
+		StartingClueTokens:    startingClueTokens,
+		MaxClueTokens:         maxClueTokens,
+		StrikeLimit:           strikeLimit,
+		HandSize:              handSize,
+
+		Correspondence:         correspondence,
+		CorrespondenceDeadline: correspondenceDeadline,
+	}
+}
+
+// GetInitialState deals the cards of the game so that its actions can be simulated
+func (gameJSON *GameJSON) GetInitialState() (*engine.State, error) {
+	variantName := "No Variant"
+	if gameJSON.Options != nil && gameJSON.Options.Variant != nil {
+		variantName = *gameJSON.Options.Variant
+	}
+	variant, ok := variants[variantName]
+	if !ok {
+		return nil, errors.New("\"" + variantName + "\" is not a valid variant")
+	}
+	if len(gameJSON.Players) < MinPlayers || len(gameJSON.Players) > MaxPlayers {
+		return nil, errors.New("the number of players must be between " +
+			strconv.Itoa(MinPlayers) + " and " + strconv.Itoa(MaxPlayers))
+	}
+	for i, name := range gameJSON.Players {
+		if stringIndexInSlice(name, gameJSON.Players) != i {
+			return nil, errors.New("there is more than one player named \"" + name + "\"")
+		}
+	}
+	if len(gameJSON.Deck) == 0 {
+		return nil, errors.New("the deck must be specified")
+	}
+	for _, card := range gameJSON.Deck {
+		if card.SuitIndex < 0 || card.SuitIndex >= len(variant.Suits) {
+			return nil, errors.New("the suit number of " + strconv.Itoa(card.SuitIndex+1) +
+				" is not valid in this variant")
+		}
+	}
+
+	options := gameJSON.GetOptions()
+	if options.StartingPlayer < 0 || options.StartingPlayer >= len(gameJSON.Players) {
+		return nil, errors.New("the starting player of " + strconv.Itoa(options.StartingPlayer) +
+			" is not valid")
+	}
+	state := engine.NewState(variant, options.EngineOptions())
+	state.ActivePlayerIndex = options.StartingPlayer
+	for i, name := range gameJSON.Players {
+		p := state.AddPlayer(name)
+		if i < len(gameJSON.Characters) {
+			p.Character = gameJSON.Characters[i].Name
+			p.CharacterMetadata = gameJSON.Characters[i].Metadata
+		}
+	}
+	state.InitDeck(gameJSON.Deck)
+	state.Deal()
+
+	return state, nil
+}
@@ -0,0 +1,520 @@
+// This file contains the text notation for games,
+// which is a human-readable alternative to the JSON format that is used for replays
+// It converts to and from the "GameJSON" struct without losing any information
+//
+// Example:
+//   id 15103
+//   seed p2v0s1
+//   players Alice Bob
+//   variant No Variant
+//   option startingClueTokens 6
+//   note Alice 12 "chop moved"
+//   deck r1 y3 g5 b1 p2 r4 ...
+//   Alice clues Bob red
+//   Bob plays slot 2
+//   Alice discards slot 5
+//   Bob ends the game (timeout)
+//
+// Lines that start with "#" are comments
+// Plays and discards refer to the slot in the hand of the player (where slot 1 is the newest card),
+// so the moves are simulated with the real rules of the game in order to find the card orders
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+var (
+	gameTextEndConditions = map[int]string{
+		EndConditionTimeout:     "timeout",
+		EndConditionTerminated:  "terminated",
+		EndConditionIdleTimeout: "idle timeout",
+	}
+
+	gameTextCardRegExp         = regexp.MustCompile(`^([a-z]+)(\d+)$`)
+	gameTextNumberedCardRegExp = regexp.MustCompile(`^(\d+):(\d+)$`)
+	gameTextEndGameRegExp      = regexp.MustCompile(`^ends the game \((.+)\)$`)
+)
+
+// ToText converts a game to the text notation
+func (gameJSON *GameJSON) ToText() (string, error) {
+	for _, name := range gameJSON.Players {
+		if name == "" || strings.IndexFunc(name, unicode.IsSpace) != -1 {
+			return "", errors.New("the player name \"" + name + "\" cannot be blank or contain " +
+				"whitespace")
+		}
+	}
+
+	var state *engine.State
+	if v, err := gameJSON.GetInitialState(); err != nil {
+		return "", err
+	} else {
+		state = v
+	}
+
+	lines := make([]string, 0)
+	if gameJSON.ID != 0 {
+		lines = append(lines, "id "+strconv.Itoa(gameJSON.ID))
+	}
+	if gameJSON.Seed != "" {
+		lines = append(lines, "seed "+gameJSON.Seed)
+	}
+	lines = append(lines, "players "+strings.Join(gameJSON.Players, " "))
+
+	if gameJSON.Options != nil {
+		if gameJSON.Options.Variant != nil {
+			lines = append(lines, "variant "+*gameJSON.Options.Variant)
+		}
+
+		// Every other option is written as a JSON value
+		// (so that there is nothing to update here when a new option is added)
+		optionsJSON := *gameJSON.Options
+		optionsJSON.Variant = nil
+		var optionsMap map[string]json.RawMessage
+		if v, err := json.Marshal(optionsJSON); err != nil {
+			return "", err
+		} else if err := json.Unmarshal(v, &optionsMap); err != nil {
+			return "", err
+		}
+		optionNames := make([]string, 0)
+		for optionName := range optionsMap {
+			optionNames = append(optionNames, optionName)
+		}
+		sort.Strings(optionNames)
+		for _, optionName := range optionNames {
+			lines = append(lines, "option "+optionName+" "+string(optionsMap[optionName]))
+		}
+	}
+
+	for i, characterAssignment := range gameJSON.Characters {
+		lines = append(lines, "character "+gameJSON.Players[i]+" "+
+			strconv.Itoa(characterAssignment.Metadata)+" "+characterAssignment.Name)
+	}
+
+	for i, playerNotes := range gameJSON.Notes {
+		for order, note := range playerNotes {
+			if note != "" {
+				lines = append(lines, "note "+gameJSON.Players[i]+" "+strconv.Itoa(order)+" "+
+					strconv.Quote(note))
+			}
+		}
+	}
+
+	cards := make([]string, 0)
+	for _, card := range gameJSON.Deck {
+		cards = append(cards, gameTextCardName(state.Variant, card))
+	}
+	lines = append(lines, "deck "+strings.Join(cards, " "))
+
+	for i, action := range gameJSON.Actions {
+		var line string
+		if v, err := gameTextActionLine(state, gameJSON, action); err != nil {
+			return "", errors.New("action " + strconv.Itoa(i) + ": " + err.Error())
+		} else {
+			line = v
+		}
+		lines = append(lines, line)
+
+		if v, _, err := state.Apply(action); err != nil {
+			return "", errors.New("action " + strconv.Itoa(i) + " is not legal: " + err.Error())
+		} else {
+			state = v
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func gameTextActionLine(
+	state *engine.State,
+	gameJSON *GameJSON,
+	action *GameAction,
+) (string, error) {
+	p := state.Players[state.ActivePlayerIndex]
+
+	switch action.Type {
+	case ActionTypePlay, ActionTypeDiscard:
+		verb := "plays"
+		if action.Type == ActionTypeDiscard {
+			verb = "discards"
+		}
+		if action.Value != 0 {
+			return "", errors.New("plays and discards cannot have a value")
+		}
+		if slot := p.GetCardSlot(action.Target); slot != -1 {
+			return p.Name + " " + verb + " slot " + strconv.Itoa(slot), nil
+		}
+		if action.Type == ActionTypePlay && state.Options.DeckPlays &&
+			action.Target == state.DeckIndex {
+
+			return p.Name + " plays the deck", nil
+		}
+		return "", errors.New("card " + strconv.Itoa(action.Target) + " is not in the hand of " +
+			p.Name)
+
+	case ActionTypeColorClue, ActionTypeRankClue:
+		if action.Target < 0 || action.Target >= len(gameJSON.Players) {
+			return "", errors.New("the clue target of " + strconv.Itoa(action.Target) +
+				" is not valid")
+		}
+		clue := strconv.Itoa(action.Value)
+		if action.Type == ActionTypeColorClue {
+			if action.Value < 0 || action.Value >= len(state.Variant.ClueColors) {
+				return "", errors.New("the color clue of " + strconv.Itoa(action.Value) +
+					" is not valid")
+			}
+			clue = strings.ToLower(state.Variant.ClueColors[action.Value])
+		}
+		return p.Name + " clues " + gameJSON.Players[action.Target] + " " + clue, nil
+
+	case ActionTypeEndGame:
+		if action.Target < 0 || action.Target >= len(gameJSON.Players) {
+			return "", errors.New("the end game target of " + strconv.Itoa(action.Target) +
+				" is not valid")
+		}
+		endCondition, ok := gameTextEndConditions[action.Value]
+		if !ok {
+			return "", errors.New("the end condition of " + strconv.Itoa(action.Value) +
+				" is not valid")
+		}
+		return gameJSON.Players[action.Target] + " ends the game (" + endCondition + ")", nil
+	}
+
+	return "", errors.New("the action type of " + strconv.Itoa(action.Type) + " is not valid")
+}
+
+// gameTextCardName returns e.g. "r1"
+// If two suits in the variant have the same abbreviation,
+// then the suit number is used instead (e.g. "3:1")
+func gameTextCardName(variant *Variant, card *CardIdentity) string {
+	if card.SuitIndex >= 0 && card.SuitIndex < len(variant.Suits) {
+		abbreviation := strings.ToLower(variant.Suits[card.SuitIndex].Abbreviation)
+		if gameTextCardRegExp.MatchString(abbreviation+"1") &&
+			len(gameTextSuitIndexes(variant, abbreviation)) == 1 {
+
+			return abbreviation + strconv.Itoa(card.Rank)
+		}
+	}
+
+	return strconv.Itoa(card.SuitIndex+1) + ":" + strconv.Itoa(card.Rank)
+}
+
+func gameTextSuitIndexes(variant *Variant, abbreviation string) []int {
+	suitIndexes := make([]int, 0)
+	for i, suit := range variant.Suits {
+		if strings.ToLower(suit.Abbreviation) == abbreviation {
+			suitIndexes = append(suitIndexes, i)
+		}
+	}
+
+	return suitIndexes
+}
+
+// parseGameText converts a game in the text notation to the JSON format
+func parseGameText(text string) (*GameJSON, error) {
+	gameJSON := &GameJSON{}
+	var variant *Variant
+	var state *engine.State
+	optionsMap := make(map[string]json.RawMessage)
+	characters := make(map[string]*CharacterAssignment)
+	notes := make(map[string]map[int]string)
+
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lineError := func(msg string) error {
+			return errors.New("line " + strconv.Itoa(i+1) + ": " + msg)
+		}
+
+		// Lines that start with the name of a player are moves
+		keyword, rest := splitGameTextLine(line)
+		if stringInSlice(keyword, gameJSON.Players) && isGameTextMove(rest) {
+			if state == nil {
+				// This is the first move, so all of the other lines must have already been specified
+				if v, err := parseGameTextFinish(
+					gameJSON,
+					optionsMap,
+					characters,
+					notes,
+				); err != nil {
+					return nil, lineError(err.Error())
+				} else {
+					state = v
+				}
+			}
+
+			var action *GameAction
+			if v, err := parseGameTextMove(state, gameJSON, keyword, rest); err != nil {
+				return nil, lineError(err.Error())
+			} else {
+				action = v
+			}
+			if v, _, err := state.Apply(action); err != nil {
+				return nil, lineError("that move is not legal: " + err.Error())
+			} else {
+				state = v
+			}
+			gameJSON.Actions = append(gameJSON.Actions, action)
+			continue
+		}
+
+		if state != nil {
+			return nil, lineError("\"" + keyword + "\" must come before the moves")
+		}
+
+		switch keyword {
+		case "id":
+			if v, err := strconv.Atoi(rest); err != nil {
+				return nil, lineError("\"" + rest + "\" is not a valid game ID")
+			} else {
+				gameJSON.ID = v
+			}
+
+		case "seed":
+			gameJSON.Seed = rest
+
+		case "players":
+			gameJSON.Players = strings.Fields(rest)
+
+		case "variant":
+			if len(gameJSON.Deck) > 0 {
+				return nil, lineError("the variant must come before the deck")
+			}
+			if v, ok := variants[rest]; !ok {
+				return nil, lineError("\"" + rest + "\" is not a valid variant")
+			} else {
+				variant = v
+			}
+			optionsMap["variant"] = json.RawMessage(strconv.Quote(rest))
+
+		case "option":
+			optionName, value := splitGameTextLine(rest)
+			if optionName == "variant" || !json.Valid([]byte(value)) {
+				return nil, lineError("\"" + rest + "\" is not a valid option")
+			}
+			optionsMap[optionName] = json.RawMessage(value)
+
+		case "character":
+			name, characterText := splitGameTextLine(rest)
+			metadataText, characterName := splitGameTextLine(characterText)
+			if !stringInSlice(name, gameJSON.Players) {
+				return nil, lineError("\"" + name + "\" is not one of the players")
+			}
+			if v, err := strconv.Atoi(metadataText); err != nil || characterName == "" {
+				return nil, lineError("the format of a character is: " +
+					"character [player] [metadata] [character name]")
+			} else {
+				characters[name] = &CharacterAssignment{
+					Name:     characterName,
+					Metadata: v,
+				}
+			}
+
+		case "note":
+			name, noteText := splitGameTextLine(rest)
+			orderText, quotedNote := splitGameTextLine(noteText)
+			if !stringInSlice(name, gameJSON.Players) {
+				return nil, lineError("\"" + name + "\" is not one of the players")
+			}
+			order, err := strconv.Atoi(orderText)
+			if err != nil || order < 0 {
+				return nil, lineError("\"" + orderText + "\" is not a valid card order")
+			}
+			note, err := strconv.Unquote(quotedNote)
+			if err != nil {
+				return nil, lineError("the note must be surrounded by double quotes")
+			}
+			if _, ok := notes[name]; !ok {
+				notes[name] = make(map[int]string)
+			}
+			notes[name][order] = note
+
+		case "deck":
+			if variant == nil {
+				variant = variants["No Variant"]
+			}
+			for _, cardName := range strings.Fields(rest) {
+				if v, err := parseGameTextCard(variant, cardName); err != nil {
+					return nil, lineError(err.Error())
+				} else {
+					gameJSON.Deck = append(gameJSON.Deck, v)
+				}
+			}
+
+		default:
+			return nil, lineError("\"" + keyword + "\" is not a valid keyword or player")
+		}
+	}
+
+	// Handle games with no moves
+	if state == nil {
+		if _, err := parseGameTextFinish(gameJSON, optionsMap, characters, notes); err != nil {
+			return nil, err
+		}
+	}
+
+	return gameJSON, nil
+}
+
+// parseGameTextFinish fills in the parts of the game that are specified on multiple lines and
+// returns the starting state of the game
+func parseGameTextFinish(
+	gameJSON *GameJSON,
+	optionsMap map[string]json.RawMessage,
+	characters map[string]*CharacterAssignment,
+	notes map[string]map[int]string,
+) (*engine.State, error) {
+	if len(gameJSON.Players) == 0 {
+		return nil, errors.New("the players must be specified")
+	}
+	if len(gameJSON.Deck) == 0 {
+		return nil, errors.New("the deck must be specified")
+	}
+
+	if len(optionsMap) > 0 {
+		if v, err := json.Marshal(optionsMap); err != nil {
+			return nil, err
+		} else if err := json.Unmarshal(v, &gameJSON.Options); err != nil {
+			return nil, errors.New("the options are not valid: " + err.Error())
+		}
+	}
+
+	if len(characters) > 0 {
+		for _, name := range gameJSON.Players {
+			characterAssignment, ok := characters[name]
+			if !ok {
+				return nil, errors.New("every player must have a character")
+			}
+			gameJSON.Characters = append(gameJSON.Characters, characterAssignment)
+		}
+	}
+
+	// Notes are stored as an array for each player that ends at their last note
+	if len(notes) > 0 {
+		for _, name := range gameJSON.Players {
+			playerNotes := make([]string, 0)
+			for order, note := range notes[name] {
+				for len(playerNotes) <= order {
+					playerNotes = append(playerNotes, "")
+				}
+				playerNotes[order] = note
+			}
+			gameJSON.Notes = append(gameJSON.Notes, playerNotes)
+		}
+	}
+
+	return gameJSON.GetInitialState()
+}
+
+func parseGameTextMove(
+	state *engine.State,
+	gameJSON *GameJSON,
+	name string,
+	move string,
+) (*GameAction, error) {
+	// The end of a game is not a move by the active player
+	if match := gameTextEndGameRegExp.FindStringSubmatch(move); match != nil {
+		for endCondition, endConditionText := range gameTextEndConditions {
+			if match[1] == endConditionText {
+				target := stringIndexInSlice(name, gameJSON.Players)
+				return engine.NewAction(ActionTypeEndGame, target, endCondition), nil
+			}
+		}
+		return nil, errors.New("\"" + match[1] + "\" is not a valid end condition")
+	}
+
+	p := state.Players[state.ActivePlayerIndex]
+	if name != p.Name {
+		return nil, errors.New("it is " + p.Name + "'s turn, not " + name + "'s turn")
+	}
+
+	verb, rest := splitGameTextLine(move)
+	switch verb {
+	case "plays", "discards":
+		actionType := ActionTypePlay
+		if verb == "discards" {
+			actionType = ActionTypeDiscard
+		}
+		if verb == "plays" && rest == "the deck" {
+			return engine.NewAction(actionType, state.DeckIndex, 0), nil
+		}
+		slotText := strings.TrimPrefix(rest, "slot ")
+		slot, err := strconv.Atoi(slotText)
+		if err != nil || slotText == rest || slot < 1 || slot > len(p.Hand) {
+			return nil, errors.New("\"" + rest + "\" is not a valid slot")
+		}
+		return engine.NewAction(actionType, p.Hand[len(p.Hand)-slot].Order, 0), nil
+
+	case "clues":
+		targetName, clue := splitGameTextLine(rest)
+		target := stringIndexInSlice(targetName, gameJSON.Players)
+		if target == -1 {
+			return nil, errors.New("\"" + targetName + "\" is not one of the players")
+		}
+		if rank, err := strconv.Atoi(clue); err == nil {
+			return engine.NewAction(ActionTypeRankClue, target, rank), nil
+		}
+		for i, color := range state.Variant.ClueColors {
+			if strings.EqualFold(clue, color) {
+				return engine.NewAction(ActionTypeColorClue, target, i), nil
+			}
+		}
+		return nil, errors.New("\"" + clue + "\" is not a valid clue in this variant")
+	}
+
+	return nil, errors.New("\"" + verb + "\" is not a valid move")
+}
+
+func parseGameTextCard(variant *Variant, cardName string) (*CardIdentity, error) {
+	if match := gameTextNumberedCardRegExp.FindStringSubmatch(cardName); match != nil {
+		suitNum, _ := strconv.Atoi(match[1])
+		rank, _ := strconv.Atoi(match[2])
+		return &CardIdentity{
+			SuitIndex: suitNum - 1,
+			Rank:      rank,
+		}, nil
+	}
+
+	if match := gameTextCardRegExp.FindStringSubmatch(strings.ToLower(cardName)); match != nil {
+		suitIndexes := gameTextSuitIndexes(variant, match[1])
+		if len(suitIndexes) == 1 {
+			rank, _ := strconv.Atoi(match[2])
+			return &CardIdentity{
+				SuitIndex: suitIndexes[0],
+				Rank:      rank,
+			}, nil
+		} else if len(suitIndexes) > 1 {
+			return nil, errors.New("\"" + cardName + "\" is ambiguous in this variant; " +
+				"use the suit number instead (e.g. \"1:" + match[2] + "\")")
+		}
+	}
+
+	return nil, errors.New("\"" + cardName + "\" is not a valid card in this variant")
+}
+
+// splitGameTextLine splits a line into the first word and the rest of the line
+func splitGameTextLine(line string) (string, string) {
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) == 1 {
+		return fields[0], ""
+	}
+
+	return fields[0], strings.TrimSpace(fields[1])
+}
+
+func isGameTextMove(move string) bool {
+	verb, _ := splitGameTextLine(move)
+	return verb == "plays" || verb == "discards" || verb == "clues" ||
+		gameTextEndGameRegExp.MatchString(move)
+}
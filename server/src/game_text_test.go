@@ -0,0 +1,296 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// playTestGameJSON fills in the deck of a game from the seed and then plays it
+// with an arbitrary (but deterministic) choice of legal actions
+// If "numMoves" is -1, the game is played until it ends; otherwise,
+// the game is ended with the provided end condition after that many moves
+func playTestGameJSON(t *testing.T, gameJSON *GameJSON, numMoves int, endCondition int) {
+	gameJSON.Deck = getDeckFromSeed(
+		variants[*gameJSON.Options.Variant],
+		gameJSON.GetOptions(),
+		gameJSON.Seed,
+	)
+
+	var state *engine.State
+	if v, err := gameJSON.GetInitialState(); err != nil {
+		t.Fatalf("%s: failed to get the initial state: %v", gameJSON.Seed, err)
+	} else {
+		state = v
+	}
+
+	gameJSON.Actions = make([]*GameAction, 0)
+	for state.EndCondition == EndConditionInProgress {
+		if len(gameJSON.Actions) == numMoves {
+			gameJSON.Actions = append(
+				gameJSON.Actions,
+				engine.NewAction(ActionTypeEndGame, state.ActivePlayerIndex, endCondition),
+			)
+			return
+		}
+
+		legalActions := state.LegalActions(state.ActivePlayerIndex)
+		if len(legalActions) == 0 {
+			t.Fatalf("%s: there are no legal actions on turn %d", gameJSON.Seed, state.Turn)
+		}
+
+		// Avoid misplays, so that the games last long enough to get to the end of the deck
+		// (and always play the deck when it is possible, so that the games with deck plays use it)
+		var a *GameAction
+		var nextState *engine.State
+		for i := range legalActions {
+			legalAction := legalActions[(state.Turn*7+i)%len(legalActions)]
+			a2 := engine.NewAction(legalAction.Type, legalAction.Target, legalAction.Value)
+			var state2 *engine.State
+			if v, _, err := state.Apply(a2); err != nil {
+				t.Fatalf("%s: failed to apply a legal action on turn %d: %v", gameJSON.Seed,
+					state.Turn, err)
+			} else {
+				state2 = v
+			}
+
+			isDeckPlay := a2.Type == ActionTypePlay && a2.Target == state.DeckIndex
+			if a == nil || isDeckPlay ||
+				(nextState.Strikes > state.Strikes && state2.Strikes == state.Strikes) {
+
+				a = a2
+				nextState = state2
+			}
+			if isDeckPlay {
+				break
+			}
+		}
+		state = nextState
+		gameJSON.Actions = append(gameJSON.Actions, a)
+	}
+}
+
+// Converting a game to the text notation and back must not lose any information
+func TestGameTextRoundTrip(t *testing.T) {
+	noVariant := "No Variant"
+	rainbow := "Rainbow (6 Suits)"
+	// Blue and Black both have an abbreviation of "B" in this variant
+	black := "Black (5 Suits)"
+	yes := true
+	startingClueTokens := 6
+	startingPlayer := 1
+
+	testCases := []struct {
+		description string
+		gameJSON    *GameJSON
+		// The number of moves to make before the game is ended, or -1 to play the whole game
+		numMoves     int
+		endCondition int
+		// Lines (or parts of lines) that must appear in the text
+		expectedText []string
+	}{
+		{
+			"a game with no extra information",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant: &noVariant,
+				},
+				Seed: "p2v0s1",
+			},
+			-1,
+			EndConditionInProgress,
+			[]string{"players Alice Bob\n", "variant No Variant\n", " clues "},
+		},
+		{
+			"a game with notes",
+			&GameJSON{ // nolint: exhaustivestruct
+				ID:      15103,
+				Players: []string{"Alice", "Bob", "Cathy"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant: &noVariant,
+				},
+				Notes: [][]string{
+					{"", "", "chop moved"},
+					{},
+					{"\"quoted\" note", "", "", "", "f"},
+				},
+				Seed: "p3v0s2",
+			},
+			-1,
+			EndConditionInProgress,
+			[]string{
+				"id 15103\n",
+				"note Alice 2 \"chop moved\"\n",
+				"note Cathy 0 \"\\\"quoted\\\" note\"\n",
+				"note Cathy 4 \"f\"\n",
+			},
+		},
+		{
+			"a game with characters",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob", "Cathy"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant:               &noVariant,
+					DetrimentalCharacters: &yes,
+				},
+				Characters: []*CharacterAssignment{
+					{Name: "Fuming", Metadata: 2},
+					{Name: "Dumbfounded", Metadata: 4},
+					{Name: "Panicky", Metadata: -1},
+				},
+				Seed: "p3v0s3",
+			},
+			-1,
+			EndConditionInProgress,
+			[]string{
+				"option detrimentalCharacters true\n",
+				"character Alice 2 Fuming\n",
+				"character Bob 4 Dumbfounded\n",
+				"character Cathy -1 Panicky\n",
+			},
+		},
+		{
+			"a game with options",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob", "Cathy", "Donald"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					StartingPlayer:     &startingPlayer,
+					Variant:            &rainbow,
+					CardCycle:          &yes,
+					StartingClueTokens: &startingClueTokens,
+				},
+				Seed: "p4v16s4",
+			},
+			-1,
+			EndConditionInProgress,
+			[]string{
+				"variant Rainbow (6 Suits)\n",
+				"option cardCycle true\n",
+				"option startingClueTokens 6\n",
+				"option startingPlayer 1\n",
+			},
+		},
+		{
+			"a game with deck plays",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant:   &noVariant,
+					DeckPlays: &yes,
+				},
+				Seed: "p2v0s5",
+			},
+			-1,
+			EndConditionInProgress,
+			[]string{"option deckPlays true\n", " plays the deck\n"},
+		},
+		{
+			"a game that was terminated",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant: &noVariant,
+				},
+				Seed: "p2v0s6",
+			},
+			5,
+			EndConditionTerminated,
+			[]string{"Bob ends the game (terminated)\n"},
+		},
+		{
+			"a game that timed out",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob", "Cathy"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant: &noVariant,
+				},
+				Seed: "p3v0s7",
+			},
+			10,
+			EndConditionTimeout,
+			[]string{"Bob ends the game (timeout)\n"},
+		},
+		{
+			"a game with two suits that have the same abbreviation",
+			&GameJSON{ // nolint: exhaustivestruct
+				Players: []string{"Alice", "Bob"},
+				Options: &OptionsJSON{ // nolint: exhaustivestruct
+					Variant: &black,
+				},
+				Seed: "p2v21s8",
+			},
+			-1,
+			EndConditionInProgress,
+			[]string{" 4:", " 5:"},
+		},
+	}
+
+	for _, tc := range testCases {
+		if _, ok := variants[*tc.gameJSON.Options.Variant]; !ok {
+			t.Fatalf("%s: the variant of \"%s\" does not exist", tc.description,
+				*tc.gameJSON.Options.Variant)
+		}
+		playTestGameJSON(t, tc.gameJSON, tc.numMoves, tc.endCondition)
+
+		var text string
+		if v, err := tc.gameJSON.ToText(); err != nil {
+			t.Fatalf("%s: failed to convert the game to text: %v", tc.description, err)
+		} else {
+			text = v
+		}
+
+		for _, expectedText := range tc.expectedText {
+			if !strings.Contains(text, expectedText) {
+				t.Errorf("%s: the text does not contain \"%s\":\n%s", tc.description, expectedText,
+					text)
+			}
+		}
+
+		var gameJSON *GameJSON
+		if v, err := parseGameText(text); err != nil {
+			t.Fatalf("%s: failed to parse the text: %v\n%s", tc.description, err, text)
+		} else {
+			gameJSON = v
+		}
+
+		if !reflect.DeepEqual(tc.gameJSON, gameJSON) {
+			t.Errorf("%s: the parsed game is different from the original game:\n%s",
+				tc.description, text)
+		}
+	}
+}
+
+func TestParseGameTextErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		text        string
+	}{
+		{"no players", "deck r1 r2\n"},
+		{"no deck", "players Alice Bob\n"},
+		{"an invalid variant", "players Alice Bob\nvariant Foo\ndeck r1\n"},
+		{"an invalid card", "players Alice Bob\ndeck r1 x9\n"},
+		{
+			"an ambiguous card",
+			"players Alice Bob\nvariant Black (5 Suits)\ndeck b1\n",
+		},
+		{"an invalid keyword", "players Alice Bob\nfoo bar\ndeck r1\n"},
+		{
+			"a move out of turn",
+			"players Alice Bob\ndeck r1 r1 r1 r2 r2 r2 r3 r3 r3 r4 r4 r5\nBob plays slot 1\n",
+		},
+		{
+			"a keyword after the moves",
+			"players Alice Bob\ndeck r1 r1 r1 r2 r2 r2 r3 r3 r3 r4 r4 r5\n" +
+				"Alice plays slot 1\nid 5\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		if _, err := parseGameText(tc.text); err == nil {
+			t.Errorf("parsing a game with %s did not return an error", tc.description)
+		}
+	}
+}
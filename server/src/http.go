@@ -33,6 +33,11 @@ type TemplateData struct {
 	SpecificSeed bool
 	Tags         map[int][]string
 
+	// Seed
+	// The best score that is achievable on the seed (-1 if the solver has not been run on it yet)
+	MaxAchievableScore  int
+	MaxAchievableProven bool
+
 	// Scores
 	DateJoined                 string
 	NumGames                   int
@@ -60,6 +65,11 @@ type TemplateData struct {
 	NumStrikeouts int
 	StrikeoutRate string
 	RecentGames   []*GameHistory
+
+	// Puzzles
+	Puzzles        []*Puzzle
+	PuzzleProgress map[int]*UserPuzzle
+	LoggedIn       bool
 }
 
 const (
@@ -223,22 +233,12 @@ func httpInit() {
 	httpRouter.GET("/profile", httpScores) // "/profile" is an alias for "/scores"
 	httpRouter.GET("/profile/:player1", httpScores)
 	httpRouter.GET("/history", httpHistory)
-	httpRouter.GET("/history/:player1", httpHistory)
-	httpRouter.GET("/history/:player1/:player2", httpHistory)
-	httpRouter.GET("/history/:player1/:player2/:player3", httpHistory)
-	httpRouter.GET("/history/:player1/:player2/:player3/:player4", httpHistory)
-	httpRouter.GET("/history/:player1/:player2/:player3/:player4/:player5", httpHistory)
-	httpRouter.GET("/history/:player1/:player2/:player3/:player4/:player5/:player6", httpHistory)
+	httpRouter.GET("/history/*players", httpHistory) // e.g. "/history/Alice/Bob/Cathy"
 	httpRouter.GET("/missing-scores", httpMissingScores)
 	httpRouter.GET("/missing-scores/:player1", httpMissingScores)
 	httpRouter.GET("/missing-scores/:player1/:numPlayers", httpMissingScores)
 	httpRouter.GET("/shared-missing-scores", httpSharedMissingScores)
-	httpRouter.GET("/shared-missing-scores/:player1", httpSharedMissingScores)
-	httpRouter.GET("/shared-missing-scores/:player1/:player2", httpSharedMissingScores)
-	httpRouter.GET("/shared-missing-scores/:player1/:player2/:player3", httpSharedMissingScores)
-	httpRouter.GET("/shared-missing-scores/:player1/:player2/:player3/:player4", httpSharedMissingScores)
-	httpRouter.GET("/shared-missing-scores/:player1/:player2/:player3/:player4/:player5", httpSharedMissingScores)
-	httpRouter.GET("/shared-missing-scores/:player1/:player2/:player3/:player4/:player5/:player6", httpSharedMissingScores)
+	httpRouter.GET("/shared-missing-scores/*players", httpSharedMissingScores)
 	httpRouter.GET("/tags", httpTags)
 	httpRouter.GET("/tags/:player1", httpTags)
 	httpRouter.GET("/seed", httpSeed)
@@ -249,12 +249,16 @@ func httpInit() {
 	httpRouter.GET("/tag", httpTag)
 	httpRouter.GET("/tag/:tag", httpTag)
 	httpRouter.GET("/videos", httpVideos)
+	httpRouter.GET("/puzzles", httpPuzzles)
 	httpRouter.GET("/password-reset", httpPasswordReset)
 	httpRouter.POST("/password-reset", httpPasswordResetPost)
 
 	// Path handlers for bots, developers, researchers, etc.
 	httpRouter.GET("/export", httpExport)
 	httpRouter.GET("/export/:databaseID", httpExport)
+	httpRouter.GET("/export/:databaseID/analysis", httpExportAnalysis)
+	httpRouter.GET("/export-bulk", httpExportBulk)
+	httpRouter.GET("/solve/:databaseID", httpSolve)
 
 	// Other
 	httpRouter.Static("/public", path.Join(projectPath, "public"))
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/Zamiell/hanabi-live/engine"
 	"github.com/gin-gonic/gin"
 )
 
@@ -42,17 +44,62 @@ func httpExport(c *gin.Context) {
 		return
 	}
 
-	// Get the players from the database
-	var dbPlayers []*DBPlayer
-	if v, err := models.Games.GetPlayers(databaseID); err != nil {
-		logger.Error("Failed to get the players from the database for game " +
-			strconv.Itoa(databaseID) + ": " + err.Error())
+	var gameJSON *GameJSON
+	if v, err := getGameJSON(databaseID); err != nil {
+		logger.Error("Failed to export game " + strconv.Itoa(databaseID) + ": " + err.Error())
 		http.Error(
 			w,
 			http.StatusText(http.StatusInternalServerError),
 			http.StatusInternalServerError,
 		)
 		return
+	} else {
+		gameJSON = v
+	}
+
+	// Include the annotations from the shared replays of the game
+	if v, err := models.ReplayAnnotations.GetAll(databaseID); err != nil {
+		logger.Error("Failed to get the replay annotations for game " + strconv.Itoa(databaseID) +
+			": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if len(v) > 0 {
+		gameJSON.Annotations = v
+	}
+
+	// The game can also be exported in the text notation (e.g. "/export/15103?format=text")
+	if c.Query("format") == "text" {
+		var gameText string
+		if v, err := gameJSON.ToText(); err != nil {
+			logger.Error("Failed to convert game " + strconv.Itoa(databaseID) +
+				" to the text notation: " + err.Error())
+			http.Error(
+				w,
+				http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError,
+			)
+			return
+		} else {
+			gameText = v
+		}
+		c.String(http.StatusOK, gameText)
+		return
+	}
+
+	c.JSON(http.StatusOK, gameJSON)
+}
+
+// getGameJSON recreates a game from the database in the JSON format that is used for replays
+// It is assumed that the game exists
+func getGameJSON(databaseID int) (*GameJSON, error) {
+	// Get the players from the database
+	var dbPlayers []*DBPlayer
+	if v, err := models.Games.GetPlayers(databaseID); err != nil {
+		return nil, errors.New("failed to get the players: " + err.Error())
 	} else {
 		dbPlayers = v
 	}
@@ -66,14 +113,7 @@ func httpExport(c *gin.Context) {
 	// Get the options from the database
 	var options *Options
 	if v, err := models.Games.GetOptions(databaseID); err != nil {
-		logger.Error("Failed to get the options from the database for game " +
-			strconv.Itoa(databaseID) + ": " + err.Error())
-		http.Error(
-			w,
-			http.StatusText(http.StatusInternalServerError),
-			http.StatusInternalServerError,
-		)
-		return
+		return nil, errors.New("failed to get the options: " + err.Error())
 	} else {
 		options = v
 	}
@@ -83,56 +123,27 @@ func httpExport(c *gin.Context) {
 	// Get the seed from the database
 	var seed string
 	if v, err := models.Games.GetSeed(databaseID); err != nil {
-		logger.Error("Failed to get the seed for game " +
-			"\"" + strconv.Itoa(databaseID) + "\": " + err.Error())
-		http.Error(
-			w,
-			http.StatusText(http.StatusInternalServerError),
-			http.StatusInternalServerError,
-		)
-		return
+		return nil, errors.New("failed to get the seed: " + err.Error())
 	} else {
 		seed = v
 	}
 
-	// Make a deck and shuffle it
-	g := &Game{ // nolint: exhaustivestruct
-		Options:      options,
-		ExtraOptions: &ExtraOptions{},
-		Seed:         seed,
-	}
-	g.InitDeck()
-	setSeed(g.Seed) // Seed the random number generator
-	g.ShuffleDeck()
+	variant := variants[options.VariantName]
+	deck := getDeckFromSeed(variant, options, seed)
 
 	// Get the actions from the database
 	var actions []*GameAction
 	if v, err := models.GameActions.GetAll(databaseID); err != nil {
-		logger.Error("Failed to get the actions from the database for game " +
-			strconv.Itoa(databaseID) + ": " + err.Error())
-		http.Error(
-			w,
-			http.StatusText(http.StatusInternalServerError),
-			http.StatusInternalServerError,
-		)
-		return
+		return nil, errors.New("failed to get the actions: " + err.Error())
 	} else {
 		actions = v
 	}
 
 	// Get the notes from the database
-	variant := variants[g.Options.VariantName]
 	noteSize := variant.GetDeckSize() + len(variant.Suits)
 	var notes [][]string
 	if v, err := models.Games.GetNotes(databaseID, len(dbPlayers), noteSize); err != nil {
-		logger.Error("Failed to get the notes from the database for game " +
-			strconv.Itoa(databaseID) + ": " + err.Error())
-		http.Error(
-			w,
-			http.StatusText(http.StatusInternalServerError),
-			http.StatusInternalServerError,
-		)
-		return
+		return nil, errors.New("failed to get the notes: " + err.Error())
 	} else {
 		notes = v
 	}
@@ -176,6 +187,20 @@ func httpExport(c *gin.Context) {
 		optionsJSON.Timed = &options.Timed
 		optionsJSON.TimeBase = &options.TimeBase
 		optionsJSON.TimePerTurn = &options.TimePerTurn
+		if options.TimeControl != TimeControlFischer {
+			optionsJSON.TimeControl = &options.TimeControl
+		}
+		if options.ByoYomiPeriods != 0 {
+			optionsJSON.ByoYomiPeriods = &options.ByoYomiPeriods
+		}
+		if options.TeamClock {
+			optionsJSON.TeamClock = &options.TeamClock
+		}
+		allDefaultOptions = false
+	}
+	if options.Correspondence {
+		optionsJSON.Correspondence = &options.Correspondence
+		optionsJSON.CorrespondenceDeadline = &options.CorrespondenceDeadline
 		allDefaultOptions = false
 	}
 	if options.Speedrun {
@@ -210,6 +235,22 @@ func httpExport(c *gin.Context) {
 		optionsJSON.DetrimentalCharacters = &options.DetrimentalCharacters
 		allDefaultOptions = false
 	}
+	if options.StartingClueTokens != 0 {
+		optionsJSON.StartingClueTokens = &options.StartingClueTokens
+		allDefaultOptions = false
+	}
+	if options.MaxClueTokens != 0 {
+		optionsJSON.MaxClueTokens = &options.MaxClueTokens
+		allDefaultOptions = false
+	}
+	if options.StrikeLimit != 0 {
+		optionsJSON.StrikeLimit = &options.StrikeLimit
+		allDefaultOptions = false
+	}
+	if options.HandSize != 0 {
+		optionsJSON.HandSize = &options.HandSize
+		allDefaultOptions = false
+	}
 	if allDefaultOptions {
 		optionsJSON = nil
 	}
@@ -218,7 +259,7 @@ func httpExport(c *gin.Context) {
 	gameJSON := &GameJSON{
 		ID:         databaseID,
 		Players:    playerNames,
-		Deck:       g.CardIdentities,
+		Deck:       deck,
 		Actions:    actions,
 		Options:    optionsJSON,
 		Notes:      notes,
@@ -226,5 +267,14 @@ func httpExport(c *gin.Context) {
 		Seed:       seed,
 	}
 
-	c.JSON(http.StatusOK, gameJSON)
+	return gameJSON, nil
+}
+
+// getDeckFromSeed makes a deck and shuffles it
+// (this must be done in exactly the same way as in the "tableStart()" function)
+func getDeckFromSeed(variant *Variant, options *Options, seed string) []*CardIdentity {
+	state := engine.NewState(variant, options.EngineOptions())
+	state.InitDeck(nil)
+	state.ShuffleDeck(newSeededRand(seed).Intn)
+	return state.CardIdentities
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zamiell/hanabi-live/engine"
+	"github.com/gin-gonic/gin"
+)
+
+// httpExportAnalysis returns the statistics for a game as JSON (e.g. "/export/15103/analysis")
+// Games that were played before analyses were recorded are analyzed on the fly
+// (without any times, since those are not stored in the database)
+func httpExportAnalysis(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	// Parse the game ID from the URL
+	databaseIDString := c.Param("databaseID")
+	if databaseIDString == "" {
+		http.Error(w, "Error: You must specify a database game ID.", http.StatusNotFound)
+		return
+	}
+
+	// Validate that it is a number
+	var databaseID int
+	if v, err := strconv.Atoi(databaseIDString); err != nil {
+		http.Error(w, "Error: That is not a valid database game ID.", http.StatusBadRequest)
+		return
+	} else {
+		databaseID = v
+	}
+
+	// Check to see if the game exists in the database
+	if exists, err := models.Games.Exists(databaseID); err != nil {
+		logger.Error("Failed to check to see if game " + strconv.Itoa(databaseID) + " exists: " +
+			err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if !exists {
+		http.Error(w, "Error: That game does not exist in the database.", http.StatusNotFound)
+		return
+	}
+
+	// Use the analysis that was generated at the end of the game, if there is one
+	if analysisJSON, exists, err := models.GameAnalyses.Get(databaseID); err != nil {
+		logger.Error("Failed to get the analysis for game " + strconv.Itoa(databaseID) + ": " +
+			err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if exists {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", analysisJSON)
+		return
+	}
+
+	var analysis *GameAnalysis
+	if v, err := getGameAnalysis(databaseID); err != nil {
+		logger.Error("Failed to analyze game " + strconv.Itoa(databaseID) + ": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		analysis = v
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// getGameAnalysis analyzes a game from the database
+// It is assumed that the game exists
+func getGameAnalysis(databaseID int) (*GameAnalysis, error) {
+	var gameJSON *GameJSON
+	if v, err := getGameJSON(databaseID); err != nil {
+		return nil, err
+	} else {
+		gameJSON = v
+	}
+
+	var initialState *engine.State
+	if v, err := gameJSON.GetInitialState(); err != nil {
+		return nil, err
+	} else {
+		initialState = v
+	}
+
+	return analyzeGame(initialState, gameJSON.Players, gameJSON.Actions, nil)
+}
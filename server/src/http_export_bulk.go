@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpExportBulk streams every game that matches the filter as a gzip archive of newline-delimited
+// JSON (see "export_bulk.go" for the filter parameters)
+// e.g. "/export-bulk?variant=No Variant&players=3&after=15103&limit=1000"
+// The ID of the last game that was sent is returned in the "X-Export-After" trailer,
+// so that the client can pass it as the "after" parameter to get the next set of games
+func httpExportBulk(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	var filter *GameExportFilter
+	if v, err := parseGameExportFilter(c.Request.URL.Query()); err != nil {
+		http.Error(w, "Error: "+err.Error(), http.StatusBadRequest)
+		return
+	} else {
+		filter = v
+	}
+
+	after := 0
+	if afterString := c.Query("after"); afterString != "" {
+		if v, err := strconv.Atoi(afterString); err != nil || v < 0 {
+			http.Error(w, "Error: That is not a valid game ID to start after.", http.StatusBadRequest)
+			return
+		} else {
+			after = v
+		}
+	}
+
+	limit := MaxBulkExportGames
+	if limitString := c.Query("limit"); limitString != "" {
+		if v, err := strconv.Atoi(limitString); err != nil || v < 1 || v > MaxBulkExportGames {
+			http.Error(
+				w,
+				"Error: The limit must be between 1 and "+strconv.Itoa(MaxBulkExportGames)+".",
+				http.StatusBadRequest,
+			)
+			return
+		} else {
+			limit = v
+		}
+	}
+
+	// Only allow a few exports at the same time, since each one can read many games
+	select {
+	case bulkExportSlots <- struct{}{}:
+		defer func() { <-bulkExportSlots }()
+	default:
+		http.Error(
+			w,
+			"Error: Too many games are being exported right now. Please try again later.",
+			http.StatusTooManyRequests,
+		)
+		return
+	}
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", "attachment; filename=\"games.ndjson.gz\"")
+	c.Header("Trailer", "X-Export-After") // The value is not known until every game is written
+	c.Status(http.StatusOK)
+
+	// Since the response has already started, we cannot send an error page if something goes wrong
+	// The archive will be truncated and the client can continue from the last game that they got
+	_, cursor, err := writeGameExport(w, filter, after, limit)
+	if err != nil {
+		logger.Error("Failed to write the bulk export: " + err.Error())
+	}
+	w.Header().Set("X-Export-After", strconv.Itoa(cursor))
+}
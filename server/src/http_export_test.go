@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// startTestGame starts a game with the "tableStart()" function
+// The game is started in the same way as a replay of a game from the database
+// (the deck is shuffled with the seed and the players keep the order that they were provided in)
+func startTestGame(t *testing.T, variantName string, playerNames []string, seed string) *Table {
+	if _, ok := variants[variantName]; !ok {
+		t.Fatalf("the variant of \"%s\" does not exist", variantName)
+	}
+
+	table := NewTable("Test game", 1)
+	table.Options.VariantName = variantName
+	table.ExtraOptions.CustomSeed = seed
+	table.ExtraOptions.NoWriteToDatabase = true
+	for i, name := range playerNames {
+		table.Players = append(table.Players, &Player{ // nolint: exhaustivestruct
+			UserID:  i + 1,
+			Name:    name,
+			Session: NewFakeSession(i+1, name),
+			Present: true,
+		})
+	}
+
+	d := &CommandData{} // nolint: exhaustivestruct
+	tableStart(context.Background(), table.Players[0].Session, d, table)
+	if !table.Running || table.Game.InitialState == nil {
+		t.Fatalf("%s: the game did not start", seed)
+	}
+
+	return table
+}
+
+// dealTestGame starts a game with the "tableStart()" function and then plays it
+// It returns the state right after the deal, the final state, and the actions that were taken
+func dealTestGame(
+	t *testing.T,
+	variantName string,
+	playerNames []string,
+	seed string,
+) (*engine.State, *engine.State, []*GameAction) {
+	initialState := startTestGame(t, variantName, playerNames, seed).Game.InitialState
+	state := initialState.Clone()
+
+	// Play through the game with an arbitrary (but deterministic) choice of legal actions
+	actions := make([]*GameAction, 0)
+	for state.EndCondition == EndConditionInProgress {
+		legalActions := state.LegalActions(state.ActivePlayerIndex)
+		if len(legalActions) == 0 {
+			t.Fatalf("there are no legal actions on turn %d", state.Turn)
+		}
+		legalAction := legalActions[(state.Turn*7)%len(legalActions)]
+		a := engine.NewAction(legalAction.Type, legalAction.Target, legalAction.Value)
+		if v, _, err := state.Apply(a); err != nil {
+			t.Fatalf("failed to apply a legal action on turn %d: %v", state.Turn, err)
+		} else {
+			state = v
+		}
+		actions = append(actions, a)
+	}
+
+	return initialState, state, actions
+}
+
+func assertSameState(
+	t *testing.T,
+	description string,
+	expected *engine.State,
+	actual *engine.State,
+) {
+	if !reflect.DeepEqual(expected.CardIdentities, actual.CardIdentities) {
+		t.Errorf("%s: the deck is different", description)
+	}
+	if !reflect.DeepEqual(expected.Deck, actual.Deck) {
+		t.Errorf("%s: the cards are different", description)
+	}
+	if !reflect.DeepEqual(expected.Players, actual.Players) {
+		t.Errorf("%s: the hands are different", description)
+	}
+	if !reflect.DeepEqual(expected.Stacks, actual.Stacks) {
+		t.Errorf("%s: the play stacks are different", description)
+	}
+	if expected.Turn != actual.Turn ||
+		expected.Score != actual.Score ||
+		expected.Strikes != actual.Strikes ||
+		expected.ClueTokens != actual.ClueTokens ||
+		expected.EndCondition != actual.EndCondition {
+
+		t.Errorf("%s: the game status is different", description)
+	}
+}
+
+// The deck is not stored in the database, so "httpExport()" must rebuild exactly the deck that
+// was played from the seed, or else the exported actions will not match the cards
+func TestGetDeckFromSeedMatchesDealtDeck(t *testing.T) {
+	testCases := []struct {
+		variantName string
+		playerNames []string
+		seed        string
+	}{
+		{"No Variant", []string{"Alice", "Bob"}, "p2v0s1"},
+		{"No Variant", []string{"Alice", "Bob", "Cathy", "Donald", "Emily"}, "p5v0s12"},
+		{"Rainbow (6 Suits)", []string{"Alice", "Bob", "Cathy"}, "p3v16s3"},
+	}
+
+	for _, tc := range testCases {
+		initialState, finalState, actions := dealTestGame(
+			t,
+			tc.variantName,
+			tc.playerNames,
+			tc.seed,
+		)
+
+		// Export the game (in the same way as the "getGameJSON()" function)
+		options := &Options{ // nolint: exhaustivestruct
+			NumPlayers:  len(tc.playerNames),
+			VariantName: tc.variantName,
+		}
+		variantName := tc.variantName
+		gameJSON := &GameJSON{ // nolint: exhaustivestruct
+			Players: tc.playerNames,
+			Deck:    getDeckFromSeed(variants[tc.variantName], options, tc.seed),
+			Actions: actions,
+			Options: &OptionsJSON{ // nolint: exhaustivestruct
+				Variant: &variantName,
+			},
+			Seed: tc.seed,
+		}
+
+		// Reconstruct the game from the export (in the same way as a replay)
+		var state *engine.State
+		if v, err := gameJSON.GetInitialState(); err != nil {
+			t.Fatalf("%s: failed to get the initial state: %v", tc.seed, err)
+		} else {
+			state = v
+		}
+		assertSameState(t, tc.seed+" (after the deal)", initialState, state)
+
+		for i, a := range gameJSON.Actions {
+			if v, _, err := state.Apply(a); err != nil {
+				t.Fatalf("%s: failed to replay action %d: %v", tc.seed, i, err)
+			} else {
+				state = v
+			}
+		}
+		assertSameState(t, tc.seed+" (at the end of the game)", finalState, state)
+	}
+}
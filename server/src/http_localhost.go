@@ -30,22 +30,35 @@ func httpLocalhostInit() {
 
 	// Create a new Gin HTTP router
 	httpRouter := gin.Default() // Has the "Logger" and "Recovery" middleware attached
+	httpRouter.Use(httpLocalhostAuditLog)
 
 	// Path handlers
-	httpRouter.POST("/ban", httpLocalhostUserAction)
+	httpRouter.GET("/adminActions", httpLocalhostAdminActions)
+	httpRouter.POST("/ban", httpLocalhostSanction)
+	httpRouter.POST("/bot", httpLocalhostUserAction)
 	httpRouter.GET("/cancel", httpLocalhostCancel)
 	httpRouter.GET("/clearEmptyTables", httpLocalhostClearEmptyTables)
 	httpRouter.GET("/debugFunction", httpLocalhostDebugFunction)
 	httpRouter.GET("/getLongTables", httpLocalhostGetLongTables)
 	httpRouter.GET("/maintenance", httpLocalhostMaintenance)
-	httpRouter.POST("/mute", httpLocalhostUserAction)
+	httpRouter.POST("/mute", httpLocalhostSanction)
 	httpRouter.GET("/print", httpLocalhostPrint)
+	httpRouter.POST("/puzzle", httpLocalhostPuzzle)
+	httpRouter.GET("/reports", httpLocalhostReports)
+	httpRouter.POST("/reportClaim", httpLocalhostReportClaim)
+	httpRouter.POST("/reportResolve", httpLocalhostReportResolve)
 	httpRouter.GET("/gracefulRestart", httpLocalhostGracefulRestart)
+	httpRouter.GET("/sanctions", httpLocalhostSanctions)
 	httpRouter.GET("/saveTables", httpLocalhostSaveTables)
 	httpRouter.POST("/sendWarning", httpLocalhostUserAction)
 	httpRouter.POST("/sendError", httpLocalhostUserAction)
+	httpRouter.POST("/unban", httpLocalhostLiftSanction)
+	httpRouter.POST("/unbot", httpLocalhostUserAction)
+	httpRouter.POST("/unmute", httpLocalhostLiftSanction)
+	httpRouter.POST("/unthrottle", httpLocalhostUnthrottle)
 	httpRouter.GET("/shutdown", httpLocalhostShutdown)
 	httpRouter.GET("/terminate", httpLocalhostTerminate)
+	httpRouter.POST("/throttle", httpLocalhostThrottle)
 	httpRouter.GET("/timeLeft", httpLocalhostTimeLeft)
 	httpRouter.GET("/uptime", httpLocalhostUptime)
 	httpRouter.GET("/version", httpLocalhostVersion)
@@ -88,26 +101,17 @@ func httpLocalhostUserAction(c *gin.Context) {
 		)
 		return
 	} else if !exists {
-		c.String(http.StatusOK, "User \""+username+"\" does not exist in the database.\n")
+		c.String(http.StatusNotFound, "User \""+username+"\" does not exist in the database.\n")
 		return
 	} else {
 		userID = v.ID
 	}
 
-	// Get the IP for this user
-	var lastIP string
-	if v, err := models.Users.GetLastIP(username); err != nil {
-		logger.Error("Failed to get the last IP for \"" + username + "\": " + err.Error())
-		return
-	} else {
-		lastIP = v
-	}
-
 	path := c.Request.URL.Path
-	if path == "/ban" {
-		httpLocalhostBan(c, username, lastIP, userID)
-	} else if path == "/mute" {
-		httpLocalhostMute(c, username, lastIP, userID)
+	if path == "/bot" {
+		httpLocalhostBot(c, username, userID, true)
+	} else if path == "/unbot" {
+		httpLocalhostBot(c, username, userID, false)
 	} else if path == "/sendWarning" {
 		httpLocalhostSendWarning(c, userID)
 	} else if path == "/sendError" {
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// The admin scripts send the name of the person who is running them in this header
+	// (see "admin/common.sh")
+	AdminHeader = "X-Admin"
+
+	DefaultAdminActionsCount = 50
+)
+
+var (
+	// Endpoints that only show information are not recorded in the audit log
+	httpLocalhostReadOnlyPaths = map[string]struct{}{
+		"/adminActions":  {},
+		"/getLongTables": {},
+		"/print":         {},
+		"/reports":       {},
+		"/sanctions":     {},
+		"/timeLeft":      {},
+		"/uptime":        {},
+		"/version":       {},
+	}
+
+	// Endpoints that might end the server before the handler returns
+	httpLocalhostServerEndingPaths = map[string]struct{}{
+		"/gracefulRestart": {},
+		"/shutdown":        {},
+	}
+)
+
+// httpLocalhostAuditLog is middleware that records every administrative action
+// in the "admin_actions" table
+// The action is recorded after it is performed, along with the status code of the response
+// (so that rejected requests can be told apart from the actions that actually happened)
+// The exception is the actions that might end the server,
+// which are recorded beforehand without a status code
+func httpLocalhostAuditLog(c *gin.Context) {
+	path := c.Request.URL.Path
+	if _, ok := httpLocalhostReadOnlyPaths[path]; ok {
+		c.Next()
+		return
+	}
+
+	adminAction := httpLocalhostGetAdminAction(c, path)
+	if _, ok := httpLocalhostServerEndingPaths[path]; ok {
+		httpLocalhostRecordAdminAction(adminAction)
+		c.Next()
+		return
+	}
+
+	c.Next()
+	adminAction.StatusCode = c.Writer.Status()
+	httpLocalhostRecordAdminAction(adminAction)
+}
+
+// httpLocalhostGetAdminAction gets the admin action for a request
+// This must be called before the handler runs so that the parameters can be read from the form
+func httpLocalhostGetAdminAction(c *gin.Context, path string) *AdminAction {
+	// The handler can still read the form values after it is parsed here
+	if err := c.Request.ParseForm(); err != nil {
+		logger.Error("Failed to parse the form for the audit log of \"" + path + "\": " +
+			err.Error())
+	}
+	parameters := make(map[string]string)
+	for key, values := range c.Request.Form {
+		parameters[key] = strings.Join(values, ", ")
+	}
+	var parametersJSON string
+	if v, err := json.Marshal(parameters); err != nil {
+		logger.Error("Failed to marshal the parameters for the audit log of \"" + path + "\": " +
+			err.Error())
+		parametersJSON = "{}"
+	} else {
+		parametersJSON = string(v)
+	}
+
+	admin := c.GetHeader(AdminHeader)
+	if admin == "" {
+		admin = parameters["moderator"]
+	}
+	if admin == "" {
+		admin = "unknown"
+	}
+
+	var targetUserID int
+	if username, ok := parameters["username"]; ok && username != "" {
+		if exists, user, err := models.Users.Get(username); err != nil {
+			logger.Error("Failed to get user \"" + username + "\" for the audit log: " +
+				err.Error())
+		} else if exists {
+			targetUserID = user.ID
+		}
+	}
+
+	// The table can also be specified by name, but only IDs are recorded
+	var targetTableID uint64
+	if v, err := strconv.ParseUint(parameters["tableID"], 10, 64); err == nil {
+		targetTableID = v
+	}
+
+	return &AdminAction{ // nolint: exhaustivestruct
+		Admin:         admin,
+		Action:        strings.TrimPrefix(path, "/"),
+		TargetUserID:  targetUserID,
+		TargetTableID: targetTableID,
+		Parameters:    parametersJSON,
+	}
+}
+
+func httpLocalhostRecordAdminAction(adminAction *AdminAction) {
+	if err := models.AdminActions.Insert(adminAction); err != nil {
+		logger.Error("Failed to insert the admin action for \"" + adminAction.Action + "\": " +
+			err.Error())
+	}
+}
+
+// httpLocalhostAdminActions lists the most recent administrative actions
+// If a username is specified, it only lists the actions that targeted that user
+func httpLocalhostAdminActions(c *gin.Context) {
+	count := DefaultAdminActionsCount
+	if countString := c.Query("count"); countString != "" {
+		if v, err := strconv.Atoi(countString); err != nil || v <= 0 {
+			http.Error(c.Writer, "Error: The count must be a positive number.", http.StatusBadRequest)
+			return
+		} else {
+			count = v
+		}
+	}
+
+	var targetUserID int
+	if username := c.Query("username"); username != "" {
+		if v, _, _, success := httpLocalhostGetUser(c, username); !success {
+			return
+		} else {
+			targetUserID = v
+		}
+	}
+
+	var adminActions []*AdminAction
+	if v, err := models.AdminActions.GetRecent(count, targetUserID); err != nil {
+		logger.Error("Failed to get the admin actions: " + err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		adminActions = v
+	}
+
+	if len(adminActions) == 0 {
+		c.String(http.StatusOK, "There are no admin actions.\n")
+		return
+	}
+
+	msg := ""
+	for _, adminAction := range adminActions {
+		msg += "#" + strconv.Itoa(adminAction.ID) + " - " +
+			adminAction.Datetime.UTC().Format("2006-01-02 15:04:05 MST") + " - " +
+			adminAction.Admin + " - " + adminAction.Action
+		if adminAction.TargetUserID != 0 {
+			msg += " - user \"" + adminAction.TargetUsername + "\" " +
+				"(" + strconv.Itoa(adminAction.TargetUserID) + ")"
+		}
+		if adminAction.TargetTableID != 0 {
+			msg += " - table " + strconv.FormatUint(adminAction.TargetTableID, 10)
+		}
+		msg += " - " + adminAction.Parameters
+		if adminAction.StatusCode != 0 {
+			msg += " - status " + strconv.Itoa(adminAction.StatusCode)
+		} else {
+			msg += " - status unknown"
+		}
+		msg += "\n"
+	}
+	c.String(http.StatusOK, msg)
+}
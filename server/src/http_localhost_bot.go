@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostBot flags (or unflags) an account as belonging to a bot
+func httpLocalhostBot(c *gin.Context, username string, userID int, bot bool) {
+	// Local variables
+	w := c.Writer
+
+	if err := models.Users.SetBot(userID, bot); err != nil {
+		logger.Error("Failed to set the bot flag for user \"" + username + "\": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// They need to re-login for the change to take effect,
+	// so disconnect their existing connection, if any
+	logoutUser(userID)
+
+	c.String(http.StatusOK, "success\n")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostPuzzle creates a new puzzle from a game in the database
+// The turn is the same as the one that is used for "!replay" games (starting at 1)
+// The answers are in the text notation for games (e.g. "clues Bob red; plays slot 2"),
+// from the perspective of the player whose turn it is
+func httpLocalhostPuzzle(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	var gameID int
+	if v, err := strconv.Atoi(c.PostForm("gameID")); err != nil {
+		http.Error(w, "Error: You must specify a valid game ID.", http.StatusBadRequest)
+		return
+	} else {
+		gameID = v
+	}
+
+	var turn int
+	if v, err := strconv.Atoi(c.PostForm("turn")); err != nil || v < 1 {
+		http.Error(w, "Error: You must specify a valid turn.", http.StatusBadRequest)
+		return
+	} else {
+		turn = v - 1 // Turns are stored as the index of the action
+	}
+
+	// Check to see if the game exists in the database
+	if exists, err := models.Games.Exists(gameID); err != nil {
+		logger.Error("Failed to check to see if game " + strconv.Itoa(gameID) + " exists: " +
+			err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if !exists {
+		c.String(http.StatusNotFound, "Game "+strconv.Itoa(gameID)+" does not exist in the database.\n")
+		return
+	}
+
+	var playerIndex int
+	var answers []*GameAction
+	if v1, v2, err := getPuzzleAnswers(gameID, turn, c.PostForm("answers")); err != nil {
+		c.String(http.StatusBadRequest, "That is not a valid puzzle: "+err.Error()+"\n")
+		return
+	} else {
+		playerIndex = v1
+		answers = v2
+	}
+
+	var puzzleID int
+	if v, err := models.Puzzles.Insert(
+		gameID,
+		turn,
+		playerIndex,
+		answers,
+		c.PostForm("description"),
+	); err != nil {
+		logger.Error("Failed to insert the puzzle for game " + strconv.Itoa(gameID) + ": " +
+			err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		puzzleID = v
+	}
+
+	c.String(http.StatusOK, "Created puzzle #"+strconv.Itoa(puzzleID)+".\n")
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostReports lists the reports that have not been resolved yet
+// If a report ID is specified, it shows the full report (including the chat messages)
+func httpLocalhostReports(c *gin.Context) {
+	idString := c.Query("id")
+	if idString != "" {
+		var report *Report
+		if v, success := httpLocalhostGetReport(c, idString); !success {
+			return
+		} else {
+			report = v
+		}
+
+		msg := report.String() + "\n"
+		if report.TableID != 0 {
+			msg += "Table ID: " + strconv.FormatUint(report.TableID, 10) + "\n"
+		}
+		if report.Notes != "" {
+			msg += "Notes: " + report.Notes + "\n"
+		}
+		msg += "Chat:\n" + report.Chat + "\n"
+		c.String(http.StatusOK, msg)
+		return
+	}
+
+	var reports []*Report
+	if v, err := models.Reports.GetUnresolved(); err != nil {
+		logger.Error("Failed to get the unresolved reports: " + err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		reports = v
+	}
+
+	if len(reports) == 0 {
+		c.String(http.StatusOK, "There are no unresolved reports.\n")
+		return
+	}
+
+	msg := ""
+	for _, report := range reports {
+		msg += report.String() + "\n"
+	}
+	c.String(http.StatusOK, msg)
+}
+
+// httpLocalhostReportClaim marks that a moderator is looking into a report
+// (so that other moderators do not work on the same report)
+func httpLocalhostReportClaim(c *gin.Context) {
+	var report *Report
+	if v, success := httpLocalhostGetReport(c, c.PostForm("id")); !success {
+		return
+	} else {
+		report = v
+	}
+
+	var moderatorID int
+	var moderatorName string
+	if v1, v2, _, success := httpLocalhostGetUser(c, c.PostForm("moderator")); !success {
+		return
+	} else {
+		moderatorID = v1
+		moderatorName = v2
+	}
+
+	if report.DatetimeResolved != nil || report.DatetimeClaimed != nil {
+		c.String(http.StatusConflict, "Report #"+strconv.Itoa(report.ID)+" is already "+
+			report.GetStatus()+".\n")
+		return
+	}
+
+	if err := models.Reports.Claim(report.ID, moderatorID); err != nil {
+		logger.Error("Failed to claim report " + strconv.Itoa(report.ID) + ": " + err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	logger.Info("Moderator \"" + moderatorName + "\" claimed report #" +
+		strconv.Itoa(report.ID) + ".")
+	c.String(http.StatusOK, "success\n")
+}
+
+// httpLocalhostReportResolve closes a report with the action that was taken
+// The action can be "none", "warn" (which requires a "msg"), "mute", or "ban"
+// (mutes and bans can have a "duration"; see "httpLocalhostSanction()")
+func httpLocalhostReportResolve(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	var report *Report
+	if v, success := httpLocalhostGetReport(c, c.PostForm("id")); !success {
+		return
+	} else {
+		report = v
+	}
+
+	var moderatorID int
+	var moderatorName string
+	if v1, v2, _, success := httpLocalhostGetUser(c, c.PostForm("moderator")); !success {
+		return
+	} else {
+		moderatorID = v1
+		moderatorName = v2
+	}
+
+	if report.DatetimeResolved != nil {
+		c.String(http.StatusConflict, "Report #"+strconv.Itoa(report.ID)+" is already "+
+			report.GetStatus()+".\n")
+		return
+	}
+
+	// Validate the action
+	action := c.PostForm("action")
+	notes := c.PostForm("notes")
+	var datetimeExpires *time.Time
+	if action == ReportActionWarn {
+		if c.PostForm("msg") == "" {
+			c.String(http.StatusBadRequest, "You must send a \"msg\" POST parameter.\n")
+			return
+		}
+		if _, ok := sessions.Get(report.ReportedID); !ok {
+			c.String(http.StatusConflict, "User \""+report.ReportedName+"\" is not online, "+
+				"so they cannot be warned.\n")
+			return
+		}
+	} else if action == ReportActionMute || action == ReportActionBan {
+		if duration, err := parseSanctionDuration(c.PostForm("duration")); err != nil {
+			http.Error(w, "Error: That is not a valid duration: "+err.Error(), http.StatusBadRequest)
+			return
+		} else if duration != 0 {
+			expires := time.Now().Add(duration)
+			datetimeExpires = &expires
+		}
+	} else if action != ReportActionNone {
+		http.Error(
+			w,
+			"Error: The action must be \""+ReportActionNone+"\", \""+ReportActionWarn+"\", "+
+				"\""+ReportActionMute+"\", or \""+ReportActionBan+"\".",
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	// Mutes and bans from reports only apply to the account of the reported user
+	// (use "/ban" or "/mute" directly to also sanction their IP)
+	if action == ReportActionMute || action == ReportActionBan {
+		reason := "Report #" + strconv.Itoa(report.ID)
+		if notes != "" {
+			reason += ": " + notes
+		}
+		if err := models.Sanctions.Insert(&SanctionRow{
+			Type:            action, // The actions have the same names as the sanction types
+			UserID:          report.ReportedID,
+			IP:              "",
+			Reason:          reason,
+			IssuedBy:        moderatorID,
+			DatetimeExpires: datetimeExpires,
+		}); err != nil {
+			logger.Error("Failed to insert the " + action + " row: " + err.Error())
+			http.Error(
+				w,
+				http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		logoutUser(report.ReportedID)
+	}
+
+	if err := models.Reports.Resolve(report.ID, moderatorID, action, notes); err != nil {
+		logger.Error("Failed to resolve report " + strconv.Itoa(report.ID) + ": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	logger.Info("Moderator \"" + moderatorName + "\" resolved report #" +
+		strconv.Itoa(report.ID) + " with the action of \"" + action + "\".")
+
+	if action == ReportActionWarn {
+		httpLocalhostSendWarning(c, report.ReportedID)
+		return
+	}
+
+	c.String(http.StatusOK, "success\n")
+}
+
+// httpLocalhostGetReport returns the report with the provided ID
+// If the report does not exist, it writes an error to the response
+func httpLocalhostGetReport(c *gin.Context, idString string) (*Report, bool) {
+	var id int
+	if v, err := strconv.Atoi(idString); err != nil {
+		http.Error(c.Writer, "Error: You must specify a valid report ID.", http.StatusBadRequest)
+		return nil, false
+	} else {
+		id = v
+	}
+
+	if report, exists, err := models.Reports.Get(id); err != nil {
+		logger.Error("Failed to get report " + strconv.Itoa(id) + ": " + err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return nil, false
+	} else if !exists {
+		c.String(http.StatusNotFound, "Report #"+strconv.Itoa(id)+" does not exist.\n")
+		return nil, false
+	} else {
+		return report, true
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SanctionScopeAccount = "account"
+	SanctionScopeIP      = "ip"
+	SanctionScopeBoth    = "both"
+)
+
+// httpLocalhostSanction bans or mutes a user
+// The "scope" can be "account", "ip", or "both" (the default)
+// The "duration" can be something like "30m", "12h", or "7d" (the default is permanent)
+func httpLocalhostSanction(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+	sanctionType := strings.TrimPrefix(c.Request.URL.Path, "/")
+
+	var userID int
+	var username string
+	var ip string
+	if v1, v2, v3, success := httpLocalhostGetUser(c, c.PostForm("username")); !success {
+		return
+	} else {
+		userID = v1
+		username = v2
+		ip = v3
+	}
+
+	var moderatorID int
+	if v, _, _, success := httpLocalhostGetUser(c, c.PostForm("moderator")); !success {
+		return
+	} else {
+		moderatorID = v
+	}
+
+	// The scope determines whether the account, the last IP, or both are sanctioned
+	scope := c.PostForm("scope")
+	if scope == "" {
+		scope = SanctionScopeBoth
+	}
+	targetUserID := userID
+	targetIP := ip
+	if scope == SanctionScopeAccount {
+		targetIP = ""
+	} else if scope == SanctionScopeIP {
+		targetUserID = 0
+	} else if scope != SanctionScopeBoth {
+		http.Error(
+			w,
+			"Error: The scope must be \""+SanctionScopeAccount+"\", \""+SanctionScopeIP+"\", "+
+				"or \""+SanctionScopeBoth+"\".",
+			http.StatusBadRequest,
+		)
+		return
+	}
+	if scope == SanctionScopeIP && ip == "" {
+		c.String(http.StatusConflict, "User \""+username+"\" does not have a last IP.\n")
+		return
+	}
+
+	var datetimeExpires *time.Time
+	if duration, err := parseSanctionDuration(c.PostForm("duration")); err != nil {
+		http.Error(
+			w,
+			"Error: That is not a valid duration: "+err.Error(),
+			http.StatusBadRequest,
+		)
+		return
+	} else if duration != 0 {
+		expires := time.Now().Add(duration)
+		datetimeExpires = &expires
+	}
+
+	// Check to see if this user is already banned / muted
+	if sanction, exists, err := models.Sanctions.Check(
+		sanctionType,
+		targetUserID,
+		targetIP,
+	); err != nil {
+		logger.Error("Failed to check to see if user \"" + username + "\" has a " + sanctionType +
+			": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if exists {
+		c.String(http.StatusConflict, "User \""+username+"\" already has an active "+sanctionType+":\n"+
+			sanction.String()+"\n")
+		return
+	}
+
+	if err := models.Sanctions.Insert(&SanctionRow{
+		Type:            sanctionType,
+		UserID:          targetUserID,
+		IP:              targetIP,
+		Reason:          c.PostForm("reason"),
+		IssuedBy:        moderatorID,
+		DatetimeExpires: datetimeExpires,
+	}); err != nil {
+		logger.Error("Failed to insert the " + sanctionType + " row: " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// Disconnect the user so that the sanction takes effect immediately
+	// (for mutes, this also updates the muted status that is shown on the client)
+	logoutUser(userID)
+
+	c.String(http.StatusOK, "success\n")
+}
+
+// httpLocalhostLiftSanction unbans or unmutes a user
+// Both the sanctions on their account and on their last IP are lifted
+func httpLocalhostLiftSanction(c *gin.Context) {
+	// Local variables
+	sanctionType := strings.TrimPrefix(strings.TrimPrefix(c.Request.URL.Path, "/"), "un")
+
+	var userID int
+	var username string
+	var ip string
+	if v1, v2, v3, success := httpLocalhostGetUser(c, c.PostForm("username")); !success {
+		return
+	} else {
+		userID = v1
+		username = v2
+		ip = v3
+	}
+
+	var moderatorID int
+	if v, _, _, success := httpLocalhostGetUser(c, c.PostForm("moderator")); !success {
+		return
+	} else {
+		moderatorID = v
+	}
+
+	var numLifted int
+	if v, err := models.Sanctions.Lift(
+		sanctionType,
+		userID,
+		ip,
+		moderatorID,
+		c.PostForm("reason"),
+	); err != nil {
+		logger.Error("Failed to lift the sanctions for user \"" + username + "\": " + err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		numLifted = v
+	}
+
+	if numLifted == 0 {
+		c.String(http.StatusConflict, "User \""+username+"\" does not have an active "+sanctionType+".\n")
+		return
+	}
+
+	// Mutes are only shown on the client after reconnecting
+	if sanctionType == SanctionTypeMute {
+		logoutUser(userID)
+	}
+
+	c.String(http.StatusOK, "Lifted "+strconv.Itoa(numLifted)+" "+sanctionType+"(s).\n")
+}
+
+// httpLocalhostSanctions lists all of the active bans and mutes
+// If a username is specified, it lists every sanction that was ever issued against that user
+// (including the ones that have expired or have been lifted)
+func httpLocalhostSanctions(c *gin.Context) {
+	var sanctions []*Sanction
+	username := c.Query("username")
+	if username == "" {
+		if v, err := models.Sanctions.GetActive(); err != nil {
+			logger.Error("Failed to get the active sanctions: " + err.Error())
+			http.Error(
+				c.Writer,
+				http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError,
+			)
+			return
+		} else {
+			sanctions = v
+		}
+	} else {
+		var userID int
+		var ip string
+		if v1, _, v2, success := httpLocalhostGetUser(c, username); !success {
+			return
+		} else {
+			userID = v1
+			ip = v2
+		}
+
+		if v, err := models.Sanctions.GetHistory(userID, ip); err != nil {
+			logger.Error("Failed to get the sanctions for user \"" + username + "\": " +
+				err.Error())
+			http.Error(
+				c.Writer,
+				http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError,
+			)
+			return
+		} else {
+			sanctions = v
+		}
+	}
+
+	if len(sanctions) == 0 {
+		c.String(http.StatusOK, "There are no sanctions.\n")
+		return
+	}
+
+	msg := ""
+	for _, sanction := range sanctions {
+		msg += sanction.String() + "\n"
+	}
+	c.String(http.StatusOK, msg)
+}
+
+// httpLocalhostGetUser returns the user ID, the username, and the last IP of a user
+// If the user does not exist, it writes an error to the response
+func httpLocalhostGetUser(c *gin.Context, username string) (int, string, string, bool) {
+	// Local variables
+	w := c.Writer
+
+	if username == "" {
+		http.Error(w, "Error: You must specify a username.", http.StatusBadRequest)
+		return 0, "", "", false
+	}
+
+	var user User
+	if exists, v, err := models.Users.Get(username); err != nil {
+		logger.Error("Failed to get user \"" + username + "\": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return 0, "", "", false
+	} else if !exists {
+		c.String(http.StatusNotFound, "User \""+username+"\" does not exist in the database.\n")
+		return 0, "", "", false
+	} else {
+		user = v
+	}
+
+	var lastIP string
+	if v, err := models.Users.GetLastIP(user.Username); err != nil {
+		logger.Error("Failed to get the last IP for \"" + user.Username + "\": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return 0, "", "", false
+	} else {
+		lastIP = v
+	}
+
+	return user.ID, user.Username, lastIP, true
+}
@@ -11,7 +11,7 @@ func httpLocalhostSendError(c *gin.Context, userID int) {
 	// Validate that the admin sent a message
 	msg := c.PostForm("msg")
 	if msg == "" {
-		c.String(http.StatusOK, "You must send a \"msg\" POST parameter.\n")
+		c.String(http.StatusBadRequest, "You must send a \"msg\" POST parameter.\n")
 		return
 	}
 
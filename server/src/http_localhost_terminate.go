@@ -34,7 +34,7 @@ func httpLocalhostTerminate(c *gin.Context) {
 	if searchingByName {
 		if v, exists := getTableIDFromName(c, tableNameOrID); !exists {
 			msg := "Table \"" + tableNameOrID + "\" does not exist.\n"
-			c.String(http.StatusOK, msg)
+			c.String(http.StatusNotFound, msg)
 			return
 		} else {
 			tableID = v
@@ -45,7 +45,14 @@ func httpLocalhostTerminate(c *gin.Context) {
 	t, exists := getTableAndLock(c, nil, tableID, true, false)
 	if !exists {
 		msg := "Table \"" + strconv.FormatUint(tableID, 10) + "\" does not exist.\n"
-		c.String(http.StatusOK, msg)
+		c.String(http.StatusNotFound, msg)
+		return
+	}
+	defer t.Unlock(c)
+
+	if !t.Running || t.Replay {
+		msg := "Table \"" + strconv.FormatUint(tableID, 10) + "\" is not an ongoing game.\n"
+		c.String(http.StatusConflict, msg)
 		return
 	}
 
@@ -59,4 +66,14 @@ func httpLocalhostTerminate(c *gin.Context) {
 		NoTableLock:  true,
 		NoTablesLock: true,
 	})
+
+	// The action is rejected if the owner of the table is not one of the players
+	// (the reason is sent to the owner instead of to us)
+	if !t.Replay && !t.Deleted {
+		msg := "Table \"" + strconv.FormatUint(tableID, 10) + "\" could not be terminated.\n"
+		c.String(http.StatusConflict, msg)
+		return
+	}
+
+	c.String(http.StatusOK, "success\n")
 }
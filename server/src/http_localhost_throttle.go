@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostThrottle gives a user (and their last IP) stricter rate limits
+// (see "throttle.go")
+func httpLocalhostThrottle(c *gin.Context) {
+	var userID int
+	var username string
+	var ip string
+	if v1, v2, v3, success := httpLocalhostGetUser(c, c.PostForm("username")); !success {
+		return
+	} else {
+		userID = v1
+		username = v2
+		ip = v3
+	}
+
+	// Check to see if this user is already throttled
+	if throttled, err := models.ThrottledIPs.Check(userID, ip); err != nil {
+		logger.Error("Failed to check to see if user \"" + username + "\" is throttled: " +
+			err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if throttled {
+		c.String(http.StatusConflict, "User \""+username+"\" is already throttled.\n")
+		return
+	}
+
+	if err := models.ThrottledIPs.Insert(ip, userID, c.PostForm("reason")); err != nil {
+		logger.Error("Failed to insert the throttled IP row: " + err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// If they are online, the stricter rate limits take effect immediately
+	if s, ok := sessions.Get(userID); ok {
+		s.SetThrottled(true)
+	}
+
+	c.String(http.StatusOK, "success\n")
+}
+
+// httpLocalhostUnthrottle removes the throttles on a user and on their last IP
+func httpLocalhostUnthrottle(c *gin.Context) {
+	var userID int
+	var username string
+	var ip string
+	if v1, v2, v3, success := httpLocalhostGetUser(c, c.PostForm("username")); !success {
+		return
+	} else {
+		userID = v1
+		username = v2
+		ip = v3
+	}
+
+	var numDeleted int
+	if v, err := models.ThrottledIPs.Delete(userID, ip); err != nil {
+		logger.Error("Failed to delete the throttled IP rows for user \"" + username + "\": " +
+			err.Error())
+		http.Error(
+			c.Writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		numDeleted = v
+	}
+
+	if numDeleted == 0 {
+		c.String(http.StatusConflict, "User \""+username+"\" is not throttled.\n")
+		return
+	}
+
+	if s, ok := sessions.Get(userID); ok {
+		s.SetThrottled(false)
+	}
+
+	c.String(http.StatusOK, "Removed "+strconv.Itoa(numDeleted)+" throttle(s).\n")
+}
@@ -131,6 +131,31 @@ func httpLogin(c *gin.Context) {
 				return
 			}
 		}
+
+		// Check to see if their account is banned
+		if sanction, banned, err := models.Sanctions.Check(
+			SanctionTypeBan,
+			user.ID,
+			data.IP,
+		); err != nil {
+			logger.Error("Failed to check to see if user \"" + data.Username + "\" is banned: " +
+				err.Error())
+			http.Error(
+				w,
+				http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError,
+			)
+			return
+		} else if banned {
+			logger.Info("User \"" + data.Username + "\" tried to log in, but they are banned.")
+			http.Error(
+				w,
+				sanction.GetDescription()+" "+
+					"Please contact an administrator if you think this is a mistake.",
+				http.StatusUnauthorized,
+			)
+			return
+		}
 	} else {
 		// Check to see if any other users have a normalized version of this username
 		// This prevents username-spoofing attacks and homoglyph usage
@@ -243,7 +268,8 @@ func httpLoginValidate(c *gin.Context) (*HTTPLoginData, bool) {
 	}
 
 	// Check to see if their IP is banned
-	if banned, err := models.BannedIPs.Check(ip); err != nil {
+	// (their account is checked after they have proven that they own it)
+	if sanction, banned, err := models.Sanctions.Check(SanctionTypeBan, 0, ip); err != nil {
 		logger.Error("Failed to check to see if the IP \"" + ip + "\" is banned: " + err.Error())
 		http.Error(
 			w,
@@ -255,7 +281,7 @@ func httpLoginValidate(c *gin.Context) (*HTTPLoginData, bool) {
 		logger.Info("IP \"" + ip + "\" tried to log in, but they are banned.")
 		http.Error(
 			w,
-			"Your IP address has been banned. "+
+			sanction.GetDescription()+" "+
 				"Please contact an administrator if you think this is a mistake.",
 			http.StatusUnauthorized,
 		)
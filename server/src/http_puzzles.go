@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	gsessions "github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+func httpPuzzles(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	var puzzles []*Puzzle
+	if v, err := models.Puzzles.GetAll(); err != nil {
+		logger.Error("Failed to get the puzzles: " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		puzzles = v
+	}
+
+	// If they are logged in, show them their progress
+	// (their cookie will have the "userID" value that we set in "httpLogin()")
+	session := gsessions.Default(c)
+	loggedIn := false
+	progress := make(map[int]*UserPuzzle)
+	if v := session.Get("userID"); v != nil {
+		if userID, ok := v.(int); ok {
+			loggedIn = true
+			if v, err := models.UserPuzzles.GetAll(userID); err != nil {
+				logger.Error("Failed to get the puzzle progress for user " +
+					strconv.Itoa(userID) + ": " + err.Error())
+				http.Error(
+					w,
+					http.StatusText(http.StatusInternalServerError),
+					http.StatusInternalServerError,
+				)
+				return
+			} else {
+				progress = v
+			}
+		}
+	}
+
+	data := &TemplateData{ // nolint: exhaustivestruct
+		Title:          "Puzzles",
+		Puzzles:        puzzles,
+		PuzzleProgress: progress,
+		LoggedIn:       loggedIn,
+	}
+	httpServeTemplate(w, data, "informational", "puzzles")
+}
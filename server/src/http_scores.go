@@ -123,7 +123,7 @@ func httpScores(c *gin.Context) {
 		NumGamesSpeedrun:           profileStats.NumGamesSpeedrun,
 		TimePlayedSpeedrun:         timePlayedSpeedrun,
 		NumMaxScores:               numMaxScores,
-		TotalMaxScores:             len(variantNames) * 5, // For 2 to 6 players
+		TotalMaxScores:             len(variantNames) * NumBestScores, // For 2 to 8 players
 		PercentageMaxScores:        percentageMaxScoresString,
 		NumMaxScoresPerType:        numMaxScoresPerType,
 		PercentageMaxScoresPerType: percentageMaxScoresPerType,
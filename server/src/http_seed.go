@@ -47,16 +47,35 @@ func httpSeed(c *gin.Context) {
 		gameHistoryList = v
 	}
 
+	// Get the result of the solver for this seed, if any
+	var maxAchievableScore int
+	var maxAchievableProven bool
+	if v1, v2, err := models.Seeds.GetMaxAchievableScore(seed); err != nil {
+		logger.Error("Failed to get the max achievable score for seed \"" + seed + "\": " +
+			err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		maxAchievableScore = v1
+		maxAchievableProven = v2
+	}
+
 	if _, ok := c.Request.URL.Query()["api"]; ok {
 		c.JSON(http.StatusOK, gameHistoryList)
 		return
 	}
 
 	data := &TemplateData{ // nolint: exhaustivestruct
-		Title:        "History",
-		History:      gameHistoryList,
-		NamesTitle:   "seed: " + seed,
-		SpecificSeed: true,
+		Title:               "History",
+		History:             gameHistoryList,
+		NamesTitle:          "seed: " + seed,
+		SpecificSeed:        true,
+		MaxAchievableScore:  maxAchievableScore,
+		MaxAchievableProven: maxAchievableProven,
 	}
 	httpServeTemplate(w, data, "profile", "history")
 }
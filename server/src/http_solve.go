@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpSolve finds the best score that was achievable on the deck of a game from the database
+// (e.g. "could this deck have been won?")
+func httpSolve(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	// Parse the game ID from the URL
+	databaseIDString := c.Param("databaseID")
+	if databaseIDString == "" {
+		http.Error(w, "Error: You must specify a database game ID.", http.StatusNotFound)
+		return
+	}
+
+	// Validate that it is a number
+	var databaseID int
+	if v, err := strconv.Atoi(databaseIDString); err != nil {
+		http.Error(w, "Error: That is not a valid database game ID.", http.StatusBadRequest)
+		return
+	} else {
+		databaseID = v
+	}
+
+	// Check to see if the game exists in the database
+	if exists, err := models.Games.Exists(databaseID); err != nil {
+		logger.Error("Failed to check to see if game " + strconv.Itoa(databaseID) + " exists: " +
+			err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else if !exists {
+		http.Error(w, "Error: That game does not exist in the database.", http.StatusNotFound)
+		return
+	}
+
+	var gameJSON *GameJSON
+	if v, err := getGameJSON(databaseID); err != nil {
+		logger.Error("Failed to export game " + strconv.Itoa(databaseID) + ": " + err.Error())
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	} else {
+		gameJSON = v
+	}
+
+	if solution, err := solveGameJSON(gameJSON); err != nil {
+		// The game cannot be solved (e.g. it has a player count that the solver does not support)
+		http.Error(w, "Error: That game cannot be solved: "+err.Error(), http.StatusBadRequest)
+	} else {
+		c.JSON(http.StatusOK, solution)
+	}
+}
@@ -92,7 +92,7 @@ func httpStats(c *gin.Context) {
 	// Convert the map (statsMap) to a slice (variantStatsList),
 	// filling in any non-played variants with 0 values
 	numMaxScores := 0
-	numMaxScoresPerType := make([]int, 5) // For 2-player, 3-player, etc.
+	numMaxScoresPerType := make([]int, NumBestScores) // For 2-player, 3-player, etc.
 	variantStatsList := make([]*VariantStatsData, 0)
 	for _, name := range variantNames {
 		variant := variants[name]
@@ -153,8 +153,8 @@ func httpStats(c *gin.Context) {
 		percentageMaxScoresPerType = append(percentageMaxScoresPerType, percentageString)
 	}
 
-	percentageMaxScores := float64(numMaxScores) / float64(len(variantNames)*5) * 100
-	// (we multiply by 5 because there are max scores for 2 to 6 players)
+	percentageMaxScores := float64(numMaxScores) / float64(len(variantNames)*NumBestScores) * 100
+	// (we multiply by the number of best scores because there are max scores for every player count)
 	percentageMaxScoresString := fmt.Sprintf("%.1f", percentageMaxScores)
 	percentageMaxScoresString = strings.TrimSuffix(percentageMaxScoresString, ".0")
 
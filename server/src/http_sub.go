@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -48,19 +47,22 @@ func httpParsePlayerNames(c *gin.Context) ([]int, []string, bool) {
 	// Normally, there will be just one player, e.g. "/history/Alice"
 	// But users can also request history for a specific combination of players,
 	// e.g. "/history/Alice/Bob/Cathy"
+	// (the route uses a catch-all parameter, so there can be an arbitrary number of players)
+	players := make([]string, 0)
+	for _, player := range strings.Split(c.Param("players"), "/") {
+		if player != "" {
+			players = append(players, player)
+		}
+	}
+	if len(players) == 0 {
+		http.Error(w, "Error: You must specify a player.", http.StatusNotFound)
+		return nil, nil, false
+	}
+
 	playerIDs := make([]int, 0)
 	playerNames := make([]string, 0)
 	playerNormalizedNames := make([]string, 0)
-	for i := 1; i <= 6; i++ {
-		player := c.Param("player" + strconv.Itoa(i))
-		if player == "" {
-			if i == 1 {
-				http.Error(w, "Error: You must specify a player.", http.StatusNotFound)
-				return nil, nil, false
-			}
-			break
-		}
-
+	for _, player := range players {
 		// Check to see if this is a duplicate player
 		// e.g. "/history/Alice/Bob/bob"
 		normalizedUsername := normalizeString(player)
@@ -109,7 +111,7 @@ func httpGetVariantStatsList(statsMap map[int]*UserStatsRow) (int, []int, []*Use
 	// Convert the map (statsMap) to a slice (variantStatsList),
 	// filling in any non-played variants with 0 values
 	numMaxScores := 0
-	numMaxScoresPerType := make([]int, 5) // For 2-player, 3-player, etc.
+	numMaxScoresPerType := make([]int, NumBestScores) // For 2-player, 3-player, etc.
 	variantStatsList := make([]*UserVariantStats, 0)
 	for _, name := range variantNames {
 		variant := variants[name]
@@ -171,8 +173,8 @@ func httpGetPercentageMaxScores(numMaxScores int, numMaxScoresPerType []int) (st
 		percentageMaxScoresPerType = append(percentageMaxScoresPerType, percentageString)
 	}
 
-	percentageMaxScores := float64(numMaxScores) / float64(len(variantNames)*5) * 100
-	// (we multiply by 5 because there are max scores for 2 to 6 players)
+	percentageMaxScores := float64(numMaxScores) / float64(len(variantNames)*NumBestScores) * 100
+	// (we multiply by the number of best scores because there are max scores for every player count)
 	percentageMaxScoresString := fmt.Sprintf("%.1f", percentageMaxScores)
 	percentageMaxScoresString = strings.TrimSuffix(percentageMaxScoresString, ".0")
 
@@ -39,24 +39,6 @@ func httpWS(c *gin.Context) {
 		ip = v
 	}
 
-	// Check to see if their IP is banned
-	if banned, err := models.BannedIPs.Check(ip); err != nil {
-		msg := "Failed to check to see if the IP \"" + ip + "\" is banned: " + err.Error()
-		httpWSError(c, msg)
-		return
-	} else if banned {
-		logger.Info("IP \"" + ip + "\" tried to establish a WebSocket connection, " +
-			"but they are banned.")
-		http.Error(
-			w,
-			"Your IP address has been banned. "+
-				"Please contact an administrator if you think this is a mistake.",
-			http.StatusUnauthorized,
-		)
-		deleteCookie(c)
-		return
-	}
-
 	// If they have a valid cookie, it should have the "userID" value that we set in "httpLogin()"
 	session := gsessions.Default(c)
 	var userID int
@@ -69,6 +51,26 @@ func httpWS(c *gin.Context) {
 		userID = v.(int)
 	}
 
+	// Check to see if their account or their IP is banned
+	// (a ban can be issued after they have already logged in)
+	if sanction, banned, err := models.Sanctions.Check(SanctionTypeBan, userID, ip); err != nil {
+		msg := "Failed to check to see if user " + strconv.Itoa(userID) + " " +
+			"from IP \"" + ip + "\" is banned: " + err.Error()
+		httpWSError(c, msg)
+		return
+	} else if banned {
+		logger.Info("User " + strconv.Itoa(userID) + " from IP \"" + ip + "\" " +
+			"tried to establish a WebSocket connection, but they are banned.")
+		http.Error(
+			w,
+			sanction.GetDescription()+" "+
+				"Please contact an administrator if you think this is a mistake.",
+			http.StatusUnauthorized,
+		)
+		deleteCookie(c)
+		return
+	}
+
 	// Get the username for this user
 	var username string
 	if v, err := models.Users.GetUsername(userID); errors.Is(err, pgx.ErrNoRows) {
@@ -0,0 +1,257 @@
+// This file contains the functions for hypotheticals in shared replays
+// The server simulates every hypothetical action with the real rules of the game,
+// so that every spectator is guaranteed to see the same thing
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+const (
+	MaxHypoBranches       = 50
+	MaxHypoNameLength     = 30
+	DefaultHypoBranchName = "main"
+)
+
+// HypoBranch is one line of a hypothetical
+// Hypotheticals are stored as a tree of named branches;
+// a branch is created from the current point of another branch and can then diverge from it
+// Every branch contains all of its own actions (including the ones that it shares with its parent),
+// so that it does not change when its parent branch changes
+type HypoBranch struct {
+	Name string `json:"name"`
+	// The name of the branch that this branch was created from
+	// (this is blank for the first branch of the hypothetical)
+	Parent string `json:"parent"`
+	// The number of actions that were copied from the parent branch
+	BranchPoint int `json:"branchPoint"`
+	// The segment of the real game that the hypothetical starts from
+	Segment int           `json:"segment"`
+	Actions []*GameAction `json:"actions"`
+}
+
+type HypoBranchMessage struct {
+	Name        string `json:"name"`
+	Parent      string `json:"parent"`
+	BranchPoint int    `json:"branchPoint"`
+	NumActions  int    `json:"numActions"`
+}
+
+func NewHypoBranch(name string, parent *HypoBranch, segment int) *HypoBranch {
+	if parent == nil {
+		return &HypoBranch{
+			Name:        name,
+			Parent:      "",
+			BranchPoint: 0,
+			Segment:     segment,
+			Actions:     make([]*GameAction, 0),
+		}
+	}
+
+	actions := make([]*GameAction, len(parent.Actions))
+	copy(actions, parent.Actions)
+
+	return &HypoBranch{
+		Name:        name,
+		Parent:      parent.Name,
+		BranchPoint: len(parent.Actions),
+		Segment:     parent.Segment,
+		Actions:     actions,
+	}
+}
+
+// GetHypoBranch returns the branch of the hypothetical that is currently being shown
+func (g *Game) GetHypoBranch() *HypoBranch {
+	return g.HypoBranches[g.HypoCurrentBranch]
+}
+
+// GetHypotheticalState recreates the state of the current branch of the hypothetical,
+// along with all of the events that were generated along the way
+func (g *Game) GetHypotheticalState() (*engine.State, []engine.Event, error) {
+	branch := g.GetHypoBranch()
+	if branch == nil {
+		return nil, nil, errors.New("the hypothetical branch \"" + g.HypoCurrentBranch +
+			"\" does not exist")
+	}
+
+	return g.getHypoBranchState(branch)
+}
+
+func (g *Game) getHypoBranchState(branch *HypoBranch) (*engine.State, []engine.Event, error) {
+	var state *engine.State
+	if v, err := g.GetStateAtSegment(branch.Segment); err != nil {
+		return nil, nil, err
+	} else {
+		state = v
+	}
+
+	events := make([]engine.Event, 0)
+	for i, a := range branch.Actions {
+		if v1, v2, err := state.Apply(a); err != nil {
+			return nil, nil, errors.New("failed to apply hypothetical action " + strconv.Itoa(i) +
+				" of branch \"" + branch.Name + "\": " + err.Error())
+		} else {
+			state = v1
+			events = append(events, v2...)
+		}
+	}
+
+	return state, events, nil
+}
+
+// GetHypoActions returns the events of the current branch of the hypothetical as JSON,
+// which is the format that the client expects
+func (g *Game) GetHypoActions() ([]string, error) {
+	var events []engine.Event
+	if _, v, err := g.GetHypotheticalState(); err != nil {
+		return nil, err
+	} else {
+		events = v
+	}
+
+	return hypoEventsToJSON(events)
+}
+
+// GetHypoBranchList returns a description of every branch of the hypothetical,
+// sorted by name
+func (g *Game) GetHypoBranchList() []*HypoBranchMessage {
+	branches := make([]*HypoBranchMessage, 0)
+	for _, branch := range g.HypoBranches {
+		branches = append(branches, &HypoBranchMessage{
+			Name:        branch.Name,
+			Parent:      branch.Parent,
+			BranchPoint: branch.BranchPoint,
+			NumActions:  len(branch.Actions),
+		})
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Name < branches[j].Name
+	})
+
+	return branches
+}
+
+// LoadHypoBranches replaces the current hypothetical with branches that were saved previously
+// Every branch is simulated to ensure that it is still valid
+func (g *Game) LoadHypoBranches(branchesJSON []byte) error {
+	branches := make(map[string]*HypoBranch)
+	if err := json.Unmarshal(branchesJSON, &branches); err != nil {
+		return err
+	}
+	if len(branches) == 0 {
+		return errors.New("there are no branches")
+	}
+
+	currentBranch := ""
+	for name, branch := range branches {
+		if branch.Name != name {
+			return errors.New("the branch \"" + branch.Name + "\" is stored under the wrong name")
+		}
+		if _, _, err := g.getHypoBranchState(branch); err != nil {
+			return err
+		}
+
+		// Start on the first branch of the hypothetical
+		if branch.Parent == "" && (currentBranch == "" || name < currentBranch) {
+			currentBranch = name
+		}
+	}
+	if currentBranch == "" {
+		return errors.New("there is no starting branch")
+	}
+
+	g.Hypothetical = true
+	g.HypoBranches = branches
+	g.HypoCurrentBranch = currentBranch
+
+	return nil
+}
+
+// validateHypoName returns a sanitized name, or an empty string if the name is not valid
+func validateHypoName(s *Session, name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		s.Warning("The name cannot be blank.")
+		return ""
+	}
+	if len(name) > MaxHypoNameLength {
+		s.Warning("The name cannot be longer than " + strconv.Itoa(MaxHypoNameLength) +
+			" characters.")
+		return ""
+	}
+
+	return name
+}
+
+// parseHypoAction converts an action sent by the shared replay leader into a game action
+// The leader can either send an action in the same format as the "action" command,
+// or (for older clients) the event that a play, a discard, or a clue would generate
+// It returns nil for other events (e.g. "draw" or "turn"),
+// since the server generates those by itself
+func parseHypoAction(actionJSON string) (*GameAction, error) {
+	var a GameAction
+	if err := json.Unmarshal([]byte(actionJSON), &a); err == nil {
+		return engine.NewAction(a.Type, a.Target, a.Value), nil
+	}
+
+	var js json.RawMessage
+	if err := json.Unmarshal([]byte(actionJSON), &js); err != nil {
+		return nil, err
+	}
+
+	return hypoActionToGameAction(actionJSON), nil
+}
+
+// hypoActionToGameAction returns nil if the hypothetical action does not correspond to something
+// that a player did (e.g. a "draw" or a "turn" event)
+func hypoActionToGameAction(actionJSON string) *GameAction {
+	type HypoAction struct {
+		Type   string      `json:"type"`
+		Order  int         `json:"order"`
+		Failed bool        `json:"failed"`
+		Target int         `json:"target"`
+		Clue   engine.Clue `json:"clue"`
+	}
+	var hypoAction HypoAction
+	if err := json.Unmarshal([]byte(actionJSON), &hypoAction); err != nil {
+		return nil
+	}
+
+	if hypoAction.Type == "play" {
+		return engine.NewAction(ActionTypePlay, hypoAction.Order, 0)
+	} else if hypoAction.Type == "discard" {
+		// A misplay is represented as a failed discard
+		if hypoAction.Failed {
+			return engine.NewAction(ActionTypePlay, hypoAction.Order, 0)
+		}
+		return engine.NewAction(ActionTypeDiscard, hypoAction.Order, 0)
+	} else if hypoAction.Type == "clue" {
+		if hypoAction.Clue.Type == ClueTypeColor {
+			return engine.NewAction(ActionTypeColorClue, hypoAction.Target, hypoAction.Clue.Value)
+		} else if hypoAction.Clue.Type == ClueTypeRank {
+			return engine.NewAction(ActionTypeRankClue, hypoAction.Target, hypoAction.Clue.Value)
+		}
+	}
+
+	return nil
+}
+
+func hypoEventsToJSON(events []engine.Event) ([]string, error) {
+	actions := make([]string, 0)
+	for _, event := range events {
+		if v, err := json.Marshal(event); err != nil {
+			return nil, err
+		} else {
+			actions = append(actions, string(v))
+		}
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// GetStateAtSegment recreates the state of the game after the given number of actions
+// (in a replay, the segment is equal to the number of actions for every game that does not have
+// detrimental characters that can take two actions in one turn)
+func (g *Game) GetStateAtSegment(segment int) (*engine.State, error) {
+	if g.InitialState == nil {
+		return nil, errors.New("the initial state for this game was not recorded")
+	}
+	if segment < 0 {
+		segment = 0
+	}
+	if segment > len(g.Actions2) {
+		segment = len(g.Actions2)
+	}
+
+	state := g.InitialState
+	for i := 0; i < segment; i++ {
+		if v, _, err := state.Apply(g.Actions2[i]); err != nil {
+			return nil, errors.New("failed to apply action " + strconv.Itoa(i) + ": " + err.Error())
+		} else {
+			state = v
+		}
+	}
+
+	return state, nil
+}
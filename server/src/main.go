@@ -3,6 +3,7 @@ package main // In Go, executable commands must always use package main
 // This file contains the entry point for the server software
 
 import (
+	"math/rand"
 	"os"
 	"os/exec"
 	"path"
@@ -41,6 +42,11 @@ func main() {
 	// Configure the deadlock detector
 	deadlock.Opts.DisableLockOrderDetection = true
 
+	// Seed the global random number generator once
+	// (it is used for things that do not need to be reproducible, like the "/random" command;
+	// anything that is derived from a game seed must use the random number generator of the game)
+	rand.Seed(time.Now().UnixNano())
+
 	// Get the project path
 	// https://stackoverflow.com/questions/18537257/
 	if v, err := os.Executable(); err != nil {
@@ -180,15 +186,22 @@ func main() {
 	suitsInit()    // (in "suits.go")
 	variantsInit() // (in "variants.go")
 
-	// Initialize the action functions command map (in "command_action.go")
-	actionsFunctionsInit()
-
 	// Initialize the replay action functions command map (in "command_replay_action.go")
 	replayActionsFunctionsInit()
 
 	// Initialize "Detrimental Character Assignments" (in "characters.go")
 	charactersInit()
 
+	// Initialize the accounts for the bots that run inside of the server (in "bot.go")
+	botsInit()
+
+	// The server can also be used as a command-line tool to export games from the database
+	// (e.g. "./hanabi-live export -variant "No Variant" -output games.ndjson.gz")
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		exportCLI(os.Args[2:])
+		return
+	}
+
 	// Initialize the list that contains every word in the dictionary
 	wordListInit()
 
@@ -209,6 +222,7 @@ func main() {
 
 	// Restore tables that were ongoing at the time of the last server restart
 	restoreTables()
+	restoreCorrespondenceTables()
 
 	// Specify that we are running the HTTP framework in production
 	// (it is "gin.DebugMode" by default)
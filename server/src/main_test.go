@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+// TestMain loads the same data files as the "main()" function,
+// so that the tests can create games with real variants
+// (the tests are run from the "server/src" directory)
+func TestMain(m *testing.M) {
+	logger = NewLogger()
+	projectPath = path.Join("..", "..")
+	dataPath = path.Join(projectPath, "data")
+
+	colorsInit()
+	suitsInit()
+	variantsInit()
+
+	os.Exit(m.Run())
+}
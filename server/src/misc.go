@@ -66,7 +66,6 @@ func getRandom(min int, max int) int {
 		logger.Error("getRandom was given invalid arguments.")
 		return 0
 	}
-	rand.Seed(time.Now().UnixNano())
 	return rand.Intn(max-min) + min // nolint: gosec
 }
 
@@ -263,14 +262,16 @@ func secondsToDurationString(seconds int) (string, error) {
 	return msg, nil
 }
 
-// setSeed seeds the random number generator with a string
-// Golang's "rand.Seed()" function takes an int64, so we need to convert a string to an int64
+// newSeededRand returns a new random number generator that is seeded with a string
+// Each game gets its own generator so that concurrent games can never interleave with each other
+// (the global generator is shared by every goroutine)
+// Golang's "rand.NewSource()" function takes an int64, so we need to convert a string to an int64
 // We use the CRC64 hash function to do this
 // Also note that seeding with negative numbers will not work
-func setSeed(seed string) {
+func newSeededRand(seed string) *rand.Rand {
 	crc64Table := crc64.MakeTable(crc64.ECMA)
 	intSeed := crc64.Checksum([]byte(seed), crc64Table)
-	rand.Seed(int64(intSeed))
+	return rand.New(rand.NewSource(int64(intSeed))) // nolint: gosec
 }
 
 func stringInSlice(a string, slice []string) bool {
@@ -282,6 +283,16 @@ func stringInSlice(a string, slice []string) bool {
 	return false
 }
 
+func stringIndexInSlice(a string, slice []string) int {
+	for i, b := range slice {
+		if b == a {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // From: https://stackoverflow.com/questions/51997276/how-one-can-do-case-insensitive-sorting-using-sort-strings-in-golang
 func sortStringsCaseInsensitive(slice []string) []string {
 	sort.Slice(slice, func(i, j int) bool {
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// shuffleTestDeck shuffles a "No Variant" deck with the random number generator for the seed
+func shuffleTestDeck(seed string) []*CardIdentity {
+	options := &Options{ // nolint: exhaustivestruct
+		VariantName: "No Variant",
+	}
+	state := engine.NewState(variants[options.VariantName], options.EngineOptions())
+	state.InitDeck(nil)
+	state.ShuffleDeck(newSeededRand(seed).Intn)
+	return state.CardIdentities
+}
+
+func TestNewSeededRandSameSeed(t *testing.T) {
+	seed := "p2v0s1"
+	deck1 := shuffleTestDeck(seed)
+	deck2 := shuffleTestDeck(seed)
+	if !reflect.DeepEqual(deck1, deck2) {
+		t.Fatalf("shuffling with the seed of \"%s\" twice produced different decks", seed)
+	}
+
+	// Make sure that the seed is actually used
+	deck3 := shuffleTestDeck("p2v0s2")
+	if reflect.DeepEqual(deck1, deck3) {
+		t.Fatal("shuffling with two different seeds produced the same deck")
+	}
+}
+
+// Games that are starting at the same time on different tables must not interfere with each other
+// (run with "go test -race" to also check for data races)
+func TestNewSeededRandConcurrent(t *testing.T) {
+	const numTables = 20
+	const numShuffles = 10
+
+	expectedDecks := make([][]*CardIdentity, numTables)
+	for i := range expectedDecks {
+		expectedDecks[i] = shuffleTestDeck("p2v0s" + strconv.Itoa(i))
+	}
+
+	var wg sync.WaitGroup
+	failedSeeds := make(chan string, numTables*numShuffles)
+	for i := 0; i < numTables; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			seed := "p2v0s" + strconv.Itoa(i)
+			for j := 0; j < numShuffles; j++ {
+				if !reflect.DeepEqual(shuffleTestDeck(seed), expectedDecks[i]) {
+					failedSeeds <- seed
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(failedSeeds)
+
+	for seed := range failedSeeds {
+		t.Errorf("a concurrent shuffle with the seed of \"%s\" produced a different deck", seed)
+	}
+}
@@ -17,20 +17,30 @@ var (
 
 // Models contains a list of interfaces representing database tables
 type Models struct {
-	BannedIPs
+	AdminActions
 	ChatLog
 	ChatLogPM
+	CorrespondenceTables
 	DiscordWaiters
 	GameActions
+	GameAnalyses
+	GameHypotheticals
 	GameParticipantNotes
 	GameParticipants
 	Games
 	GameTags
 	Metadata
-	MutedIPs
+	Puzzles
+	ReplayAnnotations
+	Reports
+	Sanctions
 	Seeds
+	ThrottledIPs
 	Users
+	UserBlocks
 	UserFriends
+	UserPuzzles
+	UserReverseBlocks
 	UserReverseFriends
 	UserSettings
 	UserStats
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// AdminActions is append-only, so there are no methods to update or delete rows
+type AdminActions struct{}
+
+// AdminAction mirrors the "admin_actions" table row
+type AdminAction struct {
+	ID             int
+	Admin          string
+	Action         string
+	TargetUserID   int    // 0 if the action did not target a user
+	TargetUsername string // Blank if the action did not target a user (or if they were deleted)
+	TargetTableID  uint64 // 0 if the action did not target a table
+	Parameters     string
+	StatusCode     int // 0 if the action was recorded before it was performed
+	Datetime       time.Time
+}
+
+func (*AdminActions) Insert(adminAction *AdminAction) error {
+	// A target of 0 is stored as NULL
+	var targetUserID interface{}
+	if adminAction.TargetUserID != 0 {
+		targetUserID = adminAction.TargetUserID
+	}
+	var targetTableID interface{}
+	if adminAction.TargetTableID != 0 {
+		targetTableID = int64(adminAction.TargetTableID)
+	}
+	var statusCode interface{}
+	if adminAction.StatusCode != 0 {
+		statusCode = adminAction.StatusCode
+	}
+
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO admin_actions (
+			admin,
+			action,
+			target_user_id,
+			target_table_id,
+			parameters,
+			status_code
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`,
+		adminAction.Admin,
+		adminAction.Action,
+		targetUserID,
+		targetTableID,
+		adminAction.Parameters,
+		statusCode,
+	)
+	return err
+}
+
+// GetRecent returns the most recent administrative actions (from oldest to newest)
+// If the target user ID is not 0, it only returns the actions that targeted that user
+func (*AdminActions) GetRecent(count int, targetUserID int) ([]*AdminAction, error) {
+	adminActions := make([]*AdminAction, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT *
+		FROM (
+			SELECT
+				admin_actions.id,
+				admin_actions.admin,
+				admin_actions.action,
+				COALESCE(admin_actions.target_user_id, 0),
+				COALESCE(users.username, ''),
+				COALESCE(admin_actions.target_table_id, 0),
+				admin_actions.parameters,
+				COALESCE(admin_actions.status_code, 0),
+				admin_actions.datetime
+			FROM admin_actions
+				LEFT JOIN users ON users.id = admin_actions.target_user_id
+			WHERE $2 = 0 OR admin_actions.target_user_id = $2
+			ORDER BY admin_actions.id DESC
+			LIMIT $1
+		) AS recent_admin_actions
+		ORDER BY id
+	`, count, targetUserID); err != nil {
+		return adminActions, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var adminAction AdminAction
+		var targetTableID int64
+		if err := rows.Scan(
+			&adminAction.ID,
+			&adminAction.Admin,
+			&adminAction.Action,
+			&adminAction.TargetUserID,
+			&adminAction.TargetUsername,
+			&targetTableID,
+			&adminAction.Parameters,
+			&adminAction.StatusCode,
+			&adminAction.Datetime,
+		); err != nil {
+			return adminActions, err
+		}
+		adminAction.TargetTableID = uint64(targetTableID)
+		adminActions = append(adminActions, &adminAction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return adminActions, err
+	}
+	rows.Close()
+
+	return adminActions, nil
+}
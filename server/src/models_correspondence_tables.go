@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type CorrespondenceTables struct{}
+
+// Set inserts or updates the saved state of an ongoing correspondence game
+func (*CorrespondenceTables) Set(tableID uint64, tableJSON []byte) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO correspondence_tables (table_id, table_json, datetime_updated)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (table_id)
+		DO UPDATE SET table_json = $2, datetime_updated = NOW()
+	`, tableID, string(tableJSON))
+	return err
+}
+
+func (*CorrespondenceTables) Delete(tableID uint64) error {
+	_, err := db.Exec(context.Background(), `
+		DELETE FROM correspondence_tables
+		WHERE table_id = $1
+	`, tableID)
+	return err
+}
+
+// GetAll returns the saved state of every ongoing correspondence game
+func (*CorrespondenceTables) GetAll() ([][]byte, error) {
+	tableJSONs := make([][]byte, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT table_json
+		FROM correspondence_tables
+		ORDER BY table_id
+	`); err != nil {
+		return tableJSONs, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var tableJSON string
+		if err := rows.Scan(&tableJSON); err != nil {
+			return tableJSONs, err
+		}
+		tableJSONs = append(tableJSONs, []byte(tableJSON))
+	}
+
+	if err := rows.Err(); err != nil {
+		return tableJSONs, err
+	}
+	rows.Close()
+
+	return tableJSONs, nil
+}
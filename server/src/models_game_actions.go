@@ -3,17 +3,15 @@ package main
 import (
 	"context"
 
+	"github.com/Zamiell/hanabi-live/engine"
 	"github.com/jackc/pgx/v4"
 )
 
 type GameActions struct{}
 
 // These fields are described in "database_schema.sql"
-type GameAction struct {
-	Type   int `json:"type"`
-	Target int `json:"target"`
-	Value  int `json:"value"`
-}
+// (the engine uses the same representation for the actions that it applies)
+type GameAction = engine.Action
 
 // GameActionRow mirrors the "game_actions" table row
 type GameActionRow struct {
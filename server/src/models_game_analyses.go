@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type GameAnalyses struct{}
+
+// Insert records the analysis that was generated at the end of a game
+// (see the "GameAnalysis" struct in "game_analysis.go")
+func (*GameAnalyses) Insert(gameID int, analysisJSON []byte) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO game_analyses (game_id, analysis)
+		VALUES ($1, $2)
+	`, gameID, string(analysisJSON))
+	return err
+}
+
+// Get returns the analysis of a game as JSON
+// The boolean will be false if the game does not have an analysis
+// (e.g. games that were played before analyses were introduced)
+func (*GameAnalyses) Get(gameID int) ([]byte, bool, error) {
+	var analysisJSON string
+	if err := db.QueryRow(context.Background(), `
+		SELECT analysis
+		FROM game_analyses
+		WHERE game_id = $1
+	`, gameID).Scan(&analysisJSON); errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return []byte(analysisJSON), true, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type GameHypotheticals struct{}
+
+// Set inserts a saved hypothetical (or replaces the one with the same name)
+func (*GameHypotheticals) Set(gameID int, userID int, name string, branchesJSON []byte) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO game_hypotheticals (game_id, user_id, name, branches)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (game_id, name)
+		DO UPDATE SET user_id = $2, branches = $4, datetime_saved = NOW()
+	`, gameID, userID, name, string(branchesJSON))
+	return err
+}
+
+// Get returns the branches of a saved hypothetical as JSON
+// The boolean will be false if there is no saved hypothetical with that name
+func (*GameHypotheticals) Get(gameID int, name string) ([]byte, bool, error) {
+	var branchesJSON string
+	if err := db.QueryRow(context.Background(), `
+		SELECT branches
+		FROM game_hypotheticals
+		WHERE game_id = $1
+			AND name = $2
+	`, gameID, name).Scan(&branchesJSON); errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return []byte(branchesJSON), true, nil
+}
+
+func (*GameHypotheticals) GetNames(gameID int) ([]string, error) {
+	names := make([]string, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT name
+		FROM game_hypotheticals
+		WHERE game_id = $1
+		ORDER BY name
+	`, gameID); err != nil {
+		return names, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return names, err
+	}
+	rows.Close()
+
+	return names, nil
+}
@@ -8,8 +8,11 @@ type GameParticipants struct{}
 
 // GameParticipantsRow mirrors the "game_participants" table row
 type GameParticipantsRow struct {
-	GameID              int
-	UserID              int
+	GameID int
+	UserID int
+	// The player that started the game in this seat, if someone else took over the seat
+	// (0 if there was no substitution)
+	OriginalUserID      int
 	Seat                int
 	CharacterAssignment int
 	CharacterMetadata   int
@@ -20,19 +23,27 @@ func (*GameParticipants) BulkInsert(gameParticipantsRows []*GameParticipantsRow)
 		INSERT INTO game_participants (
 			game_id,
 			user_id,
+			original_user_id,
 			seat,
 			character_assignment,
 			character_metadata
 		)
 		VALUES %s
 	`
-	numArgsPerRow := 5
+	numArgsPerRow := 6
 	valueArgs := make([]interface{}, 0, numArgsPerRow*len(gameParticipantsRows))
 	for _, gameParticipantsRow := range gameParticipantsRows {
+		// 0 is stored as NULL
+		var originalUserID interface{}
+		if gameParticipantsRow.OriginalUserID != 0 {
+			originalUserID = gameParticipantsRow.OriginalUserID
+		}
+
 		valueArgs = append(
 			valueArgs,
 			gameParticipantsRow.GameID,
 			gameParticipantsRow.UserID,
+			originalUserID,
 			gameParticipantsRow.Seat,
 			gameParticipantsRow.CharacterAssignment,
 			gameParticipantsRow.CharacterMetadata,
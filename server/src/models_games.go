@@ -21,6 +21,7 @@ type GameRow struct {
 	Score            int
 	NumTurns         int
 	EndCondition     int
+	NumUndos         int
 	DatetimeStarted  time.Time
 	DatetimeFinished time.Time
 }
@@ -50,10 +51,20 @@ func (*Games) Insert(gameRow GameRow) (int, error) {
 				one_less_card,
 				all_or_nothing,
 				detrimental_characters,
+				starting_clue_tokens,
+				max_clue_tokens,
+				strike_limit,
+				hand_size,
+				time_control,
+				byo_yomi_periods,
+				team_clock,
+				correspondence,
+				correspondence_deadline,
 				seed,
 				score,
 				num_turns,
 				end_condition,
+				num_undos,
 				datetime_started,
 				datetime_finished
 			) VALUES (
@@ -76,7 +87,17 @@ func (*Games) Insert(gameRow GameRow) (int, error) {
 				$17,
 				$18,
 				$19,
-				$20
+				$20,
+				$21,
+				$22,
+				$23,
+				$24,
+				$25,
+				$26,
+				$27,
+				$28,
+				$29,
+				$30
 			)
 			RETURNING id
 		`,
@@ -96,10 +117,20 @@ func (*Games) Insert(gameRow GameRow) (int, error) {
 		gameRow.Options.OneLessCard,
 		gameRow.Options.AllOrNothing,
 		gameRow.Options.DetrimentalCharacters,
+		gameRow.Options.StartingClueTokens,
+		gameRow.Options.MaxClueTokens,
+		gameRow.Options.StrikeLimit,
+		gameRow.Options.HandSize,
+		gameRow.Options.TimeControl,
+		gameRow.Options.ByoYomiPeriods,
+		gameRow.Options.TeamClock,
+		gameRow.Options.Correspondence,
+		gameRow.Options.CorrespondenceDeadline,
 		gameRow.Seed,
 		gameRow.Score,
 		gameRow.NumTurns,
 		gameRow.EndCondition,
+		gameRow.NumUndos,
 		gameRow.DatetimeStarted,
 		gameRow.DatetimeFinished,
 	).Scan(&id); err != nil {
@@ -131,6 +162,7 @@ type GameHistory struct {
 	Score              int       `json:"score"`
 	NumTurns           int       `json:"numTurns"`
 	EndCondition       int       `json:"endCondition"`
+	NumUndos           int       `json:"numUndos"`
 	DatetimeStarted    time.Time `json:"datetimeStarted"`
 	DatetimeFinished   time.Time `json:"datetimeFinished"`
 	NumGamesOnThisSeed int       `json:"numGamesOnThisSeed"`
@@ -176,10 +208,20 @@ func (*Games) GetHistoryCustomSort(gameIDs []int, sortMode string) ([]*GameHisto
 			games1.one_less_card,
 			games1.all_or_nothing,
 			games1.detrimental_characters,
+			games1.starting_clue_tokens,
+			games1.max_clue_tokens,
+			games1.strike_limit,
+			games1.hand_size,
+			games1.time_control,
+			games1.byo_yomi_periods,
+			games1.team_clock,
+			games1.correspondence,
+			games1.correspondence_deadline,
 			games1.seed,
 			games1.score,
 			games1.num_turns,
 			games1.end_condition,
+			games1.num_undos,
 			games1.datetime_started,
 			games1.datetime_finished,
 			(
@@ -237,10 +279,20 @@ func (*Games) GetHistoryCustomSort(gameIDs []int, sortMode string) ([]*GameHisto
 			&gameHistory.Options.OneLessCard,
 			&gameHistory.Options.AllOrNothing,
 			&gameHistory.Options.DetrimentalCharacters,
+			&gameHistory.Options.StartingClueTokens,
+			&gameHistory.Options.MaxClueTokens,
+			&gameHistory.Options.StrikeLimit,
+			&gameHistory.Options.HandSize,
+			&gameHistory.Options.TimeControl,
+			&gameHistory.Options.ByoYomiPeriods,
+			&gameHistory.Options.TeamClock,
+			&gameHistory.Options.Correspondence,
+			&gameHistory.Options.CorrespondenceDeadline,
 			&gameHistory.Seed,
 			&gameHistory.Score,
 			&gameHistory.NumTurns,
 			&gameHistory.EndCondition,
+			&gameHistory.NumUndos,
 			&gameHistory.DatetimeStarted,
 			&gameHistory.DatetimeFinished,
 			&gameHistory.NumGamesOnThisSeed,
@@ -536,9 +588,8 @@ func (*Games) GetGameIDsSinceDatetime(datetime string) ([]int, error) {
 	SQLString := `
 		SELECT id
 		FROM games
-		/* We must get the results in decending order for the limit to work properly */
-		ORDER BY id DESC
 		WHERE datetime_started > $1
+		ORDER BY id DESC
 	`
 
 	var rows pgx.Rows
@@ -564,6 +615,73 @@ func (*Games) GetGameIDsSinceDatetime(datetime string) ([]int, error) {
 	return gameIDs, nil
 }
 
+// GetGameIDsExport gets the IDs of the games that match the filter in ascending order
+// Only games with an ID greater than "afterID" are returned,
+// so that the caller can page through the results by passing the last ID from the previous page
+func (*Games) GetGameIDsExport(filter *GameExportFilter, afterID int, amount int) ([]int, error) {
+	gameIDs := make([]int, 0)
+
+	conditions := []string{"id > $1"}
+	args := []interface{}{afterID}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, condition+" $"+strconv.Itoa(len(args)))
+	}
+	if filter.VariantID != -1 {
+		addCondition("variant_id =", filter.VariantID)
+	}
+	if filter.NumPlayers != 0 {
+		addCondition("num_players =", filter.NumPlayers)
+	}
+	if !filter.Start.IsZero() {
+		addCondition("datetime_started >=", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		addCondition("datetime_started <", filter.End)
+	}
+	if filter.PlayerID != 0 {
+		addCondition("id IN (SELECT game_id FROM game_participants WHERE user_id =", filter.PlayerID)
+		conditions[len(conditions)-1] += ")"
+	}
+	if filter.Tag != "" {
+		addCondition("id IN (SELECT game_id FROM game_tags WHERE tag =", filter.Tag)
+		conditions[len(conditions)-1] += ")"
+	}
+	if filter.Seed != "" {
+		addCondition("seed =", filter.Seed)
+	}
+	args = append(args, amount)
+
+	SQLString := `
+		SELECT id
+		FROM games
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY id
+		LIMIT $` + strconv.Itoa(len(args))
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), SQLString, args...); err != nil {
+		return gameIDs, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var gameID int
+		if err := rows.Scan(&gameID); err != nil {
+			return gameIDs, err
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return gameIDs, err
+	}
+	rows.Close()
+
+	return gameIDs, nil
+}
+
 func (*Games) GetGameIDsSinceInterval(interval string) ([]int, error) {
 	gameIDs := make([]int, 0)
 
@@ -632,7 +750,16 @@ func (*Games) GetOptions(databaseID int) (*Options, error) {
 			one_extra_card,
 			one_less_card,
 			all_or_nothing,
-			detrimental_characters
+			detrimental_characters,
+			starting_clue_tokens,
+			max_clue_tokens,
+			strike_limit,
+			hand_size,
+			time_control,
+			byo_yomi_periods,
+			team_clock,
+			correspondence,
+			correspondence_deadline
 		FROM games
 		WHERE games.id = $1
 	`, databaseID).Scan(
@@ -650,6 +777,15 @@ func (*Games) GetOptions(databaseID int) (*Options, error) {
 		&options.OneLessCard,
 		&options.AllOrNothing,
 		&options.DetrimentalCharacters,
+		&options.StartingClueTokens,
+		&options.MaxClueTokens,
+		&options.StrikeLimit,
+		&options.HandSize,
+		&options.TimeControl,
+		&options.ByoYomiPeriods,
+		&options.TeamClock,
+		&options.Correspondence,
+		&options.CorrespondenceDeadline,
 	); err != nil {
 		return &options, err
 	}
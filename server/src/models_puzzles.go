@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type Puzzles struct{}
+
+type Puzzle struct {
+	ID     int `json:"id"`
+	GameID int `json:"gameID"`
+	// The index of the action that the player has to make (starting at 0)
+	Turn        int    `json:"turn"`
+	PlayerIndex int    `json:"playerIndex"`
+	Description string `json:"description"`
+	VariantName string `json:"variant"`
+	NumPlayers  int    `json:"numPlayers"`
+	NumSolved   int    `json:"numSolved"` // The number of users that have solved the puzzle
+	// The answers are not sent to the client so that the puzzle is not spoiled
+	Answers []*GameAction `json:"-"`
+}
+
+// Insert returns the ID of the new puzzle
+func (*Puzzles) Insert(
+	gameID int,
+	turn int,
+	playerIndex int,
+	answers []*GameAction,
+	description string,
+) (int, error) {
+	var answersJSON []byte
+	if v, err := json.Marshal(answers); err != nil {
+		return 0, err
+	} else {
+		answersJSON = v
+	}
+
+	var puzzleID int
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO puzzles (game_id, turn, player_index, answers, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, gameID, turn, playerIndex, string(answersJSON), description).Scan(&puzzleID)
+	return puzzleID, err
+}
+
+// Get returns false if the puzzle does not exist
+func (*Puzzles) Get(puzzleID int) (*Puzzle, bool, error) {
+	var puzzles []*Puzzle
+	if v, err := getPuzzles(`WHERE puzzles.id = $1`, puzzleID); err != nil {
+		return nil, false, err
+	} else {
+		puzzles = v
+	}
+
+	if len(puzzles) == 0 {
+		return nil, false, nil
+	}
+
+	return puzzles[0], true, nil
+}
+
+// GetAll returns every puzzle, ordered by ID
+func (*Puzzles) GetAll() ([]*Puzzle, error) {
+	return getPuzzles(``)
+}
+
+func getPuzzles(whereClause string, args ...interface{}) ([]*Puzzle, error) {
+	puzzles := make([]*Puzzle, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT
+			puzzles.id,
+			puzzles.game_id,
+			puzzles.turn,
+			puzzles.player_index,
+			puzzles.answers,
+			puzzles.description,
+			games.variant_id,
+			games.num_players,
+			(
+				SELECT COUNT(*)
+				FROM user_puzzles
+				WHERE user_puzzles.puzzle_id = puzzles.id
+					AND user_puzzles.solved
+			) AS num_solved
+		FROM puzzles
+			JOIN games ON games.id = puzzles.game_id
+		`+whereClause+`
+		ORDER BY puzzles.id
+	`, args...); err != nil {
+		return puzzles, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var puzzle Puzzle
+		var answersJSON string
+		var variantID int
+		if err := rows.Scan(
+			&puzzle.ID,
+			&puzzle.GameID,
+			&puzzle.Turn,
+			&puzzle.PlayerIndex,
+			&answersJSON,
+			&puzzle.Description,
+			&variantID,
+			&puzzle.NumPlayers,
+			&puzzle.NumSolved,
+		); err != nil {
+			return puzzles, err
+		}
+
+		if v, ok := variantIDMap[variantID]; !ok {
+			err := errors.New("the variant ID of " + strconv.Itoa(variantID) + " is not valid")
+			return puzzles, err
+		} else {
+			puzzle.VariantName = v
+		}
+
+		if err := json.Unmarshal([]byte(answersJSON), &puzzle.Answers); err != nil {
+			return puzzles, err
+		}
+
+		puzzles = append(puzzles, &puzzle)
+	}
+
+	if err := rows.Err(); err != nil {
+		return puzzles, err
+	}
+	rows.Close()
+
+	return puzzles, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type ReplayAnnotations struct{}
+
+func (*ReplayAnnotations) Insert(
+	gameID int,
+	turn int,
+	userID int,
+	annotationType string,
+	value string,
+) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO replay_annotations (game_id, turn, user_id, type, value)
+		VALUES ($1, $2, $3, $4, $5)
+	`, gameID, turn, userID, annotationType, value)
+	return err
+}
+
+// GetAll returns all of the annotations for a game, ordered by turn
+// (annotations on the same turn are ordered by when they were made)
+func (*ReplayAnnotations) GetAll(gameID int) ([]*ReplayAnnotation, error) {
+	annotations := make([]*ReplayAnnotation, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT
+			replay_annotations.turn,
+			replay_annotations.type,
+			users.username,
+			replay_annotations.value,
+			replay_annotations.datetime_created
+		FROM replay_annotations
+			JOIN users ON users.id = replay_annotations.user_id
+		WHERE replay_annotations.game_id = $1
+		ORDER BY replay_annotations.turn, replay_annotations.id
+	`, gameID); err != nil {
+		return annotations, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var annotation ReplayAnnotation
+		if err := rows.Scan(
+			&annotation.Turn,
+			&annotation.Type,
+			&annotation.Username,
+			&annotation.Value,
+			&annotation.Datetime,
+		); err != nil {
+			return annotations, err
+		}
+		annotations = append(annotations, &annotation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return annotations, err
+	}
+	rows.Close()
+
+	return annotations, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type Reports struct{}
+
+// Report is a report that a player made against another player (see "report.go")
+type Report struct {
+	ID           int
+	ReporterName string
+	ReportedID   int
+	ReportedName string
+	Room         string
+	TableID      uint64 // 0 if the report was made from the lobby
+	Reason       string
+	Chat         string
+	// The usernames of the moderators (blank if the report has not been claimed / resolved yet)
+	ClaimedBy        string
+	ResolvedBy       string
+	Action           string // See the "ReportAction" constants
+	Notes            string
+	DatetimeCreated  time.Time
+	DatetimeClaimed  *time.Time
+	DatetimeResolved *time.Time
+}
+
+// ReportRow contains the values that are specified when a report is made
+type ReportRow struct {
+	ReporterID int
+	ReportedID int
+	Room       string
+	TableID    uint64 // 0 if the report was made from the lobby
+	Reason     string
+	Chat       string
+}
+
+func (*Reports) Insert(row *ReportRow) (int, error) {
+	// A table ID of 0 is stored as NULL
+	var tableID interface{}
+	if row.TableID != 0 {
+		tableID = int64(row.TableID)
+	}
+
+	var id int
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO reports (reporter_id, reported_id, room, table_id, reason, chat)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, row.ReporterID, row.ReportedID, row.Room, tableID, row.Reason, row.Chat).Scan(&id)
+	return id, err
+}
+
+// HasOpen checks to see if a user already has an unresolved report against another user
+func (*Reports) HasOpen(reporterID int, reportedID int) (bool, error) {
+	var id int
+	if err := db.QueryRow(context.Background(), `
+		SELECT id
+		FROM reports
+		WHERE reporter_id = $1
+			AND reported_id = $2
+			AND datetime_resolved IS NULL
+		LIMIT 1
+	`, reporterID, reportedID).Scan(&id); errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (*Reports) Get(id int) (*Report, bool, error) {
+	var reports []*Report
+	if v, err := getReports(`
+		WHERE reports.id = $1
+	`, id); err != nil {
+		return nil, false, err
+	} else {
+		reports = v
+	}
+
+	if len(reports) == 0 {
+		return nil, false, nil
+	}
+
+	return reports[0], true, nil
+}
+
+// GetUnresolved returns the moderation queue (from oldest to newest)
+func (*Reports) GetUnresolved() ([]*Report, error) {
+	return getReports(`
+		WHERE reports.datetime_resolved IS NULL
+		ORDER BY reports.id
+	`)
+}
+
+func (*Reports) Claim(id int, moderatorID int) error {
+	_, err := db.Exec(context.Background(), `
+		UPDATE reports
+		SET
+			claimed_by = $2,
+			datetime_claimed = NOW()
+		WHERE id = $1
+	`, id, moderatorID)
+	return err
+}
+
+func (*Reports) Resolve(id int, moderatorID int, action string, notes string) error {
+	_, err := db.Exec(context.Background(), `
+		UPDATE reports
+		SET
+			resolved_by = $2,
+			action = $3,
+			notes = $4,
+			datetime_resolved = NOW()
+		WHERE id = $1
+	`, id, moderatorID, action, notes)
+	return err
+}
+
+func getReports(whereClause string, args ...interface{}) ([]*Report, error) {
+	reports := make([]*Report, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT
+			reports.id,
+			reporters.username,
+			reports.reported_id,
+			reported.username,
+			reports.room,
+			COALESCE(reports.table_id, 0),
+			reports.reason,
+			reports.chat,
+			COALESCE(claimers.username, ''),
+			COALESCE(resolvers.username, ''),
+			reports.action,
+			reports.notes,
+			reports.datetime_created,
+			reports.datetime_claimed,
+			reports.datetime_resolved
+		FROM reports
+			JOIN users AS reporters ON reporters.id = reports.reporter_id
+			JOIN users AS reported ON reported.id = reports.reported_id
+			LEFT JOIN users AS claimers ON claimers.id = reports.claimed_by
+			LEFT JOIN users AS resolvers ON resolvers.id = reports.resolved_by
+		`+whereClause, args...); err != nil {
+		return reports, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var report Report
+		var tableID int64
+		if err := rows.Scan(
+			&report.ID,
+			&report.ReporterName,
+			&report.ReportedID,
+			&report.ReportedName,
+			&report.Room,
+			&tableID,
+			&report.Reason,
+			&report.Chat,
+			&report.ClaimedBy,
+			&report.ResolvedBy,
+			&report.Action,
+			&report.Notes,
+			&report.DatetimeCreated,
+			&report.DatetimeClaimed,
+			&report.DatetimeResolved,
+		); err != nil {
+			return reports, err
+		}
+		report.TableID = uint64(tableID)
+		reports = append(reports, &report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return reports, err
+	}
+	rows.Close()
+
+	return reports, nil
+}
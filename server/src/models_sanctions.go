@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type Sanctions struct{}
+
+// Sanction is a ban or a mute (see "sanctions.go")
+type Sanction struct {
+	ID       int
+	Type     string // See the "SanctionType" constants
+	Username string // Blank if the sanction only targets an IP
+	IP       string // Blank if the sanction only targets an account
+	Reason   string
+	// The username of the moderator (blank if it was issued automatically by the server)
+	IssuedBy        string
+	DatetimeIssued  time.Time
+	DatetimeExpires *time.Time // Nil if the sanction is permanent
+	LiftedBy        string
+	LiftReason      string
+	DatetimeLifted  *time.Time // Nil if the sanction was not lifted
+}
+
+// SanctionRow contains the values that are specified when a sanction is issued
+type SanctionRow struct {
+	Type   string
+	UserID int    // 0 if the sanction only targets an IP
+	IP     string // Blank if the sanction only targets an account
+	Reason string
+	// The user ID of the moderator (0 if it was issued automatically by the server)
+	IssuedBy        int
+	DatetimeExpires *time.Time // Nil if the sanction is permanent
+}
+
+const sanctionActiveSQL = `
+	sanctions.datetime_lifted IS NULL
+	AND (sanctions.datetime_expires IS NULL OR sanctions.datetime_expires > NOW())
+`
+
+// Check returns the active sanction of the provided type for an account or an IP (if any)
+// A user ID of 0 or a blank IP will not match anything
+// If there is more than one, it returns the one that lasts the longest
+func (*Sanctions) Check(sanctionType string, userID int, ip string) (*Sanction, bool, error) {
+	var sanctions []*Sanction
+	if v, err := getSanctions(`
+		WHERE sanctions.type = $1
+			AND (sanctions.user_id = $2 OR sanctions.ip = $3)
+			AND `+sanctionActiveSQL+`
+		ORDER BY sanctions.datetime_expires DESC NULLS FIRST
+	`, sanctionType, userID, ip); err != nil {
+		return nil, false, err
+	} else {
+		sanctions = v
+	}
+
+	if len(sanctions) == 0 {
+		return nil, false, nil
+	}
+
+	return sanctions[0], true, nil
+}
+
+func (*Sanctions) Insert(row *SanctionRow) error {
+	// A user ID of 0 and a blank IP are stored as NULL
+	var userID interface{}
+	if row.UserID != 0 {
+		userID = row.UserID
+	}
+	var ip interface{}
+	if row.IP != "" {
+		ip = row.IP
+	}
+	var issuedBy interface{}
+	if row.IssuedBy != 0 {
+		issuedBy = row.IssuedBy
+	}
+
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO sanctions (type, user_id, ip, reason, issued_by, datetime_expires)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, row.Type, userID, ip, row.Reason, issuedBy, row.DatetimeExpires)
+	return err
+}
+
+// GetActive returns all of the sanctions that have not expired or been lifted
+func (*Sanctions) GetActive() ([]*Sanction, error) {
+	return getSanctions(`
+		WHERE ` + sanctionActiveSQL + `
+		ORDER BY sanctions.id
+	`)
+}
+
+// GetHistory returns every sanction that was ever issued against an account or an IP,
+// including the ones that have expired or have been lifted
+func (*Sanctions) GetHistory(userID int, ip string) ([]*Sanction, error) {
+	return getSanctions(`
+		WHERE sanctions.user_id = $1 OR sanctions.ip = $2
+		ORDER BY sanctions.id
+	`, userID, ip)
+}
+
+// Lift ends all of the active sanctions of the provided type for an account or an IP
+// The rows are kept so that there is a history of the sanction (e.g. for appeals)
+// It returns the number of sanctions that were lifted
+func (*Sanctions) Lift(
+	sanctionType string,
+	userID int,
+	ip string,
+	liftedBy int,
+	reason string,
+) (int, error) {
+	// A moderator of 0 is stored as NULL
+	var liftedByValue interface{}
+	if liftedBy != 0 {
+		liftedByValue = liftedBy
+	}
+
+	tag, err := db.Exec(context.Background(), `
+		UPDATE sanctions
+		SET
+			lifted_by = $4,
+			lift_reason = $5,
+			datetime_lifted = NOW()
+		WHERE sanctions.type = $1
+			AND (sanctions.user_id = $2 OR sanctions.ip = $3)
+			AND `+sanctionActiveSQL,
+		sanctionType,
+		userID,
+		ip,
+		liftedByValue,
+		reason,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func getSanctions(whereClause string, args ...interface{}) ([]*Sanction, error) {
+	sanctions := make([]*Sanction, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT
+			sanctions.id,
+			sanctions.type,
+			COALESCE(users.username, ''),
+			COALESCE(sanctions.ip, ''),
+			sanctions.reason,
+			COALESCE(moderators.username, ''),
+			sanctions.datetime_issued,
+			sanctions.datetime_expires,
+			COALESCE(lifters.username, ''),
+			sanctions.lift_reason,
+			sanctions.datetime_lifted
+		FROM sanctions
+			LEFT JOIN users ON users.id = sanctions.user_id
+			LEFT JOIN users AS moderators ON moderators.id = sanctions.issued_by
+			LEFT JOIN users AS lifters ON lifters.id = sanctions.lifted_by
+		`+whereClause, args...); err != nil {
+		return sanctions, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var sanction Sanction
+		if err := rows.Scan(
+			&sanction.ID,
+			&sanction.Type,
+			&sanction.Username,
+			&sanction.IP,
+			&sanction.Reason,
+			&sanction.IssuedBy,
+			&sanction.DatetimeIssued,
+			&sanction.DatetimeExpires,
+			&sanction.LiftedBy,
+			&sanction.LiftReason,
+			&sanction.DatetimeLifted,
+		); err != nil {
+			return sanctions, err
+		}
+		sanctions = append(sanctions, &sanction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return sanctions, err
+	}
+	rows.Close()
+
+	return sanctions, nil
+}
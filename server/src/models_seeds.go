@@ -61,6 +61,40 @@ func (*Seeds) GetNumGames(seed string) (int, error) {
 	return numGames, nil
 }
 
+// SetMaxAchievableScore records the result of the solver for a seed
+// A proven score is never overwritten and an unproven score is only overwritten by a better one
+func (*Seeds) SetMaxAchievableScore(seed string, score int, proven bool) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO seeds (seed, num_games, max_achievable_score, max_achievable_proven)
+		VALUES ($1, 0, $2, $3)
+		ON CONFLICT (seed) DO UPDATE
+		SET
+			max_achievable_score = EXCLUDED.max_achievable_score,
+			max_achievable_proven = EXCLUDED.max_achievable_proven
+		WHERE
+			NOT seeds.max_achievable_proven
+			AND EXCLUDED.max_achievable_score >= seeds.max_achievable_score
+	`, seed, score, proven)
+	return err
+}
+
+// GetMaxAchievableScore returns -1 if the solver has not been run on this seed yet
+func (*Seeds) GetMaxAchievableScore(seed string) (int, bool, error) {
+	var score int
+	var proven bool
+	if err := db.QueryRow(context.Background(), `
+		SELECT max_achievable_score, max_achievable_proven
+		FROM seeds
+		WHERE seed = $1
+	`, seed).Scan(&score, &proven); errors.Is(err, pgx.ErrNoRows) {
+		return -1, false, nil
+	} else if err != nil {
+		return -1, false, err
+	}
+
+	return score, proven, nil
+}
+
 func (s *Seeds) UpdateAll() error {
 	seeds := make([]string, 0)
 
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type ThrottledIPs struct{}
+
+// Check returns whether or not an account or an IP is throttled
+// A user ID of 0 or a blank IP will not match anything
+func (*ThrottledIPs) Check(userID int, ip string) (bool, error) {
+	var id int
+	if err := db.QueryRow(context.Background(), `
+		SELECT id
+		FROM throttled_ips
+		WHERE user_id = $1 OR ip = $2
+		LIMIT 1
+	`, userID, ip).Scan(&id); errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (*ThrottledIPs) Insert(ip string, userID int, reason string) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO throttled_ips (ip, user_id, reason)
+		VALUES ($1, $2, $3)
+	`, ip, userID, reason)
+	return err
+}
+
+// Delete removes all of the throttles for an account or an IP
+// It returns the number of rows that were deleted
+func (*ThrottledIPs) Delete(userID int, ip string) (int, error) {
+	tag, err := db.Exec(context.Background(), `
+		DELETE FROM throttled_ips
+		WHERE user_id = $1 OR ip = $2
+	`, userID, ip)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type UserBlocks struct{}
+
+func (*UserBlocks) Insert(userID int, blockedID int) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO user_blocks (user_id, blocked_id)
+		VALUES ($1, $2)
+	`, userID, blockedID)
+	return err
+}
+
+func (*UserBlocks) Delete(userID int, blockedID int) error {
+	_, err := db.Exec(context.Background(), `
+		DELETE FROM user_blocks
+		WHERE user_id = $1
+			AND blocked_id = $2
+	`, userID, blockedID)
+	return err
+}
+
+func (*UserBlocks) GetAllUsernames(userID int) ([]string, error) {
+	blocks := make([]string, 0)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT users.username
+		FROM user_blocks
+			JOIN users ON user_blocks.blocked_id = users.id
+		WHERE user_blocks.user_id = $1
+	`, userID); err != nil {
+		return blocks, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var blocked string
+		if err := rows.Scan(&blocked); err != nil {
+			return blocks, err
+		}
+		blocks = append(blocks, blocked)
+	}
+	blocks = sortStringsCaseInsensitive(blocks)
+
+	if err := rows.Err(); err != nil {
+		return blocks, err
+	}
+	rows.Close()
+
+	return blocks, nil
+}
+
+// GetMap composes a map that represents all of the users that this user has blocked
+// (see the "GetMap()" function for friends)
+func (*UserBlocks) GetMap(userID int) (map[int]struct{}, error) {
+	blockMap := make(map[int]struct{})
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT blocked_id
+		FROM user_blocks
+		WHERE user_id = $1
+	`, userID); err != nil {
+		return blockMap, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var blockedID int
+		if err := rows.Scan(&blockedID); err != nil {
+			return blockMap, err
+		}
+		blockMap[blockedID] = struct{}{}
+	}
+
+	if err := rows.Err(); err != nil {
+		return blockMap, err
+	}
+	rows.Close()
+
+	return blockMap, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type UserPuzzles struct{}
+
+type UserPuzzle struct {
+	PuzzleID    int  `json:"puzzleID"`
+	NumAttempts int  `json:"numAttempts"`
+	Solved      bool `json:"solved"`
+}
+
+// RecordAttempt records that a user made a move in a puzzle
+// Once a puzzle is solved, it stays solved, even if the user gets it wrong in a later attempt
+func (*UserPuzzles) RecordAttempt(userID int, puzzleID int, correct bool) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO user_puzzles (user_id, puzzle_id, num_attempts, solved, datetime_solved)
+		VALUES ($1, $2, 1, $3, CASE WHEN $3 THEN NOW() END)
+		ON CONFLICT (user_id, puzzle_id) DO UPDATE
+		SET
+			num_attempts = user_puzzles.num_attempts + 1,
+			solved = user_puzzles.solved OR EXCLUDED.solved,
+			datetime_solved = COALESCE(user_puzzles.datetime_solved, EXCLUDED.datetime_solved)
+	`, userID, puzzleID, correct)
+	return err
+}
+
+// GetAll returns the progress of a user for every puzzle that they have attempted,
+// keyed by the ID of the puzzle
+func (*UserPuzzles) GetAll(userID int) (map[int]*UserPuzzle, error) {
+	userPuzzles := make(map[int]*UserPuzzle)
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT puzzle_id, num_attempts, solved
+		FROM user_puzzles
+		WHERE user_id = $1
+	`, userID); err != nil {
+		return userPuzzles, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var userPuzzle UserPuzzle
+		if err := rows.Scan(
+			&userPuzzle.PuzzleID,
+			&userPuzzle.NumAttempts,
+			&userPuzzle.Solved,
+		); err != nil {
+			return userPuzzles, err
+		}
+		userPuzzles[userPuzzle.PuzzleID] = &userPuzzle
+	}
+
+	if err := rows.Err(); err != nil {
+		return userPuzzles, err
+	}
+	rows.Close()
+
+	return userPuzzles, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type UserReverseBlocks struct{}
+
+func (*UserReverseBlocks) Insert(userID int, blockerID int) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO user_reverse_blocks (user_id, blocker_id)
+		VALUES ($1, $2)
+	`, userID, blockerID)
+	return err
+}
+
+func (*UserReverseBlocks) Delete(userID int, blockerID int) error {
+	_, err := db.Exec(context.Background(), `
+		DELETE FROM user_reverse_blocks
+		WHERE user_id = $1
+			AND blocker_id = $2
+	`, userID, blockerID)
+	return err
+}
+
+// GetMap composes a map that represents all of the users that have blocked this user
+func (*UserReverseBlocks) GetMap(userID int) (map[int]struct{}, error) {
+	blockerMap := make(map[int]struct{})
+
+	var rows pgx.Rows
+	if v, err := db.Query(context.Background(), `
+		SELECT blocker_id
+		FROM user_reverse_blocks
+		WHERE user_id = $1
+	`, userID); err != nil {
+		return blockerMap, err
+	} else {
+		rows = v
+	}
+
+	for rows.Next() {
+		var blockerID int
+		if err := rows.Scan(&blockerID); err != nil {
+			return blockerMap, err
+		}
+		blockerMap[blockerID] = struct{}{}
+	}
+
+	if err := rows.Err(); err != nil {
+		return blockerMap, err
+	}
+	rows.Close()
+
+	return blockerMap, nil
+}
@@ -44,6 +44,10 @@ func (*UserStats) Get(userID int, variantID int) (*UserStatsRow, error) {
 			best_score5_mod,
 			best_score6,
 			best_score6_mod,
+			best_score7,
+			best_score7_mod,
+			best_score8,
+			best_score8_mod,
 			average_score,
 			num_strikeouts
 		FROM user_stats
@@ -61,6 +65,10 @@ func (*UserStats) Get(userID int, variantID int) (*UserStatsRow, error) {
 		&stats.BestScores[3].Modifier,
 		&stats.BestScores[4].Score, // 6-player
 		&stats.BestScores[4].Modifier,
+		&stats.BestScores[5].Score, // 7-player
+		&stats.BestScores[5].Modifier,
+		&stats.BestScores[6].Score, // 8-player
+		&stats.BestScores[6].Modifier,
 		&stats.AverageScore,
 		&stats.NumStrikeouts,
 	); errors.Is(err, pgx.ErrNoRows) {
@@ -95,6 +103,10 @@ func (*UserStats) GetAll(userID int) (map[int]*UserStatsRow, error) {
 			best_score5_mod,
 			best_score6,
 			best_score6_mod,
+			best_score7,
+			best_score7_mod,
+			best_score8,
+			best_score8_mod,
 			average_score,
 			num_strikeouts
 		FROM user_stats
@@ -123,6 +135,10 @@ func (*UserStats) GetAll(userID int) (map[int]*UserStatsRow, error) {
 			&stats.BestScores[3].Modifier,
 			&stats.BestScores[4].Score, // 6-player
 			&stats.BestScores[4].Modifier,
+			&stats.BestScores[5].Score, // 7-player
+			&stats.BestScores[5].Modifier,
+			&stats.BestScores[6].Score, // 8-player
+			&stats.BestScores[6].Modifier,
 			&stats.AverageScore,
 			&stats.NumStrikeouts,
 		); err != nil {
@@ -146,9 +162,11 @@ func (*UserStats) GetAll(userID int) (map[int]*UserStatsRow, error) {
 // The stats passed in as an argument do not have to contain "NumGames", "AverageScore",
 // or "NumStrikeouts"; those will be calculated from the database
 func (*UserStats) Update(userID int, variantID int, stats *UserStatsRow) error {
-	// Validate that the BestScores slice contains 5 entries
-	if len(stats.BestScores) != 5 {
-		return errors.New("BestScores does not contain 5 entries (for 2 to 6 players)")
+	// Validate that the BestScores slice contains an entry for every player count
+	if len(stats.BestScores) != NumBestScores {
+		return errors.New("BestScores does not contain " + strconv.Itoa(NumBestScores) + " " +
+			"entries (for " + strconv.Itoa(MinPlayers) + " to " + strconv.Itoa(MaxPlayers) +
+			" players)")
 	}
 
 	// First, check to see if they have a row in the stats table for this variant already
@@ -200,6 +218,10 @@ func (*UserStats) Update(userID int, variantID int, stats *UserStatsRow) error {
 				best_score5_mod = $10,
 				best_score6 = $11,
 				best_score6_mod = $12,
+				best_score7 = $13,
+				best_score7_mod = $14,
+				best_score8 = $15,
+				best_score8_mod = $16,
 				average_score = (
 					/*
 					 * We enclose this query in an "COALESCE" so that it defaults to 0
@@ -239,6 +261,10 @@ func (*UserStats) Update(userID int, variantID int, stats *UserStatsRow) error {
 		stats.BestScores[3].Modifier,
 		stats.BestScores[4].Score, // 6-player
 		stats.BestScores[4].Modifier,
+		stats.BestScores[5].Score, // 7-player
+		stats.BestScores[5].Modifier,
+		stats.BestScores[6].Score, // 8-player
+		stats.BestScores[6].Modifier,
 	)
 	return err
 }
@@ -317,7 +343,7 @@ func (us *UserStats) UpdateAll(highestVariantID int) error {
 					stats.NumStrikeouts++
 				}
 
-				bestScoresIndex := gameHistory.Options.NumPlayers - 2
+				bestScoresIndex := gameHistory.Options.NumPlayers - MinPlayers
 				bestScore := stats.BestScores[bestScoresIndex]
 				modifier := gameHistory.Options.GetModifier()
 				thisScore := &BestScore{ // nolint: exhaustivestruct
@@ -325,7 +351,8 @@ func (us *UserStats) UpdateAll(highestVariantID int) error {
 					Score:      gameHistory.Score,
 					Modifier:   modifier,
 				}
-				if thisScore.IsBetterThan(bestScore) {
+				// Games with undos do not count towards best scores
+				if thisScore.IsBetterThan(bestScore) && gameHistory.NumUndos == 0 {
 					bestScore.Score = gameHistory.Score
 					bestScore.Modifier = modifier
 				}
@@ -368,12 +395,16 @@ func (*UserStats) BulkInsert(userID int, statsMap map[int]*UserStatsRow) error {
 			best_score5_mod,
 			best_score6,
 			best_score6_mod,
+			best_score7,
+			best_score7_mod,
+			best_score8,
+			best_score8_mod,
 			average_score,
 			num_strikeouts
 		)
 		VALUES %s
 	`
-	numArgsPerRow := 15
+	numArgsPerRow := 19
 	valueArgs := make([]interface{}, 0, numArgsPerRow*len(statsMap))
 	for variantID, stats := range statsMap {
 		valueArgs = append(
@@ -391,6 +422,10 @@ func (*UserStats) BulkInsert(userID int, statsMap map[int]*UserStatsRow) error {
 			stats.BestScores[3].Modifier,
 			stats.BestScores[4].Score,
 			stats.BestScores[4].Modifier,
+			stats.BestScores[5].Score,
+			stats.BestScores[5].Modifier,
+			stats.BestScores[6].Score,
+			stats.BestScores[6].Modifier,
 			stats.AverageScore,
 			stats.NumStrikeouts,
 		)
@@ -421,5 +456,8 @@ func fillBestScores(bestScores []*BestScore) {
 		if modifier.HasFlag(ScoreModifierAllOrNothing) {
 			bestScores[i].AllOrNothing = true
 		}
+		if modifier.HasFlag(ScoreModifierCustomRules) {
+			bestScores[i].CustomRules = true
+		}
 	}
 }
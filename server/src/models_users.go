@@ -146,6 +146,25 @@ func (*Users) Update(userID int, lastIP string) error {
 	return err
 }
 
+func (*Users) IsBot(userID int) (bool, error) {
+	var bot bool
+	err := db.QueryRow(context.Background(), `
+		SELECT bot
+		FROM users
+		WHERE id = $1
+	`, userID).Scan(&bot)
+	return bot, err
+}
+
+func (*Users) SetBot(userID int, bot bool) error {
+	_, err := db.Exec(context.Background(), `
+		UPDATE users
+		SET bot = $1
+		WHERE id = $2
+	`, bot, userID)
+	return err
+}
+
 // Legacy function; delete this when all users have logged in or in 2022, whichever comes first
 func (*Users) UpdatePassword(userID int, passwordHash string) error {
 	_, err := db.Exec(context.Background(), `
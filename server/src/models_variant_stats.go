@@ -40,6 +40,8 @@ func (*VariantStats) Get(variantID int) (VariantStatsRow, error) {
 			best_score4,
 			best_score5,
 			best_score6,
+			best_score7,
+			best_score8,
 			num_max_scores,
 			average_score,
 			num_strikeouts
@@ -52,6 +54,8 @@ func (*VariantStats) Get(variantID int) (VariantStatsRow, error) {
 		&stats.BestScores[2].Score, // 4-player
 		&stats.BestScores[3].Score, // 5-player
 		&stats.BestScores[4].Score, // 6-player
+		&stats.BestScores[5].Score, // 7-player
+		&stats.BestScores[6].Score, // 8-player
 		&stats.NumMaxScores,
 		&stats.AverageScore,
 		&stats.NumStrikeouts,
@@ -77,6 +81,8 @@ func (*VariantStats) GetAll() (map[int]VariantStatsRow, error) {
 			best_score4,
 			best_score5,
 			best_score6,
+			best_score7,
+			best_score8,
 			num_max_scores,
 			average_score,
 			num_strikeouts
@@ -100,6 +106,8 @@ func (*VariantStats) GetAll() (map[int]VariantStatsRow, error) {
 			&stats.BestScores[2].Score, // 4-player
 			&stats.BestScores[3].Score, // 5-player
 			&stats.BestScores[4].Score, // 6-player
+			&stats.BestScores[5].Score, // 7-player
+			&stats.BestScores[6].Score, // 8-player
 			&stats.NumMaxScores,
 			&stats.AverageScore,
 			&stats.NumStrikeouts,
@@ -119,9 +127,11 @@ func (*VariantStats) GetAll() (map[int]VariantStatsRow, error) {
 }
 
 func (*VariantStats) Update(variantID int, maxScore int, stats VariantStatsRow) error {
-	// Validate that the BestScores slice contains 5 entries
-	if len(stats.BestScores) != 5 {
-		return errors.New("BestScores does not contain 5 entries (for 2 to 6 players)")
+	// Validate that the BestScores slice contains an entry for every player count
+	if len(stats.BestScores) != NumBestScores {
+		return errors.New("BestScores does not contain " + strconv.Itoa(NumBestScores) + " " +
+			"entries (for " + strconv.Itoa(MinPlayers) + " to " + strconv.Itoa(MaxPlayers) +
+			" players)")
 	}
 
 	// First, check to see if there is a row in the table for this variant already
@@ -164,12 +174,15 @@ func (*VariantStats) Update(variantID int, maxScore int, stats VariantStatsRow)
 				best_score4 = $4,
 				best_score5 = $5,
 				best_score6 = $6,
+				best_score7 = $7,
+				best_score8 = $8,
 				num_max_scores = (
 					SELECT COUNT(id)
 					FROM games
 					WHERE variant_id = $1
-						AND score = $7
+						AND score = $9
 						AND speedrun = FALSE
+						AND num_undos = 0
 				),
 				average_score = (
 					/*
@@ -197,6 +210,8 @@ func (*VariantStats) Update(variantID int, maxScore int, stats VariantStatsRow)
 		stats.BestScores[2].Score, // 4-player
 		stats.BestScores[3].Score, // 5-player
 		stats.BestScores[4].Score, // 6-player
+		stats.BestScores[5].Score, // 7-player
+		stats.BestScores[6].Score, // 8-player
 		maxScore,                  // num_max_scores
 	)
 	return err
@@ -223,9 +238,9 @@ func (vs *VariantStats) UpdateAll(highestVariantID int, maxScores []int) error {
 			continue
 		}
 
-		// Update scores for players 2 through 6
+		// Update scores for every player count
 		stats := NewVariantStatsRow()
-		for numPlayers := 2; numPlayers <= 6; numPlayers++ {
+		for numPlayers := MinPlayers; numPlayers <= MaxPlayers; numPlayers++ {
 			overallBestScore := 0
 
 			// Get the score for this player count (using a modifier of 0)
@@ -244,6 +259,11 @@ func (vs *VariantStats) UpdateAll(highestVariantID int, maxScores []int) error {
 					AND games.one_extra_card = FALSE
 					AND games.one_less_card = FALSE
 					AND games.all_or_nothing = FALSE
+					AND games.starting_clue_tokens = 0
+					AND games.max_clue_tokens = 0
+					AND games.strike_limit = 0
+					AND games.hand_size = 0
+					AND games.num_undos = 0
 			`, variantID, numPlayers).Scan(&bestScore); err != nil {
 				return err
 			}
@@ -252,7 +272,7 @@ func (vs *VariantStats) UpdateAll(highestVariantID int, maxScores []int) error {
 				overallBestScore = bestScore
 			}
 
-			i := numPlayers - 2
+			i := numPlayers - MinPlayers
 			stats.BestScores[i].Score = overallBestScore
 		}
 
@@ -1,7 +1,13 @@
 package main
 
+import (
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
 // Options are things that are specified about the game upon table creation (before the game starts)
 // All of these are stored in the database as columns of the "games" table
+// (except for the spectator delay and the vote requirement,
+// which only matter while the game is ongoing)
 // A pointer to these options is copied into the Game struct when the game starts for convenience
 type Options struct {
 	NumPlayers int `json:"numPlayers"`
@@ -11,7 +17,10 @@ type Options struct {
 	VariantName           string `json:"variantName"`
 	Timed                 bool   `json:"timed"`
 	TimeBase              int    `json:"timeBase"`
-	TimePerTurn           int    `json:"timePerTurn"`
+	TimePerTurn           int    `json:"timePerTurn"` // The increment, delay, or byo-yomi period
+	TimeControl           string `json:"timeControl"` // See the "TimeControl" constants
+	ByoYomiPeriods        int    `json:"byoYomiPeriods"`
+	TeamClock             bool   `json:"teamClock"`
 	Speedrun              bool   `json:"speedrun"`
 	CardCycle             bool   `json:"cardCycle"`
 	DeckPlays             bool   `json:"deckPlays"`
@@ -20,6 +29,26 @@ type Options struct {
 	OneLessCard           bool   `json:"oneLessCard"`
 	AllOrNothing          bool   `json:"allOrNothing"`
 	DetrimentalCharacters bool   `json:"detrimentalCharacters"`
+
+	// Custom rules
+	// (a value of 0 means that the normal rules are used)
+	StartingClueTokens int `json:"startingClueTokens"`
+	MaxClueTokens      int `json:"maxClueTokens"`
+	StrikeLimit        int `json:"strikeLimit"`
+	HandSize           int `json:"handSize"`
+
+	// Correspondence games are played over the course of days
+	// While they are ongoing, they are stored in the database instead of only in memory
+	Correspondence         bool `json:"correspondence"`
+	CorrespondenceDeadline int  `json:"correspondenceDeadline"` // In hours per move
+
+	// Tournament games can be shown to spectators after a delay (see "spectator_delay.go")
+	// Only one of these can be used at a time
+	SpectatorDelayTurns   int `json:"spectatorDelayTurns"`
+	SpectatorDelaySeconds int `json:"spectatorDelaySeconds"`
+
+	// Players must vote to terminate, pause, unpause, or restart the game (see "table_vote.go")
+	VoteRequired bool `json:"voteRequired"`
 }
 
 // ExtraOptions are extra specifications for the game; they are not recorded in the database
@@ -46,6 +75,7 @@ type ExtraOptions struct {
 	SetSeedSuffix string // Parsed from the game name for "!seed" games
 	SetReplay     bool   // True during "!replay" games
 	SetReplayTurn int    // Parsed from the game name for "!replay" games
+	PuzzleID      int    // Puzzles are "!replay" games that only last for one move (see "puzzle.go")
 }
 
 // To minimize JSON output, we need to use pointers to each option instead of the normal type
@@ -55,6 +85,9 @@ type OptionsJSON struct {
 	Timed                 *bool   `json:"timed,omitempty"`
 	TimeBase              *int    `json:"timeBase,omitempty"`
 	TimePerTurn           *int    `json:"timePerTurn,omitempty"`
+	TimeControl           *string `json:"timeControl,omitempty"`
+	ByoYomiPeriods        *int    `json:"byoYomiPeriods,omitempty"`
+	TeamClock             *bool   `json:"teamClock,omitempty"`
 	Speedrun              *bool   `json:"speedrun,omitempty"`
 	CardCycle             *bool   `json:"cardCycle,omitempty"`
 	DeckPlays             *bool   `json:"deckPlays,omitempty"`
@@ -63,6 +96,13 @@ type OptionsJSON struct {
 	OneLessCard           *bool   `json:"oneLessCard,omitempty"`
 	AllOrNothing          *bool   `json:"allOrNothing,omitempty"`
 	DetrimentalCharacters *bool   `json:"detrimentalCharacters,omitempty"`
+	StartingClueTokens    *int    `json:"startingClueTokens,omitempty"`
+	MaxClueTokens         *int    `json:"maxClueTokens,omitempty"`
+	StrikeLimit           *int    `json:"strikeLimit,omitempty"`
+	HandSize              *int    `json:"handSize,omitempty"`
+
+	Correspondence         *bool `json:"correspondence,omitempty"`
+	CorrespondenceDeadline *int  `json:"correspondenceDeadline,omitempty"`
 }
 
 func NewOptions() *Options {
@@ -74,6 +114,9 @@ func NewOptions() *Options {
 		Timed:                 false,
 		TimeBase:              0,
 		TimePerTurn:           0,
+		TimeControl:           TimeControlFischer,
+		ByoYomiPeriods:        0,
+		TeamClock:             false,
 		Speedrun:              false,
 		CardCycle:             false,
 		DeckPlays:             false,
@@ -82,6 +125,18 @@ func NewOptions() *Options {
 		OneLessCard:           false,
 		AllOrNothing:          false,
 		DetrimentalCharacters: false,
+		StartingClueTokens:    0,
+		MaxClueTokens:         0,
+		StrikeLimit:           0,
+		HandSize:              0,
+
+		Correspondence:         false,
+		CorrespondenceDeadline: 0,
+
+		SpectatorDelayTurns:   0,
+		SpectatorDelaySeconds: 0,
+
+		VoteRequired: false,
 	}
 }
 
@@ -105,6 +160,27 @@ func (o *Options) GetModifier() Bitmask {
 	if o.AllOrNothing {
 		modifier.AddFlag(ScoreModifierAllOrNothing)
 	}
+	if o.EngineOptions().HasCustomRules(o.NumPlayers) {
+		modifier.AddFlag(ScoreModifierCustomRules)
+	}
 
 	return modifier
 }
+
+// EngineOptions returns the subset of the options that affect the rules of the game
+func (o *Options) EngineOptions() *engine.Options {
+	return &engine.Options{
+		Speedrun:              o.Speedrun,
+		CardCycle:             o.CardCycle,
+		DeckPlays:             o.DeckPlays,
+		EmptyClues:            o.EmptyClues,
+		OneExtraCard:          o.OneExtraCard,
+		OneLessCard:           o.OneLessCard,
+		AllOrNothing:          o.AllOrNothing,
+		DetrimentalCharacters: o.DetrimentalCharacters,
+		StartingClueTokens:    o.StartingClueTokens,
+		MaxClueTokens:         o.MaxClueTokens,
+		StrikeLimit:           o.StrikeLimit,
+		HandSize:              o.HandSize,
+	}
+}
@@ -10,11 +10,18 @@ import (
 type Player struct {
 	UserID int // This is equal to the database ID for the user
 	Name   string
+	// The user ID of the player that started the game in this seat,
+	// if someone else took over the seat in the middle of the game (see "substitution.go")
+	OriginalUserID int
 	// The user session corresponding to the player is copied here for convenience
 	// Even if the user disconnects, the orphaned session will remain,
 	// and it is safe to manually perform actions on their behalf with the orphaned session
-	Session   *Session `json:"-"` // Skip when serializing
-	Present   bool
+	Session *Session `json:"-"` // Skip when serializing
+	Present bool
+	// Bots can either connect through the WebSocket like any other user
+	// or run inside of the server (in which case "BotPlayer" will be set)
+	Bot       bool
+	BotPlayer BotPlayer `json:"-"` // This is restored in the "restoreTables()" function
 	Stats     *PregameStats
 	Typing    bool
 	LastTyped time.Time
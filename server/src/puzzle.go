@@ -0,0 +1,236 @@
+// This file contains the functions for puzzles
+// A puzzle is a point in a game from the database where there is a clear best move
+// Puzzles are attempted solo on a "!replay" table that is emulated up to the turn of the puzzle
+// (the other seats are filled with fake players)
+// The first move that the player makes is graded against the accepted answers instead of being
+// performed, and then the table is deleted
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+type PuzzleResultMessage struct {
+	PuzzleID int           `json:"puzzleID"`
+	Correct  bool          `json:"correct"`
+	Answers  []*GameAction `json:"answers"`
+	// So that the player can look at the rest of the game
+	GameID int `json:"gameID"`
+	Turn   int `json:"turn"`
+}
+
+// getPuzzleAnswers validates a puzzle that is being created from a game in the database
+// "answersText" contains the accepted moves (separated by newlines or semicolons),
+// in the same text notation that is used for importing games (e.g. "clues Bob red; plays slot 2")
+// It returns the index of the player whose turn it is and the parsed answers
+func getPuzzleAnswers(gameID int, turn int, answersText string) (int, []*GameAction, error) {
+	var gameJSON *GameJSON
+	if v, err := getGameJSON(gameID); err != nil {
+		return -1, nil, err
+	} else {
+		gameJSON = v
+	}
+
+	// The turn must be an actual move in the game (and not the end of the game)
+	if turn < 0 || turn >= len(gameJSON.Actions) ||
+		gameJSON.Actions[turn].Type == ActionTypeEndGame {
+
+		return -1, nil, errors.New("game " + strconv.Itoa(gameID) + " does not have a move on " +
+			"turn " + strconv.Itoa(turn+1))
+	}
+
+	var state *engine.State
+	if v, err := gameJSON.GetInitialState(); err != nil {
+		return -1, nil, err
+	} else {
+		state = v
+	}
+	for i, a := range gameJSON.Actions[:turn] {
+		if v, _, err := state.Apply(a); err != nil {
+			return -1, nil, errors.New("failed to apply action " + strconv.Itoa(i) + ": " +
+				err.Error())
+		} else {
+			state = v
+		}
+	}
+	name := state.Players[state.ActivePlayerIndex].Name
+
+	answers := make([]*GameAction, 0)
+	lines := strings.FieldsFunc(answersText, func(r rune) bool {
+		return r == '\n' || r == ';'
+	})
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var a *GameAction
+		if v, err := parseGameTextMove(state, gameJSON, name, line); err != nil {
+			return -1, nil, errors.New("\"" + line + "\" is not a valid answer: " + err.Error())
+		} else {
+			a = v
+		}
+		if a.Type == ActionTypeEndGame {
+			return -1, nil, errors.New("\"" + line + "\" is not a valid answer")
+		}
+		if _, _, err := state.Apply(a); err != nil {
+			return -1, nil, errors.New("\"" + line + "\" is not a legal move: " + err.Error())
+		}
+		answers = append(answers, a)
+	}
+	if len(answers) == 0 {
+		return -1, nil, errors.New("there must be at least one answer")
+	}
+
+	return state.ActivePlayerIndex, answers, nil
+}
+
+// DisplayTurn returns the turn of the puzzle in the same way that it is shown in the game
+// (starting at 1)
+func (puzzle *Puzzle) DisplayTurn() int {
+	return puzzle.Turn + 1
+}
+
+// isPuzzleAnswer returns whether or not an action matches one of the accepted answers
+func isPuzzleAnswer(a *GameAction, answers []*GameAction) bool {
+	for _, answer := range answers {
+		if a.Type == answer.Type && a.Target == answer.Target && a.Value == answer.Value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// puzzleMove is called when the player makes their move in a puzzle
+// The table lock is assumed to be acquired in this function
+func puzzleMove(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+	a := engine.NewAction(d.Type, d.Target, d.Value)
+
+	// Illegal moves do not count as an attempt
+	if _, _, err := g.State.Apply(a); err != nil {
+		s.Warning(err.Error())
+		return
+	}
+
+	var puzzle *Puzzle
+	if v, exists, err := models.Puzzles.Get(t.ExtraOptions.PuzzleID); err != nil {
+		logger.Error("Failed to get puzzle " + strconv.Itoa(t.ExtraOptions.PuzzleID) + ": " +
+			err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else if !exists {
+		s.Warning("This puzzle no longer exists.")
+		t.EndPuzzle(ctx, d)
+		return
+	} else {
+		puzzle = v
+	}
+
+	correct := isPuzzleAnswer(a, puzzle.Answers)
+	if err := models.UserPuzzles.RecordAttempt(s.UserID, puzzle.ID, correct); err != nil {
+		logger.Error("Failed to record the attempt of user \"" + s.Username + "\" " +
+			"on puzzle " + strconv.Itoa(puzzle.ID) + ": " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	}
+
+	result := "failed"
+	if correct {
+		result = "solved"
+	}
+	logger.Info(t.GetName() + "User \"" + s.Username + "\" " + result + " puzzle #" +
+		strconv.Itoa(puzzle.ID) + ".")
+
+	s.Emit("puzzleResult", &PuzzleResultMessage{
+		PuzzleID: puzzle.ID,
+		Correct:  correct,
+		Answers:  puzzle.Answers,
+		GameID:   puzzle.GameID,
+		Turn:     puzzle.Turn,
+	})
+
+	// The result must be worked out before the table is deleted
+	msg := puzzleResultMsg(g.State, puzzle, correct)
+	t.EndPuzzle(ctx, d)
+
+	// Also send the result as a warning so that it is shown in the lobby
+	s.Warning(msg)
+}
+
+// puzzleResultMsg describes the result of a puzzle attempt to the player,
+// including the accepted answers if they did not solve it
+func puzzleResultMsg(state *engine.State, puzzle *Puzzle, correct bool) string {
+	var msg string
+	if correct {
+		msg = "You solved puzzle #" + strconv.Itoa(puzzle.ID) + "!"
+	} else {
+		// The player names are only needed to describe clues
+		playerNames := make([]string, 0)
+		for _, p := range state.Players {
+			playerNames = append(playerNames, p.Name)
+		}
+		gameJSON := &GameJSON{ // nolint: exhaustivestruct
+			Players: playerNames,
+		}
+
+		answers := make([]string, 0)
+		for _, a := range puzzle.Answers {
+			if v, err := gameTextActionLine(state, gameJSON, a); err != nil {
+				logger.Error("Failed to describe an answer of puzzle " + strconv.Itoa(puzzle.ID) +
+					": " + err.Error())
+			} else {
+				answers = append(answers, v)
+			}
+		}
+
+		msg = "That was not the answer to puzzle #" + strconv.Itoa(puzzle.ID) + "."
+		if len(answers) > 0 {
+			msg += " The accepted answers were: " + strings.Join(answers, "; ") + "."
+		}
+	}
+
+	return msg + " (You can see the rest of the game in the replay of game #" +
+		strconv.Itoa(puzzle.GameID) + ", starting on turn " + strconv.Itoa(puzzle.DisplayTurn()) +
+		".)"
+}
+
+// EndPuzzle deletes a puzzle table and sends the player back to the lobby
+// (puzzles are not recorded in the database like normal games)
+// The table lock is assumed to be acquired in this function
+func (t *Table) EndPuzzle(ctx context.Context, d *CommandData) {
+	// Since this is a function that changes a user's relationship to tables,
+	// we must acquires the tables lock to prevent race conditions
+	if !d.NoTablesLock {
+		tables.Lock(ctx)
+		defer tables.Unlock(ctx)
+	}
+
+	t.NotifyBoot()
+
+	// The other seats are filled with fake players, who are not in the user list
+	for _, p := range t.Players {
+		if p.UserID != t.OwnerID {
+			continue
+		}
+
+		tables.DeletePlaying(p.UserID, t.ID)
+		if p.Session != nil {
+			p.Session.SetStatus(StatusLobby)
+			p.Session.SetTableID(uint64(0))
+			notifyAllUser(p.Session)
+		}
+	}
+
+	deleteTable(t)
+	logger.Info("Ended puzzle table #" + strconv.FormatUint(t.ID, 10) + ".")
+}
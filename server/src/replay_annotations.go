@@ -0,0 +1,131 @@
+// This file contains the functions for annotations,
+// which are the things that happen in a shared replay of a game from the database
+// They are stored so that a review session can be reopened later on
+
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	ReplayAnnotationTypeChat          = "chat"
+	ReplayAnnotationTypeArrow         = "arrow"
+	ReplayAnnotationTypeEfficiencyMod = "efficiencyMod"
+	ReplayAnnotationTypeHypothetical  = "hypothetical"
+)
+
+type ReplayAnnotation struct {
+	// The shared replay segment that the annotation was made on
+	Turn     int    `json:"turn"`
+	Type     string `json:"type"`
+	Username string `json:"username"`
+	// An arrow is the card order, an efficiency modifier is a number,
+	// and a hypothetical is the JSON of all of its branches
+	Value    string    `json:"value"`
+	Datetime time.Time `json:"datetime"`
+}
+
+// SaveReplayAnnotation writes an annotation to the database if this is a shared replay of a game
+// from the database
+// The table lock is assumed to be acquired in this function
+func (t *Table) SaveReplayAnnotation(s *Session, turn int, annotationType string, value string) {
+	// Local variables
+	g := t.Game
+
+	if !t.Replay || !t.Visible || t.ExtraOptions.DatabaseID <= 0 || s == nil {
+		return
+	}
+
+	if err := models.ReplayAnnotations.Insert(
+		t.ExtraOptions.DatabaseID,
+		turn,
+		s.UserID,
+		annotationType,
+		value,
+	); err != nil {
+		logger.Error("Failed to insert a replay annotation for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		return
+	}
+
+	g.ReplayAnnotations = append(g.ReplayAnnotations, &ReplayAnnotation{
+		Turn:     turn,
+		Type:     annotationType,
+		Username: s.Username,
+		Value:    value,
+		Datetime: time.Now(),
+	})
+}
+
+// SaveReplayHypothetical saves the current hypothetical (if any) as an annotation on the segment
+// that it started from
+// The table lock is assumed to be acquired in this function
+func (t *Table) SaveReplayHypothetical(s *Session) {
+	// Local variables
+	g := t.Game
+
+	if !g.Hypothetical {
+		return
+	}
+
+	// Do not bother saving a hypothetical where nothing happened
+	empty := true
+	for _, branch := range g.HypoBranches {
+		if len(branch.Actions) > 0 {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return
+	}
+
+	var branchesJSON []byte
+	if v, err := json.Marshal(g.HypoBranches); err != nil {
+		logger.Error(t.GetName() + "Failed to marshal the hypothetical branches: " + err.Error())
+		return
+	} else {
+		branchesJSON = v
+	}
+
+	t.SaveReplayAnnotation(
+		s,
+		g.GetHypoBranch().Segment,
+		ReplayAnnotationTypeHypothetical,
+		string(branchesJSON),
+	)
+}
+
+// loadReplayAnnotations gets the annotations from all of the previous shared replays of this game
+// The table lock is assumed to be acquired in this function
+func (t *Table) loadReplayAnnotations() bool {
+	// Local variables
+	g := t.Game
+
+	if v, err := models.ReplayAnnotations.GetAll(t.ExtraOptions.DatabaseID); err != nil {
+		logger.Error("Failed to get the replay annotations for game " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID) + ": " + err.Error())
+		return false
+	} else {
+		g.ReplayAnnotations = v
+	}
+
+	// Restore the efficiency modifier from the last time that it was changed
+	latest := time.Time{}
+	for _, annotation := range g.ReplayAnnotations {
+		if annotation.Type != ReplayAnnotationTypeEfficiencyMod ||
+			annotation.Datetime.Before(latest) {
+
+			continue
+		}
+		if v, err := strconv.Atoi(annotation.Value); err == nil {
+			g.EfficiencyMod = v
+			latest = annotation.Datetime
+		}
+	}
+
+	return true
+}
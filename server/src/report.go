@@ -0,0 +1,118 @@
+// This file contains the functions for reports
+// Players can report other players for abusive chat or griefing with the "/report" command
+// The most recent chat messages in the room are saved along with the report,
+// so that the moderators can see what happened
+// Moderators work through the queue of reports with the localhost admin endpoints
+// (see "http_localhost_report.go")
+
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ReportActionNone = "none"
+	ReportActionWarn = "warn"
+	ReportActionMute = "mute"
+	ReportActionBan  = "ban"
+
+	// The number of chat messages that are saved along with a report
+	ReportChatLength = 30
+)
+
+// chatReport is a private chat command, so the reported user does not see the report
+// (see "chatPrivateCommand()")
+func chatReport(ctx context.Context, s *Session, d *CommandData) {
+	if len(d.Args) < 2 {
+		msg := "The format of the /report command is: /report [username] [reason]"
+		chatServerSendPM(s, msg, d.Room)
+		return
+	}
+
+	commandReport(ctx, s, &CommandData{ // nolint: exhaustivestruct
+		Name:         d.Args[0],
+		Msg:          strings.Join(d.Args[1:], " "),
+		Room:         d.Room,
+		NoTableLock:  d.NoTableLock,
+		NoTablesLock: d.NoTablesLock,
+	})
+}
+
+// getReportChatFromTable returns the most recent chat messages at a table
+// The table lock is assumed to be acquired in this function
+func getReportChatFromTable(t *Table) string {
+	start := len(t.Chat) - ReportChatLength
+	if start < 0 {
+		start = 0
+	}
+
+	lines := make([]string, 0)
+	for _, chatMsg := range t.Chat[start:] {
+		name := chatMsg.Username
+		if chatMsg.Server {
+			name = WebsiteName
+		}
+		lines = append(lines, formatReportChatLine(chatMsg.Datetime, name, chatMsg.Msg))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// getReportChatFromDatabase returns the most recent chat messages in the lobby
+func getReportChatFromDatabase(room string) (string, error) {
+	var rawMsgs []DBChatMessage
+	if v, err := models.ChatLog.Get(room, ReportChatLength); err != nil {
+		return "", err
+	} else {
+		rawMsgs = v
+	}
+
+	// The chat messages were queried from the database in order from newest to oldest
+	lines := make([]string, 0)
+	for i := len(rawMsgs) - 1; i >= 0; i-- {
+		rawMsg := rawMsgs[i]
+		name := rawMsg.Name
+		if rawMsg.DiscordName.Valid {
+			name = rawMsg.DiscordName.String
+		}
+		lines = append(lines, formatReportChatLine(rawMsg.Datetime, name, rawMsg.Message))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func formatReportChatLine(datetime time.Time, name string, msg string) string {
+	return "[" + datetime.UTC().Format("2006-01-02 15:04:05") + "] <" + name + "> " + msg
+}
+
+// notifyModerators logs a message and sends it to the moderator Discord channel
+// (if it is configured)
+func notifyModerators(msg string) {
+	logger.Info(msg)
+	if discordChannelModerators != "" {
+		discordSend(discordChannelModerators, "", msg)
+	}
+}
+
+// GetStatus returns "open", "claimed by [moderator]", or "resolved by [moderator]"
+func (report *Report) GetStatus() string {
+	if report.DatetimeResolved != nil {
+		return "resolved by " + report.ResolvedBy + " (" + report.Action + ")"
+	}
+	if report.DatetimeClaimed != nil {
+		return "claimed by " + report.ClaimedBy
+	}
+	return "open"
+}
+
+// String returns a one-line summary of the report for the localhost admin endpoints
+func (report *Report) String() string {
+	return "#" + strconv.Itoa(report.ID) + " - " +
+		report.DatetimeCreated.UTC().Format("2006-01-02 15:04 MST") + " - " +
+		"\"" + report.ReporterName + "\" reported \"" + report.ReportedName + "\" " +
+		"in #" + report.Room + " - " + report.Reason + " - " + report.GetStatus()
+}
@@ -0,0 +1,143 @@
+// This file contains the functions for sanctions, which are bans and mutes
+// A sanction can target an account, an IP, or both
+// Sanctions can be permanent or they can expire after a certain amount of time
+// They are issued and lifted by moderators with the localhost admin endpoints
+// (see "http_localhost_sanction.go")
+
+package main
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SanctionTypeBan  = "ban"
+	SanctionTypeMute = "mute"
+)
+
+// parseSanctionDuration parses durations like "30m", "12h", or "7d"
+// A duration of "permanent" (or a blank duration) returns 0
+func parseSanctionDuration(durationString string) (time.Duration, error) {
+	if durationString == "" || durationString == "permanent" {
+		return 0, nil
+	}
+
+	// The "time" package does not have a unit for days
+	if strings.HasSuffix(durationString, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(durationString, "d")); err != nil {
+			return 0, err
+		} else if days <= 0 {
+			return 0, errors.New("the number of days must be positive")
+		} else {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+
+	if duration, err := time.ParseDuration(durationString); err != nil {
+		return 0, err
+	} else if duration <= 0 {
+		return 0, errors.New("the duration must be positive")
+	} else {
+		return duration, nil
+	}
+}
+
+// GetDescription returns a description of the sanction that can be shown to the user
+// e.g. "You have been muted until 2020-10-30 15:04 UTC. (Reason: spamming)"
+func (sanction *Sanction) GetDescription() string {
+	msg := "You have been "
+	if sanction.Type == SanctionTypeBan {
+		msg += "banned"
+	} else {
+		msg += "muted"
+	}
+	if sanction.DatetimeExpires == nil {
+		msg += " permanently."
+	} else {
+		msg += " until " + formatSanctionDatetime(*sanction.DatetimeExpires) + "."
+	}
+	if sanction.Reason != "" {
+		msg += " (Reason: " + sanction.Reason + ")"
+	}
+
+	return msg
+}
+
+// String returns a one-line summary of the sanction for the localhost admin endpoints
+func (sanction *Sanction) String() string {
+	msg := "#" + strconv.Itoa(sanction.ID) + " - " + sanction.Type
+	if sanction.Username != "" {
+		msg += " - user \"" + sanction.Username + "\""
+	}
+	if sanction.IP != "" {
+		msg += " - IP \"" + sanction.IP + "\""
+	}
+	if sanction.Reason != "" {
+		msg += " - reason: " + sanction.Reason
+	}
+	issuedBy := sanction.IssuedBy
+	if issuedBy == "" {
+		issuedBy = "the server"
+	}
+	msg += " - issued by " + issuedBy + " on " + formatSanctionDatetime(sanction.DatetimeIssued)
+	if sanction.DatetimeExpires == nil {
+		msg += " - permanent"
+	} else {
+		msg += " - expires on " + formatSanctionDatetime(*sanction.DatetimeExpires)
+	}
+	if sanction.DatetimeLifted != nil {
+		msg += " - lifted by " + sanction.LiftedBy + " on " +
+			formatSanctionDatetime(*sanction.DatetimeLifted)
+		if sanction.LiftReason != "" {
+			msg += " (" + sanction.LiftReason + ")"
+		}
+	}
+
+	return msg
+}
+
+func formatSanctionDatetime(datetime time.Time) string {
+	return datetime.UTC().Format("2006-01-02 15:04 MST")
+}
+
+// getSessionIP returns the IP address of a WebSocket session
+// (fake sessions do not have an IP address)
+func getSessionIP(s *Session) string {
+	if s.ms == nil {
+		return ""
+	}
+
+	if v, _, err := net.SplitHostPort(s.ms.Request.RemoteAddr); err != nil {
+		logger.Error("Failed to parse the IP address from \"" + s.ms.Request.RemoteAddr + "\": " +
+			err.Error())
+		return ""
+	} else {
+		return v
+	}
+}
+
+// isMuted checks to see if the account or the IP of a user is muted
+// If so, it also sends them a warning
+// (this is checked on every chat message so that mutes take effect and expire immediately)
+func isMuted(s *Session) bool {
+	if s.FakeUser {
+		return false
+	}
+
+	ip := getSessionIP(s)
+	if sanction, muted, err := models.Sanctions.Check(SanctionTypeMute, s.UserID, ip); err != nil {
+		logger.Error("Failed to check to see if user \"" + s.Username + "\" is muted: " +
+			err.Error())
+		s.Error(DefaultErrorMsg)
+		return true
+	} else if muted {
+		s.Warning(sanction.GetDescription())
+		return true
+	}
+
+	return false
+}
@@ -23,7 +23,8 @@ func serializeTables() bool {
 		// deadlock
 
 		// Only serialize ongoing games
-		if t.Running && !t.Replay {
+		// (correspondence games are already stored in the database)
+		if t.Running && !t.Replay && !t.Options.Correspondence {
 			logger.Info("Serializing table: " + strconv.FormatUint(t.ID, 10))
 
 			// Several fields on the Table object and the Game object are set with `json:"-"` to prevent
@@ -114,6 +115,52 @@ func restoreTable(ctx context.Context, f os.FileInfo) bool {
 		logger.Fatal("Failed to unmarshal \"" + tablePath + "\": " + err.Error())
 		return false
 	}
+	restoreTableReferences(t)
+	g := t.Game
+
+	if g.Options.Timed {
+		// Give the current player some additional seconds to make up for the fact that they are
+		// forced to refresh
+		activePlayer := g.Players[g.State.ActivePlayerIndex]
+		activePlayer.Time += 20 * time.Second
+		// The time that the server was offline should not count against the current player
+		// (so the time that they took on this turn before the restart is forgiven as well)
+		g.DatetimeTurnBegin = time.Now()
+		if g.Options.TeamClock {
+			g.SyncTeamClock(activePlayer)
+		}
+
+		// Players will never run out of time on restored tables because the turn timer was never
+		// scheduled; manually do this
+		if !g.Paused {
+			g.StartTimer(ctx)
+		}
+	}
+
+	tables.Set(t.ID, t)
+	logger.Info(t.GetName() + "Restored table.")
+
+	if err := os.Remove(tablePath); err != nil {
+		logger.Fatal("Failed to delete \"" + tablePath + "\": " + err.Error())
+	}
+
+	// Restored tables will never be automatically terminated due to idleness because the
+	// "CheckIdle()" function was never initiated; manually do this
+	go t.CheckIdle(ctx)
+
+	// If it is a bot's turn, the bot will need to act
+	g.CheckBotTurn(ctx)
+
+	// The actions that are being held back from the spectators will need to be released
+	t.ReleaseSpectatorActions()
+
+	return true
+}
+
+// restoreTableReferences restores the fields of an unserialized table that could not be represented
+// in JSON
+// It is assumed that the tables mutex is locked when calling this function
+func restoreTableReferences(t *Table) {
 	t.Spectators = make([]*Spectator, 0)
 	t.KickedPlayers = make(map[int]struct{})
 	if t.ChatRead == nil {
@@ -126,9 +173,18 @@ func restoreTable(ctx context.Context, f os.FileInfo) bool {
 	g.Table = t
 	g.Options = t.Options
 	g.ExtraOptions = t.ExtraOptions
+	g.State.Variant = variants[g.Options.VariantName]
+	g.State.RelinkHands()
+	if g.InitialState != nil {
+		g.InitialState.Variant = g.State.Variant
+		g.InitialState.RelinkHands()
+	}
 	for _, gp := range g.Players {
 		gp.Game = g
 	}
+	g.SetState(g.State)
+	g.Timer = NewTurnTimer()
+	g.SpectatorTimer = NewTurnTimer()
 
 	// Restore the types of the actions
 	for i, a := range g.Actions {
@@ -140,33 +196,16 @@ func restoreTable(ctx context.Context, f os.FileInfo) bool {
 		// (they were presumably present and connected when the table serialization happened)
 		p.Present = false
 
+		// Bots that run inside of the server do not have to reconnect
+		if botConstructor, ok := botConstructors[p.Name]; ok && p.Bot {
+			p.Session = NewBotSession(p.UserID, p.Name)
+			p.Present = true
+			p.BotPlayer = botConstructor()
+		}
+
 		// Restore the player relationships
 		tables.AddPlaying(p.UserID, t.ID)
 	}
-
-	if g.Options.Timed {
-		// Give the current player some additional seconds to make up for the fact that they are
-		// forced to refresh
-		g.Players[g.ActivePlayerIndex].Time += 20 * time.Second
-
-		// Players will never run out of time on restored tables because the "CheckTimer()"
-		// function was never initiated; manually do this
-		activePlayer := g.Players[g.ActivePlayerIndex]
-		go g.CheckTimer(ctx, activePlayer.Time, g.Turn, g.PauseCount, activePlayer)
-	}
-
-	tables.Set(t.ID, t)
-	logger.Info(t.GetName() + "Restored table.")
-
-	if err := os.Remove(tablePath); err != nil {
-		logger.Fatal("Failed to delete \"" + tablePath + "\": " + err.Error())
-	}
-
-	// Restored tables will never be automatically terminated due to idleness because the
-	// "CheckIdle()" function was never initiated; manually do this
-	go t.CheckIdle(ctx)
-
-	return true
 }
 
 func restoreTableAction(t *Table, i int, a interface{}) {
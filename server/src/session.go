@@ -19,8 +19,9 @@ type Session struct {
 	SessionID uint64
 	UserID    int
 	Username  string
-	Muted     bool // Users are forcefully disconnected upon being muted, so this is static
+	Muted     bool // Only used to inform the client (mutes are checked on every chat message)
 	FakeUser  bool
+	Bot       bool // Corresponds to the "bot" column of the "users" table
 
 	// Dynamic data fields
 	// (they are updated as the user performs activities, so we need to use a mutex)
@@ -33,11 +34,16 @@ type SessionData struct {
 	TableID            uint64
 	Friends            map[int]struct{}
 	ReverseFriends     map[int]struct{}
+	Blocks             map[int]struct{}
+	ReverseBlocks      map[int]struct{}
 	Hyphenated         bool
 	Inactive           bool
 	RateLimitAllowance float64
 	RateLimitLastCheck time.Time
 	Banned             bool
+	Throttled          bool // See "throttle.go"
+	ThrottleBuckets    map[string]*RateLimitBucket
+	ThrottleWarnings   int
 }
 
 var (
@@ -55,17 +61,23 @@ func NewSession() *Session {
 		Username:  "[unknown]",
 		Muted:     false,
 		FakeUser:  false,
+		Bot:       false,
 
 		Data: &SessionData{
 			Status:             StatusLobby, // By default, new users are in the lobby
 			TableID:            uint64(0),   // 0 is used as a null value
 			Friends:            make(map[int]struct{}),
 			ReverseFriends:     make(map[int]struct{}),
+			Blocks:             make(map[int]struct{}),
+			ReverseBlocks:      make(map[int]struct{}),
 			Hyphenated:         false,
 			Inactive:           false,
 			RateLimitAllowance: RateLimitRate,
 			RateLimitLastCheck: time.Now(),
 			Banned:             false,
+			Throttled:          false,
+			ThrottleBuckets:    make(map[string]*RateLimitBucket),
+			ThrottleWarnings:   0,
 		},
 		DataMutex: &deadlock.RWMutex{},
 	}
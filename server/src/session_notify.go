@@ -73,6 +73,8 @@ type TableMessage struct {
 	Timed             bool     `json:"timed"`
 	TimeBase          int      `json:"timeBase"`
 	TimePerTurn       int      `json:"timePerTurn"`
+	TimeControl       string   `json:"timeControl"`
+	Correspondence    bool     `json:"correspondence"`
 	SharedReplay      bool     `json:"sharedReplay"`
 	Progress          int      `json:"progress"`
 	Players           []string `json:"players"`
@@ -104,6 +106,8 @@ func makeTableMessage(s *Session, t *Table) *TableMessage {
 		Timed:             t.Options.Timed,
 		TimeBase:          t.Options.TimeBase,
 		TimePerTurn:       t.Options.TimePerTurn,
+		TimeControl:       t.Options.TimeControl,
+		Correspondence:    t.Options.Correspondence,
 		SharedReplay:      t.Replay,
 		Progress:          t.Progress,
 		Players:           players,
@@ -235,32 +239,101 @@ func (s *Session) NotifyTime(t *Table) {
 	g := t.Game
 
 	// Create the clock message
+	// (we could be sending the message in the middle of someone's turn, so account for this)
+	// JavaScript expects time in milliseconds
 	times := make([]int64, 0)
+	periods := make([]int, 0)
+	var delay int64
 	for i, p := range g.Players {
-		// We could be sending the message in the middle of someone's turn, so account for this
-		timeLeft := p.Time
-		if g.ActivePlayerIndex == i {
-			elapsedTime := time.Since(g.DatetimeTurnBegin)
-			timeLeft -= elapsedTime
+		timeLeft, periodsLeft, delayLeft := g.GetClock(p)
+		times = append(times, int64(timeLeft/time.Millisecond))
+		periods = append(periods, periodsLeft)
+		if g.State.ActivePlayerIndex == i {
+			delay = int64(delayLeft / time.Millisecond)
 		}
-
-		// JavaScript expects time in milliseconds
-		milliseconds := int64(timeLeft / time.Millisecond)
-		times = append(times, milliseconds)
 	}
-	timeTaken := int64(time.Since(g.DatetimeTurnBegin) / time.Millisecond)
+	timeTaken := int64(g.GetTurnTimeTaken() / time.Millisecond)
 
 	type ClockMessage struct {
 		TableID           uint64  `json:"tableID"`
 		Times             []int64 `json:"times"`
 		ActivePlayerIndex int     `json:"activePlayerIndex"`
 		TimeTaken         int64   `json:"timeTaken"`
+		TimeControl       string  `json:"timeControl"`
+		TeamClock         bool    `json:"teamClock"`
+		// The number of byo-yomi periods that each player has left
+		Periods []int `json:"periods"`
+		// The delay that is left before the clock of the active player starts to run
+		Delay int64 `json:"delay"`
 	}
 	s.Emit("clock", &ClockMessage{
 		TableID:           t.ID,
 		Times:             times,
-		ActivePlayerIndex: g.ActivePlayerIndex,
+		ActivePlayerIndex: g.State.ActivePlayerIndex,
 		TimeTaken:         timeTaken,
+		TimeControl:       t.Options.TimeControl,
+		TeamClock:         t.Options.TeamClock,
+		Periods:           periods,
+		Delay:             delay,
+	})
+}
+
+// NotifyHypothetical sends the entire current branch of the hypothetical
+// (e.g. when someone joins a shared replay or the leader switches to a different branch)
+func (s *Session) NotifyHypothetical(t *Table) {
+	g := t.Game
+
+	var actions []string
+	if v, err := g.GetHypoActions(); err != nil {
+		logger.Error(t.GetName() + "Failed to get the hypothetical actions: " + err.Error())
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		actions = v
+	}
+
+	type HypotheticalMessage struct {
+		TableID        uint64               `json:"tableID"`
+		ShowDrawnCards bool                 `json:"showDrawnCards"`
+		Actions        []string             `json:"actions"`
+		Branch         string               `json:"branch"`
+		Branches       []*HypoBranchMessage `json:"branches"`
+	}
+	s.Emit("hypothetical", &HypotheticalMessage{
+		TableID:        t.ID,
+		ShowDrawnCards: g.HypoShowDrawnCards,
+		Actions:        actions,
+		Branch:         g.HypoCurrentBranch,
+		Branches:       g.GetHypoBranchList(),
+	})
+}
+
+// NotifyReplayAnnotations sends the annotations that have been saved from shared replays of this
+// game
+func (s *Session) NotifyReplayAnnotations(t *Table) {
+	g := t.Game
+
+	type ReplayAnnotationsMessage struct {
+		TableID     uint64              `json:"tableID"`
+		Annotations []*ReplayAnnotation `json:"annotations"`
+	}
+	s.Emit("replayAnnotations", &ReplayAnnotationsMessage{
+		TableID:     t.ID,
+		Annotations: g.ReplayAnnotations,
+	})
+}
+
+// NotifyGameAnalysis sends the statistics that were generated at the end of the game
+func (s *Session) NotifyGameAnalysis(t *Table) {
+	g := t.Game
+
+	type GameAnalysisMessage struct {
+		TableID  uint64        `json:"tableID"`
+		Analysis *GameAnalysis `json:"analysis"`
+	}
+	s.Emit("gameAnalysis", &GameAnalysisMessage{
+		TableID:  t.ID,
+		Analysis: g.Analysis,
 	})
 }
 
@@ -312,7 +385,7 @@ func (s *Session) NotifyCardIdentities(t *Table) {
 	}
 	s.Emit("cardIdentities", &CardIdentitiesMessage{
 		TableID:        t.ID,
-		CardIdentities: g.CardIdentities,
+		CardIdentities: g.State.CardIdentities,
 	})
 }
 
@@ -338,9 +411,12 @@ func (s *Session) NotifyNoteList(t *Table, shadowingPlayerIndex int) {
 	}
 
 	// Get the notes from all the players & spectators
+	// (the notes of the players are hidden while there is a spectator delay)
 	notes := make([]NoteList, 0)
 	for _, p := range g.Players {
-		if shadowingPlayerIndex == -1 || shadowingPlayerIndex == p.Index {
+		if !t.HasSpectatorDelay() &&
+			(shadowingPlayerIndex == -1 || shadowingPlayerIndex == p.Index) {
+
 			notes = append(notes, NoteList{
 				Name:  p.Name,
 				Notes: p.Notes,
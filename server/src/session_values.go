@@ -73,6 +73,93 @@ func (s *Session) ReverseFriends() map[int]struct{} {
 	return s.Data.ReverseFriends
 }
 
+// Blocks returns the users that this user has blocked
+// The map is replaced instead of modified when a block is added or removed (see "AddBlock()"),
+// so it is safe to read from it after the lock is released, but it must never be written to
+func (s *Session) Blocks() map[int]struct{} {
+	if s == nil {
+		logger.Error("The \"Blocks\" method was called for a nil session.")
+		return make(map[int]struct{})
+	}
+
+	s.DataMutex.RLock()
+	defer s.DataMutex.RUnlock()
+	return s.Data.Blocks
+}
+
+// ReverseBlocks returns the users that have blocked this user
+// Like with the "Blocks()" method, the returned map must never be written to
+func (s *Session) ReverseBlocks() map[int]struct{} {
+	if s == nil {
+		logger.Error("The \"ReverseBlocks\" method was called for a nil session.")
+		return make(map[int]struct{})
+	}
+
+	s.DataMutex.RLock()
+	defer s.DataMutex.RUnlock()
+	return s.Data.ReverseBlocks
+}
+
+func (s *Session) AddBlock(userID int) {
+	if s == nil {
+		logger.Error("The \"AddBlock\" method was called for a nil session.")
+		return
+	}
+
+	s.DataMutex.Lock()
+	s.Data.Blocks = copyBlockMap(s.Data.Blocks, userID, true)
+	s.DataMutex.Unlock()
+}
+
+func (s *Session) DeleteBlock(userID int) {
+	if s == nil {
+		logger.Error("The \"DeleteBlock\" method was called for a nil session.")
+		return
+	}
+
+	s.DataMutex.Lock()
+	s.Data.Blocks = copyBlockMap(s.Data.Blocks, userID, false)
+	s.DataMutex.Unlock()
+}
+
+func (s *Session) AddReverseBlock(userID int) {
+	if s == nil {
+		logger.Error("The \"AddReverseBlock\" method was called for a nil session.")
+		return
+	}
+
+	s.DataMutex.Lock()
+	s.Data.ReverseBlocks = copyBlockMap(s.Data.ReverseBlocks, userID, true)
+	s.DataMutex.Unlock()
+}
+
+func (s *Session) DeleteReverseBlock(userID int) {
+	if s == nil {
+		logger.Error("The \"DeleteReverseBlock\" method was called for a nil session.")
+		return
+	}
+
+	s.DataMutex.Lock()
+	s.Data.ReverseBlocks = copyBlockMap(s.Data.ReverseBlocks, userID, false)
+	s.DataMutex.Unlock()
+}
+
+// copyBlockMap returns a copy of the map with the user ID added or removed
+// Other goroutines may be reading from the old map without holding the session lock,
+// so it must not be modified in place
+func copyBlockMap(blockMap map[int]struct{}, userID int, add bool) map[int]struct{} {
+	newBlockMap := make(map[int]struct{}, len(blockMap)+1)
+	for k := range blockMap {
+		newBlockMap[k] = struct{}{}
+	}
+	if add {
+		newBlockMap[userID] = struct{}{}
+	} else {
+		delete(newBlockMap, userID)
+	}
+	return newBlockMap
+}
+
 func (s *Session) Hyphenated() bool {
 	if s == nil {
 		logger.Error("The \"Hyphenated\" method was called for a nil session.")
@@ -171,3 +258,39 @@ func (s *Session) Banned() bool {
 	defer s.DataMutex.RUnlock()
 	return s.Data.Banned
 }
+
+func (s *Session) SetBanned(banned bool) {
+	if s == nil {
+		logger.Error("The \"SetBanned\" method was called for a nil session.")
+		return
+	}
+
+	s.DataMutex.Lock()
+	s.Data.Banned = banned
+	s.DataMutex.Unlock()
+}
+
+func (s *Session) Throttled() bool {
+	if s == nil {
+		logger.Error("The \"Throttled\" method was called for a nil session.")
+		return false
+	}
+
+	s.DataMutex.RLock()
+	defer s.DataMutex.RUnlock()
+	return s.Data.Throttled
+}
+
+// SetThrottled also resets the throttle buckets and warnings
+func (s *Session) SetThrottled(throttled bool) {
+	if s == nil {
+		logger.Error("The \"SetThrottled\" method was called for a nil session.")
+		return
+	}
+
+	s.DataMutex.Lock()
+	s.Data.Throttled = throttled
+	s.Data.ThrottleBuckets = make(map[string]*RateLimitBucket)
+	s.Data.ThrottleWarnings = 0
+	s.DataMutex.Unlock()
+}
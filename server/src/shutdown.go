@@ -141,7 +141,7 @@ func terminateAllStartedTables(ctx context.Context) {
 	// It is assumed that the tables mutex is locked when calling this function
 	for _, t := range tables.GetList(false) {
 		t.Lock(ctx)
-		if t.Running && !t.Replay {
+		if t.Running && !t.Replay && !t.Options.Correspondence {
 			s := t.GetOwnerSession()
 			commandAction(ctx, s, &CommandData{ // nolint: exhaustivestruct
 				TableID:      t.ID,
@@ -162,7 +162,8 @@ func countActiveTables(ctx context.Context) int {
 	numTables := 0
 	for _, t := range tableList {
 		t.Lock(ctx)
-		if t.Running && !t.Replay {
+		// Correspondence games are stored in the database, so we do not need to wait for them
+		if t.Running && !t.Replay && !t.Options.Correspondence {
 			numTables++
 		}
 		t.Unlock(ctx)
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+const (
+	// The solver does a bounded search; this is the maximum number of states that it will visit
+	SolverMaxNodes = 100000
+)
+
+var (
+	// The solver can use a lot of CPU, so we only allow one search to happen at a time
+	solverMutex = &sync.Mutex{}
+)
+
+// solveGameJSON finds the best score that is achievable on the deck of a game (along with the
+// line that achieves it) if every player could see every card
+// The actions of the game are ignored
+// An error is only returned if the game is not valid
+func solveGameJSON(gameJSON *GameJSON) (*engine.Solution, error) {
+	// Validate the game
+	variantName := "No Variant"
+	if gameJSON.Options != nil && gameJSON.Options.Variant != nil {
+		variantName = *gameJSON.Options.Variant
+	}
+	variant, ok := variants[variantName]
+	if !ok {
+		return nil, errors.New("\"" + variantName + "\" is not a valid variant")
+	}
+	if len(gameJSON.Players) < MinPlayers || len(gameJSON.Players) > MaxPlayers {
+		return nil, errors.New("the number of players must be between " +
+			strconv.Itoa(MinPlayers) + " and " + strconv.Itoa(MaxPlayers))
+	}
+	if len(gameJSON.Deck) != variant.GetDeckSize() {
+		return nil, errors.New("the deck must have " + strconv.Itoa(variant.GetDeckSize()) +
+			" cards")
+	}
+	options := gameJSON.GetOptions()
+	if options.StartingPlayer < 0 || options.StartingPlayer >= len(gameJSON.Players) {
+		return nil, errors.New("the starting player is invalid")
+	}
+
+	// Recreate the state at the start of the game
+	// (this must be done in the same way as in the "tableStart()" function)
+	state := engine.NewState(variant, options.EngineOptions())
+	state.ActivePlayerIndex = options.StartingPlayer
+	for i, name := range gameJSON.Players {
+		p := state.AddPlayer(name)
+		if options.DetrimentalCharacters && i < len(gameJSON.Characters) {
+			p.Character = gameJSON.Characters[i].Name
+			p.CharacterMetadata = gameJSON.Characters[i].Metadata
+		}
+	}
+	state.InitDeck(gameJSON.Deck)
+	state.Deal()
+
+	return solveState(state, gameJSON.Seed, options), nil
+}
+
+// solveState finds the best score that is achievable from the provided state
+// The state must not be modified by anything else while the solver is running
+func solveState(state *engine.State, seed string, options *Options) *engine.Solution {
+	solverMutex.Lock()
+	solution := state.Solve(SolverMaxNodes)
+	solverMutex.Unlock()
+
+	// Record the result so that it can be shown on the seed page
+	// (other games on the same seed can have different options, so we only record the result for
+	// games with the default rules that are solved from the beginning)
+	if seed != "" &&
+		seed != "JSON" &&
+		state.Turn == 0 &&
+		options.StartingPlayer == 0 &&
+		*options.EngineOptions() == (engine.Options{}) { // nolint: exhaustivestruct
+
+		if err := models.Seeds.SetMaxAchievableScore(
+			seed,
+			solution.Score,
+			solution.Proven,
+		); err != nil {
+			logger.Error("Failed to set the max achievable score for seed \"" + seed + "\": " +
+				err.Error())
+		}
+	}
+
+	return solution
+}
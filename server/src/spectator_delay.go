@@ -0,0 +1,169 @@
+// This file contains the functions for the spectator delay,
+// which is an option for tournament games that are being streamed
+// The actions of the game are held back from the spectators until enough turns or enough time
+// has passed, so that a spectator cannot relay information to the players
+// Furthermore, spectators cannot shadow a player and the spectator chat is hidden from the
+// players until the game ends
+
+package main
+
+import (
+	"time"
+)
+
+const (
+	MaxSpectatorDelayTurns   = 20
+	MaxSpectatorDelaySeconds = 600 // 10 minutes
+)
+
+// DelayedAction records when an action happened,
+// so that we know when it can be sent to the spectators
+type DelayedAction struct {
+	Index    int // The index of the action in the "Actions" slice of the game
+	Turn     int
+	Datetime time.Time
+}
+
+// HasSpectatorDelay returns whether or not the spectators are currently being shown the game after
+// a delay (once the game is over, they can see everything)
+func (t *Table) HasSpectatorDelay() bool {
+	if t.Options.SpectatorDelayTurns == 0 && t.Options.SpectatorDelaySeconds == 0 {
+		return false
+	}
+
+	return t.Running && !t.Replay && t.Game.State.EndCondition == EndConditionInProgress
+}
+
+// QueueSpectatorAction holds back the last action of the game from the spectators
+// The table lock is assumed to be acquired in this function
+func (t *Table) QueueSpectatorAction() {
+	// Local variables
+	g := t.Game
+
+	g.DelayedActions = append(g.DelayedActions, &DelayedAction{
+		Index:    len(g.Actions) - 1,
+		Turn:     g.State.Turn,
+		Datetime: time.Now(),
+	})
+	t.ReleaseSpectatorActions()
+}
+
+// ReleaseSpectatorActions sends the spectators every action that is past the delay
+// If there are still actions left, it will run again when the next one is due
+// The table lock is assumed to be acquired in this function
+func (t *Table) ReleaseSpectatorActions() {
+	// Local variables
+	g := t.Game
+
+	for len(g.DelayedActions) > 0 && t.isSpectatorDelayOver(g.DelayedActions[0]) {
+		t.releaseSpectatorAction(g.DelayedActions[0])
+		g.DelayedActions = g.DelayedActions[1:]
+	}
+
+	if len(g.DelayedActions) > 0 && t.Options.SpectatorDelaySeconds > 0 {
+		delay := time.Duration(t.Options.SpectatorDelaySeconds) * time.Second
+		releaseTime := g.DelayedActions[0].Datetime.Add(delay)
+		g.SpectatorTimer.Schedule(time.Until(releaseTime), t.checkSpectatorDelay)
+	}
+}
+
+func (t *Table) isSpectatorDelayOver(delayedAction *DelayedAction) bool {
+	// Local variables
+	g := t.Game
+
+	if t.Options.SpectatorDelayTurns > 0 {
+		return g.State.Turn-delayedAction.Turn >= t.Options.SpectatorDelayTurns
+	}
+
+	delay := time.Duration(t.Options.SpectatorDelaySeconds) * time.Second
+	return time.Since(delayedAction.Datetime) >= delay
+}
+
+func (t *Table) releaseSpectatorAction(delayedAction *DelayedAction) {
+	// Local variables
+	g := t.Game
+
+	a := g.Actions[delayedAction.Index]
+	for _, sp := range t.Spectators {
+		sp.Session.NotifyGameAction(t, a)
+	}
+}
+
+// GetSpectatorActions returns the actions of the game that the spectators are allowed to see
+func (t *Table) GetSpectatorActions() []interface{} {
+	// Local variables
+	g := t.Game
+
+	if len(g.DelayedActions) == 0 {
+		return g.Actions
+	}
+
+	return g.Actions[:g.DelayedActions[0].Index]
+}
+
+// checkSpectatorDelay is called by the spectator timer when the next action is due
+func (t *Table) checkSpectatorDelay() {
+	ctx := NewMiscContext("checkSpectatorDelay")
+
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(ctx, nil, t.ID, false, true)
+	if !exists || t != t2 {
+		return
+	}
+	t.Lock(ctx)
+	defer t.Unlock(ctx)
+
+	// Check to see if the game ended in the meantime
+	// (in which case the spectators have already been sent everything)
+	if !t.HasSpectatorDelay() {
+		return
+	}
+
+	t.ReleaseSpectatorActions()
+}
+
+// EndSpectatorDelay sends the spectators all of the actions that were held back from them and
+// shows the spectator chat to the players
+// It is called when the game ends
+// The table lock is assumed to be acquired in this function
+func (t *Table) EndSpectatorDelay() {
+	// Local variables
+	g := t.Game
+
+	if t.Options.SpectatorDelayTurns == 0 && t.Options.SpectatorDelaySeconds == 0 {
+		return
+	}
+
+	g.SpectatorTimer.Stop()
+	for _, delayedAction := range g.DelayedActions {
+		t.releaseSpectatorAction(delayedAction)
+	}
+	g.DelayedActions = make([]*DelayedAction, 0)
+
+	for _, chatMsg := range t.Chat {
+		if !chatMsg.HiddenFromPlayers {
+			continue
+		}
+		chatMsg.HiddenFromPlayers = false
+
+		for _, p := range t.Players {
+			if p.Present {
+				p.Session.Emit("chat", &ChatMessage{
+					Msg:       chatMsg.Msg,
+					Who:       chatMsg.Username,
+					Discord:   false,
+					Server:    chatMsg.Server,
+					Datetime:  chatMsg.Datetime,
+					Room:      t.GetRoomName(),
+					Recipient: "",
+				})
+			}
+		}
+	}
+}
+
+// isHiddenFromPlayers returns whether or not the players should not be able to see something that
+// the provided spectator is doing (e.g. chatting)
+func (t *Table) isHiddenFromPlayers(spectatorIndex int) bool {
+	return spectatorIndex != -1 && t.HasSpectatorDelay()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// The action that ends the game must not be sent to the spectators before the actions that are
+// still being held back from them
+func TestSpectatorDelayAtTheEndOfTheGame(t *testing.T) {
+	testCases := []struct {
+		description           string
+		spectatorDelayTurns   int
+		spectatorDelaySeconds int
+	}{
+		{"a delay in turns", 3, 0},
+		{"a delay in seconds", 0, MaxSpectatorDelaySeconds},
+	}
+
+	for _, tc := range testCases {
+		initialState, _, actions := dealTestGame(t, "No Variant", []string{"Alice", "Bob"},
+			"p2v0s1")
+
+		table := NewTable("Spectator delay test", 1)
+		table.Options.SpectatorDelayTurns = tc.spectatorDelayTurns
+		table.Options.SpectatorDelaySeconds = tc.spectatorDelaySeconds
+		table.Spectators = append(table.Spectators, &Spectator{ // nolint: exhaustivestruct
+			UserID:               2,
+			Name:                 "Cathy",
+			Session:              NewFakeSession(2, "Cathy"),
+			ShadowingPlayerIndex: -1,
+		})
+		g := NewGame(table)
+		g.SetState(initialState)
+		table.Running = true
+
+		for _, a := range actions {
+			var state *engine.State
+			var events []engine.Event
+			if v1, v2, err := g.State.Apply(a); err != nil {
+				t.Fatalf("%s: failed to apply an action on turn %d: %v", tc.description,
+					g.State.Turn, err)
+			} else {
+				state = v1
+				events = v2
+			}
+			g.SetState(state)
+			g.Actions2 = append(g.Actions2, a)
+			for _, event := range events {
+				g.Actions = append(g.Actions, event)
+				table.NotifyGameAction()
+			}
+		}
+		if g.State.EndCondition == EndConditionInProgress {
+			t.Fatalf("%s: the game did not end", tc.description)
+		}
+
+		// Every action from the first one that was held back must still be waiting in the queue,
+		// including the ones from the final move
+		if len(g.DelayedActions) == 0 {
+			t.Fatalf("%s: no actions were held back from the spectators", tc.description)
+		}
+		firstIndex := g.DelayedActions[0].Index
+		for i, delayedAction := range g.DelayedActions {
+			if delayedAction.Index != firstIndex+i {
+				t.Fatalf("%s: the delayed action %d is for action %d instead of action %d",
+					tc.description, i, delayedAction.Index, firstIndex+i)
+			}
+		}
+		if firstIndex+len(g.DelayedActions) != len(g.Actions) {
+			t.Errorf("%s: %d actions were sent to the spectators ahead of the delayed actions",
+				tc.description, len(g.Actions)-firstIndex-len(g.DelayedActions))
+		}
+		if len(table.GetSpectatorActions()) != firstIndex {
+			t.Errorf("%s: the spectators can see %d actions instead of %d", tc.description,
+				len(table.GetSpectatorActions()), firstIndex)
+		}
+
+		table.EndSpectatorDelay()
+		if len(g.DelayedActions) != 0 {
+			t.Errorf("%s: %d actions are still held back after the game ended", tc.description,
+				len(g.DelayedActions))
+		}
+		if len(table.GetSpectatorActions()) != len(g.Actions) {
+			t.Errorf("%s: the spectators can only see %d of the %d actions after the game ended",
+				tc.description, len(table.GetSpectatorActions()), len(g.Actions))
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// This file contains the functions for substituting a player in the middle of a game
+// The table owner proposes a spectator to take over the seat of a player who has to leave
+// Every other player (and the substitute) must accept before the substitution happens
+// The substitute inherits everything about the seat (the hand, the notes, and the clock)
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+type Substitution struct {
+	PlayerIndex int // The seat that is being taken over
+	UserID      int // The spectator who is taking over the seat
+	Name        string
+	// The user IDs of the users that have accepted the substitution so far
+	Accepted map[int]struct{}
+	// Timer is the scheduler that cancels the substitution when the time runs out
+	// (the players have as long as they do for a table vote; see "VoteTimeout")
+	Timer *TurnTimer
+}
+
+// getSubstitutionVoters returns the user IDs of the users that have to accept a substitution
+// Bots that run inside of the server do not vote
+func (t *Table) getSubstitutionVoters() []int {
+	// Local variables
+	sub := t.Substitution
+
+	voters := make([]int, 0)
+	for i, p := range t.Players {
+		if i != sub.PlayerIndex && p.BotPlayer == nil {
+			voters = append(voters, p.UserID)
+		}
+	}
+	voters = append(voters, sub.UserID)
+
+	return voters
+}
+
+// getSubstitutionWaitingOn returns the names of the users that have not accepted yet
+func (t *Table) getSubstitutionWaitingOn() []string {
+	// Local variables
+	sub := t.Substitution
+
+	names := make([]string, 0)
+	for _, userID := range t.getSubstitutionVoters() {
+		if _, ok := sub.Accepted[userID]; ok {
+			continue
+		}
+		if userID == sub.UserID {
+			names = append(names, sub.Name)
+		} else {
+			names = append(names, t.Players[t.GetPlayerIndexFromID(userID)].Name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// isPlayingAnotherRealTimeTable returns whether or not a spectator is already playing at another
+// real-time table (correspondence games do not count, since they can be played alongside other
+// games)
+// The tables lock is assumed to be acquired in this function
+func isPlayingAnotherRealTimeTable(sp *Spectator, t *Table) bool {
+	if t.Options.Correspondence || (sp.Session != nil && sp.Session.Bot) {
+		return false
+	}
+
+	return len(tables.GetRealTimeTablesUserPlaying(sp.UserID)) > 0
+}
+
+// substitutionVote records a vote on the pending substitution
+// If everyone has accepted, the substitution is performed
+// The table lock is assumed to be acquired in this function
+func substitutionVote(ctx context.Context, s *Session, d *CommandData, t *Table, accept bool) {
+	// Local variables
+	sub := t.Substitution
+
+	if !accept {
+		sub.Timer.Stop()
+		t.Substitution = nil
+		msg := s.Username + " declined the substitution of " + sub.Name + " for " +
+			t.Players[sub.PlayerIndex].Name + "."
+		chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+		return
+	}
+
+	sub.Accepted[s.UserID] = struct{}{}
+
+	waitingOn := t.getSubstitutionWaitingOn()
+	if len(waitingOn) > 0 {
+		msg := s.Username + " accepted the substitution. Waiting on: " +
+			strings.Join(waitingOn, ", ")
+		chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+		return
+	}
+
+	substitutePlayer(ctx, d, t)
+}
+
+// substitutePlayer gives the seat of a player to the substitute
+// The table lock is assumed to be acquired in this function
+func substitutePlayer(ctx context.Context, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+	sub := t.Substitution
+	sub.Timer.Stop()
+	t.Substitution = nil
+
+	// Since this is a function that changes a user's relationship to tables,
+	// we must acquires the tables lock to prevent race conditions
+	if !d.NoTablesLock {
+		tables.Lock(ctx)
+		defer tables.Unlock(ctx)
+	}
+
+	// The substitute must still be spectating
+	spectatorIndex := t.GetSpectatorIndexFromID(sub.UserID)
+	if spectatorIndex == -1 {
+		msg := sub.Name + " is no longer spectating, so the substitution was canceled."
+		chatServerSend(ctx, msg, t.GetRoomName(), true)
+		return
+	}
+	sp := t.Spectators[spectatorIndex]
+
+	// The substitute may have joined another game while the vote was in progress
+	// (only bots have the ability to join more than one table; see "tableJoin()")
+	if isPlayingAnotherRealTimeTable(sp, t) {
+		msg := sp.Name + " is already playing at another table, so the substitution was canceled."
+		chatServerSend(ctx, msg, t.GetRoomName(), true)
+		return
+	}
+
+	t.Spectators = append(t.Spectators[:spectatorIndex], t.Spectators[spectatorIndex+1:]...)
+	tables.DeleteSpectating(sp.UserID, t.ID)
+
+	// Take the original player out of the game
+	p := t.Players[sub.PlayerIndex]
+	originalUserID := p.UserID
+	originalName := p.Name
+	tables.DeletePlaying(p.UserID, t.ID)
+	if p.Session != nil {
+		if p.Present {
+			p.Session.NotifyBoot(t)
+		}
+		p.Session.SetStatus(StatusLobby)
+		p.Session.SetTableID(uint64(0))
+		notifyAllUser(p.Session)
+	}
+
+	// The database records who started the game in this seat
+	// (if there is more than one substitution for the same seat, this stays as the first player)
+	if p.OriginalUserID == 0 {
+		p.OriginalUserID = p.UserID
+	}
+
+	// Put the substitute in the seat
+	// The "GamePlayer" object stays the same, so they keep the hand, the notes, and the clock
+	p.UserID = sp.UserID
+	p.Name = sp.Name
+	p.Session = sp.Session
+	p.Bot = sp.Session != nil && sp.Session.Bot
+	p.Present = false // This will be set to true once they load the game
+	p.Typing = false
+	g.Players[sub.PlayerIndex].Name = sp.Name
+	tables.AddPlaying(p.UserID, t.ID)
+	p.Session.SetStatus(StatusPlaying)
+	p.Session.SetTableID(t.ID)
+	notifyAllUser(p.Session)
+
+	// The table needs an owner who is still playing,
+	// so the substitute also takes over the table if the original player owned it
+	msg := sp.Name + " has taken over the seat of " + originalName + "."
+	if t.OwnerID == originalUserID {
+		t.OwnerID = sp.UserID
+		msg += " They are now the owner of the table."
+	}
+
+	// Correspondence games are stored in the database, so they have to be saved again
+	t.SaveCorrespondence()
+
+	logger.Info(t.GetName() + "Substituted " + sp.Name + " for " + originalName + ".")
+	chatServerSend(ctx, msg, t.GetRoomName(), true)
+
+	notifyAllTable(t)    // Update the player list for the row in the lobby
+	t.NotifySpectators() // Update the in-game spectator list
+
+	// Everyone needs to reload the game so that they see the new name
+	// (and so that the substitute sees the game from the perspective of the player)
+	p.Session.NotifyTableStart(t)
+	for _, p2 := range t.Players {
+		if p2.Present {
+			p2.Session.NotifyTableStart(t)
+		}
+	}
+	for _, sp2 := range t.Spectators {
+		sp2.Session.NotifyTableStart(t)
+	}
+}
+
+// CheckSubstitutionExpired is meant to be called after the time to accept a substitution has run
+// out
+func (t *Table) CheckSubstitutionExpired(ctx context.Context, sub *Substitution) {
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(ctx, nil, t.ID, false, true)
+	if !exists || t != t2 {
+		return
+	}
+	t.Lock(ctx)
+	defer t.Unlock(ctx)
+
+	// Check to see if the substitution already happened or was declined
+	if t.Substitution != sub {
+		return
+	}
+
+	msg := "The substitution of " + sub.Name + " for " + t.Players[sub.PlayerIndex].Name +
+		" expired. (Did not accept: " + strings.Join(t.getSubstitutionWaitingOn(), ", ") + ")"
+	logger.Info(t.GetName() + msg)
+	chatServerSend(ctx, msg, t.GetRoomName(), false)
+
+	t.Substitution = nil
+}
+
+// CancelSubstitution ends the pending substitution (if any) without performing it
+// (e.g. when the game ends)
+// The table lock is assumed to be acquired in this function
+func (t *Table) CancelSubstitution() {
+	if t.Substitution == nil {
+		return
+	}
+
+	t.Substitution.Timer.Stop()
+	t.Substitution = nil
+}
@@ -53,6 +53,12 @@ type Table struct {
 	ChatRead map[int]int         // A map of which users have read which messages
 	Deleted  bool                `json:"-"` // Used to prevent race conditions
 
+	// The owner can propose a spectator to take over the seat of a player in the middle of a game
+	// (see "substitution.go")
+	Substitution *Substitution `json:"-"`
+	// Players can vote to terminate, pause, unpause, or restart the game (see "table_vote.go")
+	Vote *TableVote `json:"-"`
+
 	// Each table has its own mutex to ensure that only one action can occur at the same time
 	mutex *deadlock.Mutex
 }
@@ -63,6 +69,8 @@ type TableChatMessage struct {
 	Msg      string
 	Datetime time.Time
 	Server   bool
+	// Spectators of a game with a spectator delay cannot talk to the players until the game ends
+	HiddenFromPlayers bool
 }
 
 var (
@@ -104,6 +112,9 @@ func NewTable(name string, ownerID int) *Table {
 		ChatRead: make(map[int]int),
 		Deleted:  false,
 
+		Substitution: nil,
+		Vote:         nil,
+
 		mutex: &deadlock.Mutex{},
 	}
 }
@@ -164,6 +175,12 @@ func (t *Table) CheckIdle(ctx context.Context) {
 		return
 	}
 
+	// Correspondence games are expected to be idle for long periods of time
+	// (they have a deadline for every move instead)
+	if t.IsOngoingCorrespondenceGame() {
+		return
+	}
+
 	t.EndIdle(ctx)
 }
 
@@ -237,7 +254,7 @@ func (t *Table) GetName() string {
 	if g == nil {
 		name += "Not started"
 	} else {
-		name += "Turn " + strconv.Itoa(g.Turn)
+		name += "Turn " + strconv.Itoa(g.State.Turn)
 	}
 	name += " - "
 	return name
@@ -4,8 +4,10 @@ package main
 	Notifications for both before and during a game
 */
 
-func (t *Table) NotifyChat(chatMessage *ChatMessage) {
-	if !t.Replay {
+// NotifyChat sends a chat message to everyone at the table
+// (or only to the spectators, if it is hidden from the players)
+func (t *Table) NotifyChat(chatMessage *ChatMessage, hiddenFromPlayers bool) {
+	if !t.Replay && !hiddenFromPlayers {
 		for _, p := range t.Players {
 			if p.Present {
 				p.Session.Emit("chat", chatMessage)
@@ -19,7 +21,15 @@ func (t *Table) NotifyChat(chatMessage *ChatMessage) {
 }
 
 func (t *Table) NotifyChatTyping(name string, typing bool) {
-	if !t.Replay {
+	// The players cannot see the spectators of a game with a spectator delay
+	hiddenFromPlayers := false
+	for i, sp := range t.Spectators {
+		if sp.Name == name {
+			hiddenFromPlayers = t.isHiddenFromPlayers(i)
+		}
+	}
+
+	if !t.Replay && !hiddenFromPlayers {
 		for _, p := range t.Players {
 			if p.Present && p.Name != name { // We do not need to alert the person who is typing
 				p.Session.NotifyChatTyping(t, name, typing)
@@ -34,6 +44,30 @@ func (t *Table) NotifyChatTyping(name string, typing bool) {
 	}
 }
 
+// NotifyBlockers warns the people at the table who have blocked a user that just joined it
+// (a spectator who is hidden from the players only causes the other spectators to be warned)
+func (t *Table) NotifyBlockers(s *Session, hiddenFromPlayers bool) {
+	reverseBlocks := s.ReverseBlocks()
+	if len(reverseBlocks) == 0 {
+		return
+	}
+	msg := "\"" + s.Username + "\", who you have blocked, has joined your table."
+
+	if !t.Replay && !hiddenFromPlayers {
+		for _, p := range t.Players {
+			if _, ok := reverseBlocks[p.UserID]; ok && p.Present {
+				p.Session.Warning(msg)
+			}
+		}
+	}
+
+	for _, sp := range t.Spectators {
+		if _, ok := reverseBlocks[sp.UserID]; ok {
+			sp.Session.Warning(msg)
+		}
+	}
+}
+
 /*
 	Notifications before a game has started
 */
@@ -158,38 +192,17 @@ func (t *Table) NotifyGameAction() {
 	}
 
 	// Also send the spectators an update
+	// (if some actions are still being held back, this one has to wait behind them,
+	// even if it is the action that ended the game; see "EndSpectatorDelay()")
+	if t.HasSpectatorDelay() || len(g.DelayedActions) > 0 {
+		t.QueueSpectatorAction()
+		return
+	}
 	for _, sp := range t.Spectators {
 		sp.Session.NotifyGameAction(t, a)
 	}
 }
 
-// NotifyStatus appends a new "status" action and alerts everyone
-func (t *Table) NotifyStatus() {
-	g := t.Game
-	g.Actions = append(g.Actions, ActionStatus{
-		Type:     "status",
-		Clues:    g.ClueTokens,
-		Score:    g.Score,
-		MaxScore: g.MaxScore,
-	})
-	t.NotifyGameAction()
-}
-
-// NotifyTurn appends a new "turn" action and alerts everyone
-func (t *Table) NotifyTurn() {
-	g := t.Game
-	currentPlayerIndex := g.ActivePlayerIndex
-	if g.EndCondition > EndConditionInProgress {
-		currentPlayerIndex = -1
-	}
-	g.Actions = append(g.Actions, ActionTurn{
-		Type:               "turn",
-		Num:                g.Turn,
-		CurrentPlayerIndex: currentPlayerIndex,
-	})
-	t.NotifyGameAction()
-}
-
 func (t *Table) NotifyFinishOngoingGame() {
 	type FinishOngoingGameMessage struct {
 		TableID            uint64 `json:"tableID"`
@@ -221,11 +234,31 @@ func (t *Table) NotifyTime() {
 		}
 	}
 
+	// The clocks would show the spectators whose turn it is
+	if t.HasSpectatorDelay() {
+		return
+	}
 	for _, sp := range t.Spectators {
 		sp.Session.NotifyTime(t)
 	}
 }
 
+// NotifyCorrespondence lets every player in a correspondence game know that the game has changed,
+// even if they are not currently looking at it
+func (t *Table) NotifyCorrespondence() {
+	for _, p := range t.Players {
+		if p.Session != nil {
+			p.Session.Emit("correspondenceTable", makeCorrespondenceTableMessage(p.Session, t))
+		}
+	}
+}
+
+func (t *Table) NotifyHypothetical() {
+	for _, sp := range t.Spectators {
+		sp.Session.NotifyHypothetical(t)
+	}
+}
+
 func (t *Table) NotifyPause() {
 	for _, p := range t.Players {
 		if p.Present {
@@ -256,9 +289,12 @@ func (t *Table) NotifySpectatorsNote(order int) {
 			Name string `json:"name"`
 			Text string `json:"text"`
 		}
+		// The notes of the players are hidden while there is a spectator delay
 		notes := make([]Note, 0)
 		for _, p := range g.Players {
-			if sp.ShadowingPlayerIndex == -1 || sp.ShadowingPlayerIndex == p.Index {
+			if !t.HasSpectatorDelay() &&
+				(sp.ShadowingPlayerIndex == -1 || sp.ShadowingPlayerIndex == p.Index) {
+
 				notes = append(notes, Note{
 					Name: p.Name,
 					Text: p.Notes[order],
@@ -0,0 +1,329 @@
+// This file contains the functions for table votes
+// Any player can propose to terminate, pause, unpause, or restart the game
+// (or to undo the last move, see "undo.go"),
+// and then the other players have a short amount of time to accept or decline
+// Tables that are created with the "voteRequired" option do not let a single player do any of
+// these things on their own; on other tables, votes are optional
+// The outcome of every vote (and who voted for what) is logged to the table chat
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	VoteTypeTerminate = "terminate"
+	VoteTypePause     = "pause"
+	VoteTypeUnpause   = "unpause"
+	VoteTypeRestart   = "restart"
+	VoteTypeUndo      = "undo"
+
+	// The players must all accept before this much time has passed
+	VoteTimeout = time.Minute
+)
+
+var (
+	voteDescriptions = map[string]string{
+		VoteTypeTerminate: "terminate the game",
+		VoteTypePause:     "pause the game",
+		VoteTypeUnpause:   "unpause the game",
+		VoteTypeRestart:   "restart the game with the same players",
+		VoteTypeUndo:      "undo the last move",
+	}
+)
+
+type TableVote struct {
+	Type       string // See the "VoteType" constants
+	ProposerID int
+	// The user IDs of the users that have accepted the vote so far
+	Accepted map[int]struct{}
+	// Timer is the scheduler that ends the vote when the time runs out
+	Timer *TurnTimer
+}
+
+// getVoters returns the user IDs of the users that vote on the table votes
+// Bots that run inside of the server do not vote
+func (t *Table) getVoters() []int {
+	voters := make([]int, 0)
+	for _, p := range t.Players {
+		if p.BotPlayer == nil {
+			voters = append(voters, p.UserID)
+		}
+	}
+
+	return voters
+}
+
+// getVoteNames returns the sorted names of the voters that have accepted (or not accepted)
+func (t *Table) getVoteNames(accepted bool) []string {
+	names := make([]string, 0)
+	for _, userID := range t.getVoters() {
+		if _, ok := t.Vote.Accepted[userID]; ok == accepted {
+			names = append(names, t.Players[t.GetPlayerIndexFromID(userID)].Name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// validateVote checks to see if a table vote of the provided type can be proposed right now
+// The table lock is assumed to be acquired in this function
+func validateVote(s *Session, t *Table, voteType string) bool {
+	// Local variables
+	g := t.Game
+
+	if _, ok := voteDescriptions[voteType]; !ok {
+		s.Warning("\"" + voteType + "\" is not a valid vote. You can vote to: " +
+			VoteTypeTerminate + ", " + VoteTypePause + ", " + VoteTypeUnpause + ", " +
+			VoteTypeRestart + ", " + VoteTypeUndo)
+		return false
+	}
+
+	if t.Vote != nil {
+		s.Warning("There is already a pending vote to " + voteDescriptions[t.Vote.Type] + ".")
+		return false
+	}
+
+	if t.Substitution != nil {
+		s.Warning("You cannot propose a vote while there is a pending substitution.")
+		return false
+	}
+
+	if voteType == VoteTypePause || voteType == VoteTypeUnpause {
+		if !t.Options.Timed {
+			s.Warning("This is not a timed game, so you cannot pause / unpause.")
+			return false
+		}
+		if voteType == VoteTypePause && g.Paused {
+			s.Warning("The game is already paused.")
+			return false
+		}
+		if voteType == VoteTypeUnpause && !g.Paused {
+			s.Warning("The game is not paused, so you cannot unpause.")
+			return false
+		}
+	}
+
+	if voteType == VoteTypeRestart {
+		if strings.HasPrefix(t.Name, "!seed") || strings.HasPrefix(t.InitialName, "!seed") {
+			s.Warning("You are not allowed to restart \"!seed\" games.")
+			return false
+		}
+		if strings.HasPrefix(t.Name, "!replay") || strings.HasPrefix(t.InitialName, "!replay") {
+			s.Warning("You are not allowed to restart \"!replay\" games.")
+			return false
+		}
+	}
+
+	if voteType == VoteTypeUndo && !validateUndo(s, t) {
+		return false
+	}
+
+	return true
+}
+
+// startVote proposes a table vote (the proposer automatically accepts it)
+// The table lock is assumed to be acquired in this function
+func startVote(ctx context.Context, s *Session, d *CommandData, t *Table, voteType string) {
+	if !validateVote(s, t, voteType) {
+		return
+	}
+
+	vote := &TableVote{
+		Type:       voteType,
+		ProposerID: s.UserID,
+		Accepted:   make(map[int]struct{}),
+		Timer:      NewTurnTimer(),
+	}
+	vote.Accepted[s.UserID] = struct{}{}
+	t.Vote = vote
+
+	logger.Info(t.GetName() + "User \"" + s.Username + "\" proposed a vote to " +
+		voteDescriptions[voteType] + ".")
+
+	// The vote might pass right away if there are no other players (e.g. a game with bots)
+	if len(t.getVoteNames(false)) == 0 {
+		passVote(ctx, s, d, t)
+		return
+	}
+
+	// End the vote if the players do not all accept in time
+	vote.Timer.Schedule(VoteTimeout, func() {
+		t.CheckVoteExpired(ctx, vote)
+	})
+
+	msg := s.Username + " proposed a vote to " + voteDescriptions[voteType] + ". " +
+		"Use /accept or /decline to vote within " +
+		strconv.Itoa(int(VoteTimeout.Seconds())) + " seconds. " +
+		"Waiting on: " + strings.Join(t.getVoteNames(false), ", ")
+	chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+}
+
+// castVote records a vote on the pending table vote
+// If everyone has accepted, the vote passes; if anyone declines, the vote fails
+// The table lock is assumed to be acquired in this function
+func castVote(ctx context.Context, s *Session, d *CommandData, t *Table, accept bool) {
+	if !accept {
+		endVote(ctx, d, t, "failed because "+s.Username+" declined")
+		return
+	}
+
+	t.Vote.Accepted[s.UserID] = struct{}{}
+
+	waitingOn := t.getVoteNames(false)
+	if len(waitingOn) > 0 {
+		msg := s.Username + " accepted the vote. Waiting on: " + strings.Join(waitingOn, ", ")
+		chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+		return
+	}
+
+	passVote(ctx, s, d, t)
+}
+
+// CheckVoteExpired is meant to be called after the time to vote has run out
+func (t *Table) CheckVoteExpired(ctx context.Context, vote *TableVote) {
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(ctx, nil, t.ID, false, true)
+	if !exists || t != t2 {
+		return
+	}
+	t.Lock(ctx)
+	defer t.Unlock(ctx)
+
+	// Check to see if the vote already ended
+	if t.Vote != vote {
+		return
+	}
+
+	endVote(ctx, &CommandData{}, t, "expired")
+}
+
+// endVote logs the outcome of the vote (and the voters) to the table chat
+// The table lock is assumed to be acquired in this function
+func endVote(ctx context.Context, d *CommandData, t *Table, outcome string) {
+	// Local variables
+	vote := t.Vote
+
+	msg := "The vote to " + voteDescriptions[vote.Type] + " " + outcome + ". " +
+		"(Accepted: " + strings.Join(t.getVoteNames(true), ", ")
+	if notAccepted := t.getVoteNames(false); len(notAccepted) > 0 {
+		msg += "; did not accept: " + strings.Join(notAccepted, ", ")
+	}
+	msg += ")"
+
+	logger.Info(t.GetName() + msg)
+	chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+
+	vote.Timer.Stop()
+	t.Vote = nil
+}
+
+// passVote ends the vote and performs the action that was voted on
+// on behalf of the player who proposed it
+// The table lock is assumed to be acquired in this function
+func passVote(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	// Local variables
+	vote := t.Vote
+
+	endVote(ctx, d, t, "passed")
+
+	// Get the session of the player who proposed the vote
+	s2 := t.Players[t.GetPlayerIndexFromID(vote.ProposerID)].Session
+	if s2 == nil {
+		// A player's session should never be nil
+		// They might be in the process of reconnecting, so perform the action as the last voter
+		s2 = s
+	}
+
+	// The "Server" flag marks that the vote has already passed,
+	// so that the commands do not start another vote
+	switch vote.Type {
+	case VoteTypeTerminate:
+		commandTableTerminate(ctx, s2, &CommandData{ // nolint: exhaustivestruct
+			TableID:      t.ID,
+			Server:       true,
+			NoTableLock:  true,
+			NoTablesLock: d.NoTablesLock,
+		})
+
+	case VoteTypePause, VoteTypeUnpause:
+		commandPause(ctx, s2, &CommandData{ // nolint: exhaustivestruct
+			TableID:      t.ID,
+			Setting:      vote.Type,
+			Server:       true,
+			NoTableLock:  true,
+			NoTablesLock: d.NoTablesLock,
+		})
+
+	case VoteTypeRestart:
+		restartVote(ctx, s2, d, t)
+
+	case VoteTypeUndo:
+		undo(ctx, d, t)
+	}
+}
+
+// restartVote restarts the game with the same players after a restart vote passes
+// The table lock is assumed to be acquired in this function
+func restartVote(ctx context.Context, s *Session, d *CommandData, t *Table) {
+	// Restarting is normally done from the shared replay after the game ends,
+	// so if the game is ongoing, we have to end it first
+	if !t.Replay {
+		commandTableTerminate(ctx, s, &CommandData{ // nolint: exhaustivestruct
+			TableID:      t.ID,
+			Server:       true,
+			NoTableLock:  true,
+			NoTablesLock: d.NoTablesLock,
+		})
+
+		// The game is only converted to a shared replay if there are players present
+		if !t.Replay || t.Deleted {
+			return
+		}
+	}
+
+	// Only the leader of a shared replay can restart it
+	spectatorIndex := t.GetSpectatorIndexFromID(t.OwnerID)
+	if spectatorIndex == -1 {
+		msg := "The game could not be restarted because the leader is not in the shared replay."
+		chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+		return
+	}
+
+	s2 := t.Spectators[spectatorIndex].Session
+	commandTableRestart(ctx, s2, &CommandData{ // nolint: exhaustivestruct
+		TableID:      t.ID,
+		Server:       true,
+		NoTableLock:  true,
+		NoTablesLock: d.NoTablesLock,
+	})
+}
+
+// CancelUndoVote ends a pending vote to undo the last move when another move is made
+// (since it would no longer undo the move that the players were voting on)
+// The table lock is assumed to be acquired in this function
+func (t *Table) CancelUndoVote(ctx context.Context, d *CommandData) {
+	if t.Vote == nil || t.Vote.Type != VoteTypeUndo {
+		return
+	}
+
+	endVote(ctx, d, t, "was canceled because another move was made")
+}
+
+// CancelVote ends the pending vote (if any) without performing anything
+// (e.g. when the game ends on its own)
+// The table lock is assumed to be acquired in this function
+func (t *Table) CancelVote() {
+	if t.Vote == nil {
+		return
+	}
+
+	t.Vote.Timer.Stop()
+	t.Vote = nil
+}
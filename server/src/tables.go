@@ -154,6 +154,20 @@ func (ts *Tables) GetTablesUserPlaying(userID int) []uint64 {
 	return make([]uint64, 0)
 }
 
+// GetRealTimeTablesUserPlaying is the same as "GetTablesUserPlaying()",
+// but it excludes correspondence games
+// (users can play in any number of correspondence games alongside one real-time game)
+func (ts *Tables) GetRealTimeTablesUserPlaying(userID int) []uint64 {
+	// It is assumed that the tables mutex is locked when calling this function
+	tableIDs := make([]uint64, 0)
+	for _, tableID := range ts.GetTablesUserPlaying(userID) {
+		if t, ok := ts.tables[tableID]; ok && !t.Options.Correspondence {
+			tableIDs = append(tableIDs, tableID)
+		}
+	}
+	return tableIDs
+}
+
 func (ts *Tables) PrintPlaying() {
 	// It is assumed that the tables mutex is locked when calling this function
 	logger.Debug("Playing relationships:")
@@ -0,0 +1,138 @@
+// This file contains the functions for throttling
+// Users are throttled when they flood the server or when a moderator throttles them
+// (the throttled accounts and IPs are stored in the "throttled_ips" table)
+// Throttled users have stricter rate limits for some kinds of commands,
+// with a separate bucket for each kind (e.g. chatting does not use up the allowance for actions)
+// When they exceed a limit, the command is ignored and they are warned,
+// and if they keep doing it, they are disconnected
+// Users that flood the server while they are already throttled are banned
+
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	ThrottleBucketChat        = "chat"
+	ThrottleBucketTableCreate = "tableCreate"
+	ThrottleBucketAction      = "action"
+
+	// The number of warnings that a throttled user can get before they are disconnected
+	ThrottleMaxWarnings = 3
+)
+
+type ThrottleLimit struct {
+	Rate float64 // Number of commands sent
+	Per  float64 // Per seconds
+}
+
+var (
+	throttleLimits = map[string]*ThrottleLimit{
+		ThrottleBucketChat:        {Rate: 3, Per: 10},
+		ThrottleBucketTableCreate: {Rate: 1, Per: 30},
+		ThrottleBucketAction:      {Rate: 5, Per: 5},
+	}
+
+	// Commands that are not in this map are only subject to the normal rate limit
+	throttleCommandBuckets = map[string]string{
+		"chat":         ThrottleBucketChat,
+		"chatPM":       ThrottleBucketChat,
+		"report":       ThrottleBucketChat,
+		"tableCreate":  ThrottleBucketTableCreate,
+		"replayCreate": ThrottleBucketTableCreate,
+		"action":       ThrottleBucketAction,
+	}
+)
+
+type RateLimitBucket struct {
+	Allowance float64
+	LastCheck time.Time
+}
+
+// Take uses up one command from the bucket
+// It returns false if there is no allowance left
+// Algorithm from: http://stackoverflow.com/questions/667508
+func (bucket *RateLimitBucket) Take(limit *ThrottleLimit) bool {
+	now := time.Now()
+	timePassed := now.Sub(bucket.LastCheck).Seconds()
+	bucket.LastCheck = now
+
+	bucket.Allowance += timePassed * (limit.Rate / limit.Per)
+	if bucket.Allowance > limit.Rate {
+		bucket.Allowance = limit.Rate
+	}
+
+	if bucket.Allowance < 1 {
+		return false
+	}
+
+	bucket.Allowance--
+	return true
+}
+
+// throttleCommand checks to see if a throttled user is allowed to perform a command right now
+// It returns false if the command should be ignored
+func throttleCommand(s *Session, command string) bool {
+	if !s.Throttled() {
+		return true
+	}
+
+	bucketName, ok := throttleCommandBuckets[command]
+	if !ok {
+		return true
+	}
+	limit := throttleLimits[bucketName]
+
+	s.DataMutex.Lock()
+	bucket, ok := s.Data.ThrottleBuckets[bucketName]
+	if !ok {
+		bucket = &RateLimitBucket{
+			Allowance: limit.Rate,
+			LastCheck: time.Now(),
+		}
+		s.Data.ThrottleBuckets[bucketName] = bucket
+	}
+	allowed := bucket.Take(limit)
+	if !allowed {
+		s.Data.ThrottleWarnings++
+	}
+	numWarnings := s.Data.ThrottleWarnings
+	s.DataMutex.Unlock()
+
+	if allowed {
+		return true
+	}
+
+	if numWarnings > ThrottleMaxWarnings {
+		// Ignore any of their remaining messages in the queue after they are disconnected
+		if numWarnings == ThrottleMaxWarnings+1 {
+			logger.Info("Throttled user \"" + s.Username + "\" exceeded the rate limit for " +
+				"\"" + bucketName + "\" too many times; disconnecting them.")
+			logoutUser(s.UserID)
+		}
+		return false
+	}
+
+	s.Warning("You are sending too many messages of this kind, so your last one was ignored. " +
+		"Please slow down. (Warning " + strconv.Itoa(numWarnings) + " of " +
+		strconv.Itoa(ThrottleMaxWarnings) + "; after that, you will be disconnected.)")
+	return false
+}
+
+// throttle records a user (and their IP) in the "throttled_ips" table
+// If they are online, the stricter rate limits take effect immediately
+func throttle(s *Session, reason string) {
+	// Local variables
+	ip := getSessionIP(s)
+
+	if err := models.ThrottledIPs.Insert(ip, s.UserID, reason); err != nil {
+		logger.Error("Failed to insert the throttled IP row: " + err.Error())
+		return
+	}
+
+	s.SetThrottled(true)
+	logger.Info("Successfully throttled user \"" + s.Username + "\" from IP address " +
+		"\"" + ip + "\".")
+}
@@ -0,0 +1,160 @@
+// This file contains the functions for undoing the last action of a game
+// (e.g. when a player misclicks)
+// An undo is proposed with a table vote and all of the other players must accept it
+// (see "table_vote.go")
+// Undos are only allowed in untimed games that are not speedruns
+// Games with undos are flagged in the database so that they do not count towards best scores
+
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+// validateUndo checks to see if the last action of the game can be undone
+// The table lock is assumed to be acquired in this function
+func validateUndo(s *Session, t *Table) bool {
+	// Local variables
+	g := t.Game
+
+	if t.Options.Timed || t.Options.Speedrun {
+		s.Warning("You can only undo in untimed games that are not speedruns.")
+		return false
+	}
+
+	// The spectators are sent the actions later on, so we cannot take one back
+	if t.HasSpectatorDelay() {
+		s.Warning("You cannot undo in a game with a spectator delay.")
+		return false
+	}
+
+	if t.ExtraOptions.PuzzleID != 0 {
+		s.Warning("You cannot undo in a puzzle.")
+		return false
+	}
+
+	if len(g.Actions2) == 0 {
+		s.Warning("There are no moves to undo.")
+		return false
+	}
+
+	if err := g.checkUndoHistory(); err != nil {
+		s.Warning("You cannot undo in this game, since " + err.Error() + ".")
+		return false
+	}
+
+	return true
+}
+
+// Undo rolls back the game to the turn before the last action
+// The state is rebuilt by applying all of the other actions to the initial state
+// The table lock is assumed to be acquired in this function
+func (g *Game) Undo() error {
+	if len(g.Actions2) == 0 {
+		return errors.New("there are no actions to undo")
+	}
+	if err := g.checkUndoHistory(); err != nil {
+		return err
+	}
+	actions := g.Actions2[:len(g.Actions2)-1]
+	lastAction := g.Actions2[len(g.Actions2)-1]
+
+	state := g.InitialState.Clone()
+	for i, a := range actions {
+		if v, _, err := state.Apply(a); err != nil {
+			return errors.New("failed to apply action " + strconv.Itoa(i) + ": " + err.Error())
+		} else {
+			state = v
+		}
+	}
+
+	// Find out how many events the last action added to the action log
+	var numEvents int
+	if _, events, err := state.Apply(lastAction); err != nil {
+		return errors.New("failed to apply the last action: " + err.Error())
+	} else {
+		numEvents = len(events)
+	}
+	if numEvents > len(g.Actions) {
+		return errors.New("the last action has more events than the action log")
+	}
+
+	// Give back the time that the last action took from the clock of the player
+	// (undos are only allowed in non-timed games, where "ChargeTime()" only subtracts the time)
+	g.Players[state.ActivePlayerIndex].Time += g.TurnDurations[len(actions)]
+
+	g.SetState(state)
+	g.Actions = g.Actions[:len(g.Actions)-numEvents]
+	g.Actions2 = actions
+	g.TurnDurations = g.TurnDurations[:len(actions)]
+	g.NumUndos++
+
+	// The player gets to take their turn again
+	g.TurnTimeTaken = 0
+	g.DatetimeTurnBegin = time.Now()
+
+	return nil
+}
+
+// checkUndoHistory checks that we have everything that is needed to rebuild the state
+func (g *Game) checkUndoHistory() error {
+	if g.InitialState == nil {
+		return errors.New("the initial state of the game is not available")
+	}
+
+	if len(g.TurnDurations) != len(g.Actions2) {
+		return errors.New("the turn durations of the game are not available")
+	}
+
+	return nil
+}
+
+// undo is called after a vote to undo the last action passes
+// The table lock is assumed to be acquired in this function
+func undo(ctx context.Context, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	if err := g.Undo(); err != nil {
+		logger.Error(t.GetName() + "Failed to undo the last action: " + err.Error())
+		msg := "Something went wrong when undoing the last move. " +
+			"Please report this error to an administrator."
+		chatServerSend(ctx, msg, t.GetRoomName(), d.NoTablesLock)
+		return
+	}
+
+	logger.Info(t.GetName() + "Undid the last action. " +
+		"It is now " + g.Players[g.State.ActivePlayerIndex].Name + "'s turn.")
+
+	// Update the progress
+	// (but not while there is a spectator delay; see "commandAction()")
+	progressFloat := float64(g.State.Score) / float64(g.State.MaxScore) * 100 // In percent
+	progress := int(math.Round(progressFloat))
+	if progress != t.Progress && !t.HasSpectatorDelay() {
+		t.Progress = progress
+		t.NotifyProgress()
+	}
+
+	// Correspondence games are saved after every move
+	if t.IsOngoingCorrespondenceGame() {
+		t.SaveCorrespondence()
+		t.NotifyCorrespondence()
+	}
+
+	// Everyone needs to reload the game, since the client cannot take back an action
+	for _, p := range t.Players {
+		if p.Present {
+			p.Session.NotifyTableStart(t)
+		}
+	}
+	for _, sp := range t.Spectators {
+		sp.Session.NotifyTableStart(t)
+	}
+
+	// If it is a bot's turn, the bot will need to act
+	g.CheckBotTurn(ctx)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Zamiell/hanabi-live/engine"
+)
+
+// A game that was saved to disk during a server restart must still have everything that is needed
+// to undo the last action after it is restored
+func TestUndoAfterRestore(t *testing.T) {
+	ctx := context.Background()
+	table := startTestGame(t, "No Variant", []string{"Alice", "Bob"}, "p2v0s1")
+	tables.Set(table.ID, table)
+	defer tables.Delete(table.ID)
+	g := table.Game
+	g.DatetimeTurnBegin = time.Now()
+
+	// Perform a few actions in the same way as the players would
+	var expectedState *engine.State
+	expectedNumActions := 0
+	expectedTimes := make([]time.Duration, 0)
+	numActions := 3
+	for i := 0; i < numActions; i++ {
+		if i == numActions-1 {
+			expectedState = g.State.Clone()
+			expectedNumActions = len(g.Actions)
+			for _, p := range g.Players {
+				expectedTimes = append(expectedTimes, p.Time)
+			}
+		}
+
+		// Only give clues, so that the game does not end
+		p := table.Players[g.State.ActivePlayerIndex]
+		var legalAction *engine.LegalAction
+		for _, a := range g.State.LegalActions(g.State.ActivePlayerIndex) {
+			if a.Type == ActionTypeColorClue || a.Type == ActionTypeRankClue {
+				legalAction = a
+				break
+			}
+		}
+		if legalAction == nil {
+			t.Fatalf("there are no clues to give on turn %d", i)
+		}
+		commandAction(ctx, p.Session, &CommandData{ // nolint: exhaustivestruct
+			TableID:      table.ID,
+			Type:         legalAction.Type,
+			Target:       legalAction.Target,
+			Value:        legalAction.Value,
+			NoTableLock:  true,
+			NoTablesLock: true,
+		})
+		if len(g.Actions2) != i+1 {
+			t.Fatalf("the action on turn %d was not performed", i)
+		}
+	}
+
+	// Save the table and restore it (in the same way as "serializeTables()" and "restoreTable()")
+	var tableJSON []byte
+	if v, err := json.Marshal(table); err != nil {
+		t.Fatalf("failed to marshal the table: %v", err)
+	} else {
+		tableJSON = v
+	}
+	restoredTable := &Table{}
+	if err := json.Unmarshal(tableJSON, restoredTable); err != nil {
+		t.Fatalf("failed to unmarshal the table: %v", err)
+	}
+	restoreTableReferences(restoredTable)
+	restoredGame := restoredTable.Game
+
+	if err := restoredGame.Undo(); err != nil {
+		t.Fatalf("failed to undo the last action: %v", err)
+	}
+	assertSameState(t, "after the undo", expectedState, restoredGame.State)
+	if len(restoredGame.Actions) != expectedNumActions {
+		t.Errorf("there are %d actions in the action log instead of %d",
+			len(restoredGame.Actions), expectedNumActions)
+	}
+	if len(restoredGame.Actions2) != numActions-1 ||
+		len(restoredGame.TurnDurations) != numActions-1 {
+
+		t.Errorf("there are %d actions and %d turn durations instead of %d of each",
+			len(restoredGame.Actions2), len(restoredGame.TurnDurations), numActions-1)
+	}
+
+	// The time that the last action took must be given back to the player who took it
+	for i, p := range restoredGame.Players {
+		if p.Time != expectedTimes[i] {
+			t.Errorf("%s has %v on their clock instead of %v", p.Name, p.Time, expectedTimes[i])
+		}
+	}
+}
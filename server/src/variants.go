@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"path"
+	"sort"
 	"strconv"
-	"strings"
 )
 
 var (
@@ -32,6 +32,28 @@ type VariantJSON struct {
 	SpecialNoClueColors    bool      `json:"specialNoClueColors"`
 	SpecialNoClueRanks     bool      `json:"specialNoClueRanks"`
 	SpecialDeceptive       bool      `json:"specialDeceptive"`
+
+	// Rule modifiers (see the "Variant" struct for a description of each one)
+	AlternatingClues     bool   `json:"alternatingClues"`
+	ColorCluesTouchRanks bool   `json:"colorCluesTouchRanks"`
+	DiscardsGiveHalfClue bool   `json:"discardsGiveHalfClue"`
+	NoClueForPlaying5    bool   `json:"noClueForPlaying5"`
+	StackDirection       string `json:"stackDirection"` // "up" (the default) or "upOrDown"
+	HidePlays            bool   `json:"hidePlays"`
+	HideClueTypes        bool   `json:"hideClueTypes"`
+	HideClueValues       bool   `json:"hideClueValues"`
+	// CardsPerRank is an optional element that maps each rank to the number of copies of it
+	// (JSON object keys are always strings, so they are converted to integers below)
+	CardsPerRank map[string]int `json:"cardsPerRank"`
+}
+
+// defaultCardsPerRank is the number of copies of each rank in a normal suit
+var defaultCardsPerRank = map[int]int{
+	1: 3,
+	2: 2,
+	3: 2,
+	4: 2,
+	5: 1,
 }
 
 func variantsInit() {
@@ -89,14 +111,28 @@ func variantsInit() {
 			}
 		}
 
+		// Validate or derive the number of copies of each rank
+		cardsPerRank := defaultCardsPerRank
+		if variant.CardsPerRank != nil {
+			cardsPerRank = make(map[int]int)
+			for rankString, numCopies := range variant.CardsPerRank {
+				if rank, err := strconv.Atoi(rankString); err != nil {
+					logger.Fatal("The variant of \"" + variant.Name + "\" has an invalid rank of " +
+						"\"" + rankString + "\" in the \"cardsPerRank\" field.")
+					return
+				} else {
+					cardsPerRank[rank] = numCopies
+				}
+			}
+		}
+
 		// Derive the card ranks (the ranks that the cards of each suit will be)
-		// By default, assume ranks 1 through 5
-		variantRanks := []int{1, 2, 3, 4, 5}
-		if strings.HasPrefix(variant.Name, "Up or Down") {
-			// The "Up or Down" variants have START cards
-			// ("startCardRank" is defined in the "variantUpOrDown.go" file)
-			variantRanks = append(variantRanks, StartCardRank)
+		// (e.g. the "Up or Down" variants also have START cards)
+		variantRanks := make([]int, 0)
+		for rank := range cardsPerRank {
+			variantRanks = append(variantRanks, rank)
 		}
+		sort.Ints(variantRanks)
 
 		// Validate or derive the clue colors (the colors available to clue in this variant)
 		clueColors := variant.ClueColors
@@ -159,6 +195,21 @@ func variantsInit() {
 			SpecialDeceptive:       variant.SpecialDeceptive,
 			MaxScore:               len(variantSuits) * 5,
 			// (we assume that there are 5 points per stack)
+			AlternatingClues:     variant.AlternatingClues,
+			ColorCluesTouchRanks: variant.ColorCluesTouchRanks,
+			DiscardsGiveHalfClue: variant.DiscardsGiveHalfClue,
+			NoClueForPlaying5:    variant.NoClueForPlaying5,
+			UpOrDown:             variant.StackDirection == "upOrDown",
+			HidePlays:            variant.HidePlays,
+			HideClueTypes:        variant.HideClueTypes,
+			HideClueValues:       variant.HideClueValues,
+			CardsPerRank:         cardsPerRank,
+		}
+
+		// Validate that the rules of the variant are consistent with each other
+		if err := validateVariant(variants[variant.Name], variant.StackDirection); err != nil {
+			logger.Fatal("The variant of \"" + variant.Name + "\" is invalid: " + err.Error())
+			return
 		}
 
 		// Validate that all of the ID's are unique
@@ -187,89 +238,3 @@ func variantsInit() {
 		}
 	*/
 }
-
-// variantIsCardTouched returns true if a clue will touch a particular suit
-// For example, a yellow clue will not touch a green card in a normal game,
-// but it will the "Dual-Color" variant
-// This mirrors the function "touchesCard()" in "clues.ts"
-func variantIsCardTouched(variantName string, clue Clue, card *Card) bool {
-	variant := variants[variantName]
-	suit := variant.Suits[card.SuitIndex]
-
-	if clue.Type == ClueTypeColor {
-		clueColorName := variant.ClueColors[clue.Value]
-
-		if variant.IsSynesthesia() && !suit.NoClueRanks {
-			// In addition to any other matching, match color based on rank.
-			prismColorIndex := (card.Rank - 1) % len(variant.ClueColors)
-			prismColorName := variant.ClueColors[prismColorIndex]
-			if clueColorName == prismColorName {
-				return true
-			}
-		}
-
-		if variant.ColorCluesTouchNothing {
-			return false
-		}
-
-		if suit.AllClueColors {
-			return true
-		}
-		if suit.NoClueColors {
-			return false
-		}
-
-		if variant.SpecialRank == card.Rank {
-			if variant.SpecialAllClueColors {
-				return true
-			}
-			if variant.SpecialNoClueColors {
-				return false
-			}
-		}
-
-		if suit.Prism {
-			// The color that touches a prism card is contingent upon the card's rank
-			prismColorIndex := (card.Rank - 1) % len(variant.ClueColors)
-			if card.Rank == StartCardRank {
-				// "START" cards count as rank 0, so they are touched by the final color
-				prismColorIndex = len(variant.ClueColors) - 1
-			}
-			prismColorName := variant.ClueColors[prismColorIndex]
-			return clueColorName == prismColorName
-		}
-
-		return stringInSlice(clueColorName, suit.ClueColors)
-	}
-
-	if clue.Type == ClueTypeRank {
-		if variant.RankCluesTouchNothing {
-			return false
-		}
-
-		if variant.Suits[card.SuitIndex].AllClueRanks {
-			return true
-		}
-		if variant.Suits[card.SuitIndex].NoClueRanks {
-			return false
-		}
-
-		if variant.SpecialRank == card.Rank {
-			if variant.SpecialAllClueRanks {
-				return true
-			}
-			if variant.SpecialNoClueRanks {
-				return false
-			}
-			if variant.SpecialDeceptive {
-				// The rank that touches a deceptive card is contingent upon the card's suit
-				deceptiveRank := variant.ClueRanks[card.SuitIndex%len(variant.ClueRanks)]
-				return clue.Value == deceptiveRank
-			}
-		}
-
-		return clue.Value == card.Rank
-	}
-
-	return false
-}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+)
+
+// validateVariant checks that the rules of a variant (as defined in the "variants.json" file)
+// do not contradict each other
+// Since every rule is data-driven, a typo in the JSON would otherwise silently result in a
+// variant that behaves differently than intended
+func validateVariant(variant *Variant, stackDirection string) error {
+	// Validate the stack direction
+	if stackDirection != "" && stackDirection != "up" && stackDirection != "upOrDown" {
+		return errors.New("the stack direction of \"" + stackDirection + "\" is not valid " +
+			"(it must be either \"up\" or \"upOrDown\")")
+	}
+
+	// Validate the ranks
+	for rank := 1; rank <= 5; rank++ {
+		if _, ok := variant.CardsPerRank[rank]; !ok {
+			return errors.New("there are no cards of rank " + strconv.Itoa(rank))
+		}
+	}
+	for rank, numCopies := range variant.CardsPerRank {
+		if (rank < 1 || rank > 5) && rank != StartCardRank {
+			return errors.New("the rank of " + strconv.Itoa(rank) + " is not valid")
+		}
+		if rank == StartCardRank && !variant.UpOrDown {
+			return errors.New("START cards can only be used with a stack direction of \"upOrDown\"")
+		}
+		if numCopies < 1 {
+			return errors.New("rank " + strconv.Itoa(rank) + " must have at least one copy")
+		}
+	}
+	if variant.UpOrDown {
+		for _, suit := range variant.Suits {
+			if suit.Reversed {
+				return errors.New("reversed suits cannot be used with a stack direction of " +
+					"\"upOrDown\"")
+			}
+		}
+	}
+
+	// Validate the clue rules
+	for _, rank := range variant.ClueRanks {
+		if rank < 1 || rank > 5 {
+			return errors.New("the clue rank of " + strconv.Itoa(rank) + " is not valid")
+		}
+	}
+	if variant.ColorCluesTouchRanks {
+		if variant.ColorCluesTouchNothing {
+			return errors.New("color clues cannot both touch ranks and touch nothing")
+		}
+		if len(variant.ClueColors) == 0 {
+			return errors.New("color clues cannot touch ranks if there are no clue colors")
+		}
+	}
+
+	// Validate the special rank
+	hasSpecialRule := variant.SpecialAllClueColors ||
+		variant.SpecialAllClueRanks ||
+		variant.SpecialNoClueColors ||
+		variant.SpecialNoClueRanks ||
+		variant.SpecialDeceptive
+	if variant.SpecialRank == -1 && hasSpecialRule {
+		return errors.New("there are special rank rules but no special rank")
+	}
+	if variant.SpecialRank != -1 {
+		if !hasSpecialRule {
+			return errors.New("there is a special rank but no special rank rules")
+		}
+		if !intInSlice(variant.SpecialRank, variant.Ranks) {
+			return errors.New("the special rank of " + strconv.Itoa(variant.SpecialRank) + " " +
+				"is not one of the ranks of the variant")
+		}
+	}
+	if variant.SpecialAllClueColors && variant.SpecialNoClueColors {
+		return errors.New("the special rank cannot be touched by both all colors and no colors")
+	}
+	if variant.SpecialAllClueRanks && variant.SpecialNoClueRanks {
+		return errors.New("the special rank cannot be touched by both all ranks and no ranks")
+	}
+	if variant.SpecialDeceptive && (variant.SpecialAllClueRanks || variant.SpecialNoClueRanks) {
+		return errors.New("a deceptive special rank cannot have other rank clue rules")
+	}
+
+	// Validate the hidden information rules
+	if variant.HideClueTypes && !variant.HideClueValues {
+		return errors.New("the clue types cannot be hidden if the clue values are shown")
+	}
+
+	// Validate the suits
+	for _, suit := range variant.Suits {
+		if suit.AllClueColors && suit.NoClueColors {
+			return errors.New("the suit of \"" + suit.Name + "\" cannot be touched by both " +
+				"all colors and no colors")
+		}
+		if suit.AllClueRanks && suit.NoClueRanks {
+			return errors.New("the suit of \"" + suit.Name + "\" cannot be touched by both " +
+				"all ranks and no ranks")
+		}
+		if suit.Prism && len(suit.ClueColors) > 0 {
+			return errors.New("the prism suit of \"" + suit.Name + "\" cannot also have " +
+				"explicit clue colors")
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,8 +17,12 @@ import (
 type WebsocketConnectData struct {
 	// Data that will be attached to the session
 	Muted          bool
+	Throttled      bool
+	Bot            bool
 	Friends        map[int]struct{}
 	ReverseFriends map[int]struct{}
+	Blocks         map[int]struct{}
+	ReverseBlocks  map[int]struct{}
 	Hyphenated     bool
 
 	// Other stats
@@ -57,8 +62,12 @@ func websocketConnect(ms *melody.Session) {
 
 	// Attach the new data to the session object
 	s.Muted = data.Muted
+	s.Data.Throttled = data.Throttled
+	s.Bot = data.Bot
 	s.Data.Friends = data.Friends
 	s.Data.ReverseFriends = data.ReverseFriends
+	s.Data.Blocks = data.Blocks
+	s.Data.ReverseBlocks = data.ReverseBlocks
 	s.Data.Hyphenated = data.Hyphenated
 
 	// We only want one computer to connect to one user at a time
@@ -94,6 +103,7 @@ func websocketConnect(ms *melody.Session) {
 	websocketConnectWelcomeMessage(s, data)
 	websocketConnectUserList(s)
 	websocketConnectTableList(ctx, s)
+	sendCorrespondenceTableList(ctx, s)
 	websocketConnectChat(s)
 	websocketConnectHistory(s)
 	if len(data.Friends) > 0 {
@@ -110,6 +120,8 @@ func websocketConnectGetData(ctx context.Context, ms *melody.Session, userID int
 	data := &WebsocketConnectData{ // nolint: exhaustivestruct
 		Friends:        make(map[int]struct{}),
 		ReverseFriends: make(map[int]struct{}),
+		Blocks:         make(map[int]struct{}),
+		ReverseBlocks:  make(map[int]struct{}),
 	}
 
 	// -----------------------------------------
@@ -126,14 +138,32 @@ func websocketConnectGetData(ctx context.Context, ms *melody.Session, userID int
 		ip = v
 	}
 
-	// Check to see if their IP is muted
-	if v, err := models.MutedIPs.Check(ip); err != nil {
-		logger.Error("Failed to check to see if the IP \"" + ip + "\" is muted: " + err.Error())
+	// Check to see if their account or their IP is muted
+	if _, v, err := models.Sanctions.Check(SanctionTypeMute, userID, ip); err != nil {
+		logger.Error("Failed to check to see if user \"" + username + "\" is muted: " +
+			err.Error())
 		return data
 	} else {
 		data.Muted = v
 	}
 
+	// Check to see if their account or their IP is throttled
+	if v, err := models.ThrottledIPs.Check(userID, ip); err != nil {
+		logger.Error("Failed to check to see if user \"" + username + "\" is throttled: " +
+			err.Error())
+		return data
+	} else {
+		data.Throttled = v
+	}
+
+	// Check to see if this is a bot account
+	if v, err := models.Users.IsBot(userID); err != nil {
+		logger.Error("Failed to check to see if user \"" + username + "\" is a bot: " + err.Error())
+		return data
+	} else {
+		data.Bot = v
+	}
+
 	// Get their friends
 	if v, err := models.UserFriends.GetMap(userID); err != nil {
 		logger.Error("Failed to get the friends map for user \"" + username + "\": " + err.Error())
@@ -151,6 +181,23 @@ func websocketConnectGetData(ctx context.Context, ms *melody.Session, userID int
 		data.ReverseFriends = v
 	}
 
+	// Get the users that they have blocked
+	if v, err := models.UserBlocks.GetMap(userID); err != nil {
+		logger.Error("Failed to get the blocks map for user \"" + username + "\": " + err.Error())
+		return data
+	} else {
+		data.Blocks = v
+	}
+
+	// Get the users that have blocked them
+	if v, err := models.UserReverseBlocks.GetMap(userID); err != nil {
+		logger.Error("Failed to get the reverse blocks map for user \"" + username + "\": " +
+			err.Error())
+		return data
+	} else {
+		data.ReverseBlocks = v
+	}
+
 	// Get whether or not they are a member of the Hyphen-ated group
 	if v, err := models.UserSettings.IsHyphenated(userID); err != nil {
 		logger.Error("Failed to get the Hyphen-ated setting for user \"" + username + "\": " +
@@ -204,17 +251,55 @@ func websocketConnectGetData(ctx context.Context, ms *melody.Session, userID int
 	// ----------------------------------------
 
 	// We must acquire the tables lock before calling the below functions
+	// We make a copy of the table IDs since the tables lock is released before the tables are
+	// locked (the "tableJoin()" function acquires the two locks in the opposite order)
 	tables.RLock()
-	defer tables.RUnlock()
-
-	data.PlayingAtTables = tables.GetTablesUserPlaying(userID)
+	playingAtTables := append([]uint64{}, tables.GetTablesUserPlaying(userID)...)
 	if tableID, ok := tables.GetDisconSpectatingTable(userID); ok {
 		data.DisconSpectatingTable = tableID
 	}
+	tables.RUnlock()
+
+	data.PlayingAtTables = websocketConnectSortPlayingAtTables(ctx, userID, playingAtTables)
 
 	return data
 }
 
+// websocketConnectSortPlayingAtTables orders the tables that a user is playing at so that the client
+// automatically puts them back into the one that needs their attention the most
+// (the client reattends the first table in the list)
+// Real-time games come first, followed by correspondence games where it is their turn
+// The tables lock must not be held when calling this function, since it locks each table
+func websocketConnectSortPlayingAtTables(
+	ctx context.Context,
+	userID int,
+	tableIDs []uint64,
+) []uint64 {
+	priorities := make(map[uint64]int)
+	for _, tableID := range tableIDs {
+		priority := 2
+		if t, exists := getTableAndLock(ctx, nil, tableID, true, true); exists {
+			if !t.Options.Correspondence {
+				priority = 0
+			} else if t.IsOngoingCorrespondenceGame() {
+				activePlayer := t.Players[t.Game.State.ActivePlayerIndex]
+				if activePlayer.UserID == userID {
+					priority = 1
+				}
+			}
+			t.Unlock(ctx)
+		}
+		priorities[tableID] = priority
+	}
+
+	// The slice is a copy (see "websocketConnectGetData()"), so it is safe to sort it in place
+	sort.SliceStable(tableIDs, func(i, j int) bool {
+		return priorities[tableIDs[i]] < priorities[tableIDs[j]]
+	})
+
+	return tableIDs
+}
+
 func websocketConnectWelcomeMessage(s *Session, data *WebsocketConnectData) {
 	// Send an initial message that contains information about who they are and
 	// the current state of the server
@@ -223,6 +308,7 @@ func websocketConnectWelcomeMessage(s *Session, data *WebsocketConnectData) {
 		Username      string   `json:"username"`
 		TotalGames    int      `json:"totalGames"`
 		Muted         bool     `json:"muted"`
+		Bot           bool     `json:"bot"`
 		FirstTimeUser bool     `json:"firstTimeUser"`
 		Settings      Settings `json:"settings"`
 		Friends       []string `json:"friends"`
@@ -250,6 +336,7 @@ func websocketConnectWelcomeMessage(s *Session, data *WebsocketConnectData) {
 
 		Muted:         s.Muted,            // Some users are muted (as a resulting of spamming, etc.)
 		FirstTimeUser: data.FirstTimeUser, // First time users get a quick tutorial
+		Bot:           s.Bot,              // Bots use this to confirm that they were recognized
 
 		// The various client settings are stored server-side so that users can seamlessly
 		// transition between computers
@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"net"
 	"strings"
 	"time"
 
@@ -61,9 +60,20 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 		}
 
 		if newRateLimitAllowance < 1 {
-			// They are flooding, so automatically ban them
-			logger.Warn("User \"" + s.Username + "\" triggered rate-limiting; banning them.")
-			ban(s)
+			// They are flooding, so automatically throttle them
+			// (or ban them if they were already throttled)
+			if s.Throttled() {
+				logger.Warn("Throttled user \"" + s.Username + "\" triggered rate-limiting; " +
+					"banning them.")
+				ban(s)
+				return
+			}
+
+			logger.Warn("User \"" + s.Username + "\" triggered rate-limiting; throttling them.")
+			throttle(s, "Flooding the server")
+			s.SetRateLimitAllowance(RateLimitRate)
+			s.Warning("You have been throttled for flooding the server. " +
+				"If you do it again, you will be banned.")
 			return
 		}
 
@@ -96,6 +106,11 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 		commandFunction = v
 	}
 
+	// Throttled users have stricter rate limits for some commands
+	if !s.FakeUser && !throttleCommand(s, command) {
+		return
+	}
+
 	// Unmarshal the JSON (this code is taken from Golem)
 	var d *CommandData
 	if err := json.Unmarshal(jsonData, &d); err != nil {
@@ -110,30 +125,32 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 }
 
 func ban(s *Session) {
-	// Parse the IP address
-	var ip string
-	if v, _, err := net.SplitHostPort(s.ms.Request.RemoteAddr); err != nil {
-		logger.Error("Failed to parse the IP address from \"" + s.ms.Request.RemoteAddr + "\": " +
-			err.Error())
-		return
-	} else {
-		ip = v
-	}
+	// Local variables
+	ip := getSessionIP(s)
 
-	// Check to see if this IP is already banned
-	if banned, err := models.BannedIPs.Check(ip); err != nil {
-		logger.Error("Failed to check to see if the IP \"" + ip + "\" is banned: " + err.Error())
+	// Check to see if this user is already banned
+	if _, banned, err := models.Sanctions.Check(SanctionTypeBan, s.UserID, ip); err != nil {
+		logger.Error("Failed to check to see if user \"" + s.Username + "\" is banned: " +
+			err.Error())
 		return
 	} else if banned {
 		return
 	}
 
-	// Insert a new row in the database for this IP
-	if err := models.BannedIPs.Insert(ip, s.UserID); err != nil {
-		logger.Error("Failed to insert the banned IP row: " + err.Error())
+	// Insert a new permanent ban for both the account and the IP
+	if err := models.Sanctions.Insert(&SanctionRow{ // nolint: exhaustivestruct
+		Type:   SanctionTypeBan,
+		UserID: s.UserID,
+		IP:     ip,
+		Reason: "Flooding the server",
+	}); err != nil {
+		logger.Error("Failed to insert the ban row: " + err.Error())
 		return
 	}
 
+	// Ignore any of their remaining messages in the queue
+	s.SetBanned(true)
+
 	logoutUser(s.UserID)
 	logger.Info("Successfully banned user \"" + s.Username + "\" from IP address \"" + ip + "\".")
 }